@@ -0,0 +1,137 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bxcodec/go-clean-arch/internal/config"
+	mysqlRepo "github.com/bxcodec/go-clean-arch/internal/repository/mysql"
+)
+
+// deps 子命令依赖的配置加载与数据库连接，测试时替换为内存实现
+type deps struct {
+	loadConfig func() (*config.Config, error)
+	openMySQL  func(cfg config.DatabaseConfig) (*sql.DB, error)
+}
+
+func defaultDeps() deps {
+	return deps{
+		loadConfig: config.Load,
+		openMySQL: func(cfg config.DatabaseConfig) (*sql.DB, error) {
+			if err := registerMySQLTLS(cfg); err != nil {
+				return nil, fmt.Errorf("invalid database.tls: %w", err)
+			}
+			return openMySQL(mysqlDSN(cfg), cfg), nil
+		},
+	}
+}
+
+// newRootCmd 构建命令行入口，未指定子命令时等同于 serve
+func newRootCmd(d deps) *cobra.Command {
+	root := &cobra.Command{
+		Use:          "app",
+		Short:        "文章管理服务",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE:         d.runServe,
+	}
+	root.AddCommand(
+		&cobra.Command{
+			Use:   "serve",
+			Short: "启动 HTTP 服务（默认）",
+			Args:  cobra.NoArgs,
+			RunE:  d.runServe,
+		},
+		&cobra.Command{
+			Use:   "migrate",
+			Short: "执行尚未执行的数据库迁移（仅支持 mysql）",
+			Args:  cobra.NoArgs,
+			RunE:  d.runMigrate,
+		},
+		newSeedCmd(d),
+	)
+	return root
+}
+
+// newSeedCmd 在一个事务中写入随机生成的作者与文章，已有文章时不写入，--force 先清空再写入
+func newSeedCmd(d deps) *cobra.Command {
+	var (
+		authors  int
+		articles int
+		force    bool
+	)
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "写入随机生成的示例作者与文章（仅支持 mysql）",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if authors < 1 || articles < 0 {
+				return fmt.Errorf("--authors must be at least 1 and --articles at least 0")
+			}
+			db, err := d.mysql(cmd.Name())
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			authorList, articleList := fakeSeedData(authors, articles)
+			seeded, err := mysqlRepo.Seed(cmd.Context(), db, authorList, articleList, force)
+			if err != nil {
+				return err
+			}
+			if !seeded {
+				fmt.Fprintln(cmd.OutOrStdout(), "数据库中已有文章，未写入示例数据；使用 --force 清空后重新写入")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "已写入 %d 位作者、%d 篇文章\n", len(authorList), len(articleList))
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&authors, "authors", 5, "生成的作者数")
+	cmd.Flags().IntVar(&articles, "articles", 20, "生成的文章数，依次分配给各作者")
+	cmd.Flags().BoolVar(&force, "force", false, "先删除所有作者、文章与标签再写入")
+	return cmd
+}
+
+func (d deps) runServe(*cobra.Command, []string) error {
+	cfg, err := d.loadConfig()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	serve(cfg)
+	return nil
+}
+
+func (d deps) runMigrate(cmd *cobra.Command, _ []string) error {
+	db, err := d.mysql(cmd.Name())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	applied, err := mysqlRepo.Migrate(cmd.Context(), db)
+	for _, version := range applied {
+		fmt.Fprintf(cmd.OutOrStdout(), "已执行迁移 %s\n", version)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "数据库已是最新版本")
+	}
+	return nil
+}
+
+// mysql 加载配置并连接主库，mongo 没有需要迁移的表结构，不支持这些子命令
+func (d deps) mysql(command string) (*sql.DB, error) {
+	cfg, err := d.loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	if cfg.Database.Driver != "mysql" {
+		return nil, fmt.Errorf("%s only supports database.driver mysql, got %s", command, cfg.Database.Driver)
+	}
+	return d.openMySQL(cfg.Database)
+}
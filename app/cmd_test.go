@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/config"
+)
+
+func testDeps(driver string, db *sql.DB) deps {
+	return deps{
+		loadConfig: func() (*config.Config, error) {
+			return &config.Config{Database: config.DatabaseConfig{Driver: driver}}, nil
+		},
+		openMySQL: func(config.DatabaseConfig) (*sql.DB, error) { return db, nil },
+	}
+}
+
+func TestMigrateCommand(t *testing.T) {
+	t.Run("applies-pending", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT version FROM schema_migrations").
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("0001_create_author"))
+		for _, m := range []struct{ version, stmt string }{
+			{"0002_create_article", "CREATE TABLE IF NOT EXISTS article \\("},
+			{"0003_create_article_tag", "CREATE TABLE IF NOT EXISTS article_tag \\("},
+			{"0004_create_audit_log", "CREATE TABLE IF NOT EXISTS audit_log \\("},
+			{"0005_add_article_view_count", "ALTER TABLE article ADD COLUMN view_count"},
+			{"0006_scope_article_unique_keys", "ALTER TABLE article\\s+DROP INDEX uniq_article_title"},
+		} {
+			mock.ExpectExec(m.stmt).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec("INSERT schema_migrations SET version=\\? , applied_at=\\?").WithArgs(m.version, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		mock.ExpectClose()
+
+		cmd := newRootCmd(testDeps("mysql", db))
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"migrate"})
+		require.NoError(t, cmd.Execute())
+
+		assert.Equal(t, "已执行迁移 0002_create_article\n已执行迁移 0003_create_article_tag\n已执行迁移 0004_create_audit_log\n已执行迁移 0005_add_article_view_count\n已执行迁移 0006_scope_article_unique_keys\n", out.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("up-to-date", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT version FROM schema_migrations").WillReturnRows(sqlmock.NewRows([]string{"version"}).
+			AddRow("0001_create_author").AddRow("0002_create_article").AddRow("0003_create_article_tag").AddRow("0004_create_audit_log").
+			AddRow("0005_add_article_view_count").AddRow("0006_scope_article_unique_keys"))
+		mock.ExpectClose()
+
+		cmd := newRootCmd(testDeps("mysql", db))
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"migrate"})
+		require.NoError(t, cmd.Execute())
+
+		assert.Equal(t, "数据库已是最新版本\n", out.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("mongo-unsupported", func(t *testing.T) {
+		cmd := newRootCmd(testDeps("mongo", nil))
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"migrate"})
+
+		assert.EqualError(t, cmd.Execute(), "migrate only supports database.driver mysql, got mongo")
+	})
+}
+
+func TestSeedCommand(t *testing.T) {
+	t.Run("empty-database", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM article").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		for i := 1; i <= 2; i++ {
+			mock.ExpectExec("INSERT author SET name=\\?").WillReturnResult(sqlmock.NewResult(int64(i), 1))
+		}
+		prep := mock.ExpectPrepare("INSERT  article SET title=\\?")
+		// the articles are spread over the authors in turn
+		for i, authorID := range []int64{1, 2, 1} {
+			prep.ExpectExec().WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), authorID,
+				sqlmock.AnyArg(), sqlmock.AnyArg(), nil).WillReturnResult(sqlmock.NewResult(int64(i+1), 1))
+		}
+		mock.ExpectCommit()
+		mock.ExpectClose()
+
+		cmd := newRootCmd(testDeps("mysql", db))
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"seed", "--authors", "2", "--articles", "3"})
+		require.NoError(t, cmd.Execute())
+
+		assert.Equal(t, "已写入 2 位作者、3 篇文章\n", out.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("already-seeded", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM article").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+		mock.ExpectRollback()
+		mock.ExpectClose()
+
+		cmd := newRootCmd(testDeps("mysql", db))
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"seed"})
+		require.NoError(t, cmd.Execute())
+
+		assert.Contains(t, out.String(), "未写入示例数据")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("force", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		for _, table := range []string{"article_tag", "article", "author"} {
+			mock.ExpectExec("DELETE FROM " + table + "$").WillReturnResult(sqlmock.NewResult(0, 3))
+		}
+		mock.ExpectExec("INSERT author SET name=\\?").WillReturnResult(sqlmock.NewResult(7, 1))
+		prep := mock.ExpectPrepare("INSERT  article SET title=\\?")
+		prep.ExpectExec().WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		mock.ExpectClose()
+
+		cmd := newRootCmd(testDeps("mysql", db))
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"seed", "--authors", "1", "--articles", "1", "--force"})
+		require.NoError(t, cmd.Execute())
+
+		assert.Equal(t, "已写入 1 位作者、1 篇文章\n", out.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFakeSeedData(t *testing.T) {
+	authors, articles := fakeSeedData(3, 8)
+
+	assert.Len(t, authors, 3)
+	require.Len(t, articles, 8)
+	titles := map[string]bool{}
+	for i, ar := range articles {
+		assert.NotEmpty(t, ar.Slug)
+		assert.NotEmpty(t, ar.Content)
+		assert.False(t, titles[ar.Title], "duplicated title %q", ar.Title)
+		titles[ar.Title] = true
+		if i > 0 {
+			assert.True(t, ar.CreatedAt.After(articles[i-1].CreatedAt))
+		}
+	}
+	assert.Equal(t, domain.StatusDraft, articles[3].Status)
+	assert.Equal(t, domain.StatusPublished, articles[4].Status)
+}
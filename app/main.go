@@ -1,117 +1,334 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/url"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/go-sql-driver/mysql"
-	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"github.com/bxcodec/go-clean-arch/internal/repository/breaker"
+	mongoRepo "github.com/bxcodec/go-clean-arch/internal/repository/mongo"
 	mysqlRepo "github.com/bxcodec/go-clean-arch/internal/repository/mysql"
+	"github.com/bxcodec/go-clean-arch/internal/repository/slowquery"
 
 	"github.com/bxcodec/go-clean-arch/article"
+	"github.com/bxcodec/go-clean-arch/internal/config"
+	"github.com/bxcodec/go-clean-arch/internal/event"
 	"github.com/bxcodec/go-clean-arch/internal/handler"
 	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/buildinfo"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/health"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/logger"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/tracing"
+	"github.com/bxcodec/go-clean-arch/internal/server"
 	log "github.com/lingdongomg/g-lib/logger"
 )
 
-const (
-	defaultTimeout = 30
-	defaultAddress = ":9090"
-)
+// maxConnectBackoff 启动时重试连接数据库的最长等待时间
+const maxConnectBackoff = 30 * time.Second
 
-func init() {
-	// 设置配置文件名和路径
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath("../configs")
-	viper.AddConfigPath("./configs")
-	viper.AddConfigPath(".")
+// logConfigPath 日志配置文件路径，文件名不能随意更改
+const logConfigPath = "./configs/log.conf.yaml"
 
-	// 读取配置文件
-	if err := viper.ReadInConfig(); err != nil {
-		// 在日志系统初始化之前，使用标准库
-		fmt.Printf("Error reading config file: %v\n", err)
-		panic(err)
+func main() {
+	// 未指定子命令时执行 serve；migrate、seed 等运维任务复用同一份配置与数据库连接
+	if err := newRootCmd(defaultDeps()).Execute(); err != nil {
+		os.Exit(1)
 	}
 }
 
-func main() {
+// serve 启动 HTTP 服务，直到收到退出信号
+func serve(cfg *config.Config) {
 	// 示例1：没有进行任何初始化，直接引用包名进行打印，打印输出到当前default.log文件中
 	log.Info("应用启动中...")
+	build := buildinfo.Get()
+	log.Infof("版本 %s，commit %s，构建时间 %s", build.Version, build.Commit, build.BuildTime)
 
 	// 示例2：通过文件进行配置实例化，实例化后可以使用返回值logger打印，也可以直接使用包名进行打印（则可以忽略返回值logger）
 	// 规范建议是统一使用包名log.XXX进行日志输出，另外任何框架都必须包括如下的日志配置文件，配置文件名不能随意更改
-	_, err := log.NewZapLogger("./configs/log.conf.yaml")
+	err := initLogConfig(func(path string) error {
+		_, err := log.NewZapLogger(path)
+		return err
+	}, logConfigPath, cfg.Log.RequireConfig)
 	if err != nil {
-		// 如果配置文件不存在，使用默认配置
-		log.Warn("日志配置文件不存在，使用默认配置:", err)
+		log.Fatal("日志配置文件加载失败", err)
+	}
+
+	// 构建统一的应用 logger，handler 与中间件均通过它输出日志
+	logLevel, err := logger.ParseLevel(cfg.Log.Level)
+	if err != nil {
+		log.Warn("日志级别配置无效，使用 info:", err)
 	}
+	appLogger := logger.New(logLevel, nil)
 
 	log.Info("日志系统初始化完成")
 
 	// 设置Gin模式
-	if !viper.GetBool("debug") {
+	if !cfg.Debug {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	// 准备数据库连接
-	dbHost := viper.GetString("database.host")
-	dbPort := viper.GetString("database.port")
-	dbUser := viper.GetString("database.user")
-	dbPass := viper.GetString("database.password")
-	dbName := viper.GetString("database.name")
-	connection := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", dbUser, dbPass, dbHost, dbPort, dbName)
-	val := url.Values{}
-	val.Add("parseTime", "1")
-	val.Add("loc", "Asia/Jakarta")
-	dsn := fmt.Sprintf("%s?%s", connection, val.Encode())
-	dbConn, err := sql.Open(`mysql`, dsn)
-	if err != nil {
-		log.Fatal("failed to open connection to database", err)
-	}
-	err = dbConn.Ping()
-	if err != nil {
-		log.Fatal("failed to ping database", err)
+	// 准备数据库连接与Repository
+	var (
+		articleRepo article.ArticleRepository
+		authorRepo  article.AuthorRepository
+		auditLogger article.AuditLogger
+		adminOpts   []handler.AdminOption
+	)
+	// 就绪检查：各依赖在此注册，由 GET /readyz 并发执行
+	checker := health.New(cfg.Health.Timeout)
+	switch cfg.Database.Driver {
+	case "mysql":
+		if err := registerMySQLTLS(cfg.Database); err != nil {
+			log.Fatal("invalid database.tls: ", err)
+		}
+		dbConn := openMySQL(mysqlDSN(cfg.Database), cfg.Database)
+		defer func() {
+			err := dbConn.Close()
+			if err != nil {
+				log.Fatal("got error when closing the DB connection", err)
+			}
+		}()
+		// 列表与详情查询走只读副本，写操作始终走主库
+		var replicaConn *sql.DB
+		if cfg.Database.Replica != "" {
+			replicaDSN, err := mysqlReplicaDSN(cfg.Database)
+			if err != nil {
+				log.Fatal("invalid database.replica: ", err)
+			}
+			replicaConn = openMySQL(replicaDSN, cfg.Database)
+			defer func() {
+				err := replicaConn.Close()
+				if err != nil {
+					log.Fatal("got error when closing the replica connection", err)
+				}
+			}()
+		}
+		checker.Register(health.Check{Name: "mysql", Func: dbConn.PingContext})
+		if replicaConn != nil {
+			checker.Register(health.Check{Name: "mysql_replica", Func: replicaConn.PingContext})
+		}
+		authorRepo = mysqlRepo.NewAuthorRepository(dbConn)
+		articleRepo = mysqlRepo.NewArticleRepositoryWithReplica(dbConn, replicaConn)
+		auditLogger = mysqlRepo.NewAuditRepository(dbConn)
+		adminOpts = append(adminOpts, handler.WithDBStats(dbConn.Stats))
+	case "mongo":
+		client := openMongo(cfg.Database)
+		defer func() {
+			err := client.Disconnect(context.Background())
+			if err != nil {
+				log.Fatal("got error when closing the DB connection", err)
+			}
+		}()
+		checker.Register(health.Check{Name: "mongo", Func: func(ctx context.Context) error {
+			return client.Ping(ctx, nil)
+		}})
+		db := client.Database(cfg.Database.Name)
+		authorRepo = mongoRepo.NewAuthorRepository(db)
+		articleRepo = mongoRepo.NewArticleRepository(db)
+		auditLogger = mongoRepo.NewAuditRepository(db)
 	}
 
 	log.Info("数据库连接成功")
 
+	// 慢查询日志：记录耗时超过阈值的数据库调用，放在熔断之内以只统计真正执行的查询
+	if threshold := cfg.Database.SlowQueryThreshold; threshold > 0 {
+		timer := slowquery.New(threshold, appLogger)
+		articleRepo = slowquery.NewArticleRepository(articleRepo, timer)
+		authorRepo = slowquery.NewAuthorRepository(authorRepo, timer)
+	}
+
+	// 熔断：数据库连续失败后快速失败，冷却期后放行探测请求
+	if cfg.Breaker.Enabled {
+		b := breaker.New(breaker.Config{
+			MaxFailures: cfg.Breaker.MaxFailures,
+			Cooldown:    cfg.Breaker.Cooldown,
+		})
+		articleRepo = breaker.NewArticleRepository(articleRepo, b)
+		authorRepo = breaker.NewAuthorRepository(authorRepo, b)
+	}
+
+	// 初始化链路追踪，OTLP 导出地址等通过 OTEL_EXPORTER_OTLP_* 环境变量配置
+	shutdownTracing, err := tracing.Setup(context.Background())
+	if err != nil {
+		log.Fatal("failed to set up tracing", err)
+	}
 	defer func() {
-		err := dbConn.Close()
-		if err != nil {
-			log.Fatal("got error when closing the DB connection", err)
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Error("failed to flush traces", err)
 		}
 	}()
 
 	// 准备Gin引擎
 	r := gin.New()
+	srv := server.New(cfg.Server.Address, r, cfg.Server.DrainTimeout)
+
+	// 仅信任配置的代理转发的 X-Forwarded-For，未配置时不信任任何代理
+	if err := server.TrustProxies(r, cfg.Server.TrustedProxies); err != nil {
+		log.Fatal("invalid server.trusted_proxies: ", err)
+	}
 
 	// 注册中间件
-	r.Use(gin.Logger())
-	r.Use(middleware.ErrorHandler())
-	r.Use(middleware.ErrorMiddleware())
-	r.Use(middleware.CORS())
+	// 路径规范化需最先注册：/api//v1/articles、/api/v1/articles/ 在内部按 /api/v1/articles 重新路由
+	r.Use(middleware.CleanPath(r))
+	r.Use(middleware.RequestID())
+	// 根据 Accept-Language 返回对应语言的错误消息，默认中文
+	r.Use(middleware.Locale())
+	r.Use(middleware.Tracing())
+	// TLS 在代理处终结时，将经 http 转发的请求重定向到 https 或直接拒绝（仅 release 模式）
+	if mode := cfg.Server.RequireHTTPS; mode == "redirect" || mode == "reject" {
+		httpsConfig := middleware.DefaultHTTPSConfig
+		httpsConfig.Reject = mode == "reject"
+		r.Use(middleware.RequireHTTPSWithConfig(httpsConfig))
+	}
+	r.Use(middleware.ServerTiming())
+	accessLogConfig := middleware.DefaultAccessLogConfig
+	accessLogConfig.LogRequestBody = cfg.Log.RequestBody
+	accessLogConfig.MaxBodySize = cfg.Log.RequestBodyMax
+	accessLogConfig.RedactFields = cfg.Log.RedactFields
+	accessLogConfig.SampleRate = cfg.Log.SampleRate
+	r.Use(middleware.AccessLogWithConfig(appLogger, accessLogConfig))
+	r.Use(middleware.ErrorHandlerWithLogger(appLogger))
+	r.Use(middleware.ErrorMiddlewareWithLogger(appLogger))
+	// 限制并发处理的请求数，避免压垮数据库连接池
+	r.Use(middleware.MaxInFlight(cfg.Server.MaxInFlight))
+	// 关闭期间新到达的请求直接返回 503，由负载均衡重试到其他实例
+	r.Use(middleware.RejectWhileDraining(srv.Draining))
+	corsConfig := middleware.DefaultCORSConfig
+	corsConfig.MaxAge = cfg.CORS.MaxAge
+	if len(cfg.API.Versions) > 0 {
+		corsConfig.AllowHeaders = append(slices.Clone(corsConfig.AllowHeaders), middleware.APIVersionHeader)
+		corsConfig.ExposeHeaders = append(slices.Clone(corsConfig.ExposeHeaders), middleware.APIVersionHeader)
+	}
+	r.Use(middleware.CORSWithConfig(corsConfig))
+
+	// 通过 X-API-Version 选择 API 版本，未携带时使用默认版本
+	if len(cfg.API.Versions) > 0 {
+		r.Use(middleware.RequireAPIVersionWithConfig(middleware.APIVersionConfig{
+			Supported: cfg.API.Versions,
+			Default:   cfg.API.DefaultVersion,
+		}))
+	}
+
+	// 请求携带已弃用的查询参数时通过 Deprecation、Sunset、Warning 头提示客户端迁移
+	if len(cfg.API.DeprecatedParams) > 0 {
+		params := make([]middleware.DeprecatedParam, 0, len(cfg.API.DeprecatedParams))
+		for _, p := range cfg.API.DeprecatedParams {
+			params = append(params, middleware.DeprecatedParam{Name: p.Name, Replacement: p.Replacement, Sunset: p.Sunset})
+		}
+		r.Use(middleware.DeprecatedParams(params...))
+	}
 
-	// 设置超时中间件
-	timeout := viper.GetInt("context.timeout")
-	if timeout == 0 {
-		log.Warn("timeout not configured, using default timeout")
-		timeout = defaultTimeout
+	// 从 Bearer JWT 中识别操作者供审计日志使用，令牌缺失或无效时按匿名处理
+	if cfg.Auth.JWTSecret != "" {
+		r.Use(middleware.JWTActor([]byte(cfg.Auth.JWTSecret)))
 	}
-	timeoutContext := time.Duration(timeout) * time.Second
-	r.Use(middleware.SetRequestContextWithTimeout(timeoutContext))
 
-	// 准备Repository
-	authorRepo := mysqlRepo.NewAuthorRepository(dbConn)
-	articleRepo := mysqlRepo.NewArticleRepository(dbConn)
+	// 路径存在但方法不支持时返回 405 并列出 Allow，而不是 404
+	middleware.EnableMethodNotAllowed(r)
+	// 未知路径同样返回 JSON 格式的 404
+	r.NoRoute(middleware.NotFound())
+
+	// 成功响应统一包装，便于网关按固定结构解析
+	if cfg.API.Envelope {
+		r.Use(handler.Envelope())
+	}
+
+	// 设置超时中间件，处理函数未及时响应取消时也会按时返回 504
+	r.Use(middleware.TimeoutWithResponse(middleware.TimeoutConfig{
+		Default: cfg.Context.Timeout,
+		Max:     cfg.Context.MaxTimeout,
+		Secret:  cfg.Context.InternalSecret,
+	}))
 
 	// 构建Service层
-	svc := article.NewService(articleRepo, authorRepo)
-	handler.NewArticleHandler(r, svc)
+	cursorSecret := cfg.Cursor.Secret
+	if cursorSecret == "" {
+		log.Warn("cursor secret not configured, cursors will not survive a restart")
+	}
+	serviceOpts := []article.Option{
+		article.WithCursorSecret([]byte(cursorSecret)),
+		// 标题唯一时创建前先检查并返回 409；数据库不对标题建唯一索引，以便关闭该选项并允许重建已删除文章的标题
+		article.WithUniqueTitles(cfg.Content.UniqueTitles),
+	}
+	if webhookURL := cfg.Events.WebhookURL; webhookURL != "" {
+		// 事件在后台投递，订阅方缓慢或不可用时不阻塞写请求；退出时在超时内投递完队列中的事件
+		publisher := event.NewAsyncPublisher(event.NewWebhookPublisher(webhookURL, nil), cfg.Events.QueueSize, cfg.Events.Timeout)
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), cfg.Events.Timeout)
+			defer cancel()
+			if err := publisher.Close(ctx); err != nil {
+				log.Warn("未投递完的事件已丢弃:", err)
+			}
+		}()
+		serviceOpts = append(serviceOpts, article.WithEventPublisher(publisher))
+	}
+	// 存储前清理文章内容中的 HTML，防止存储型 XSS
+	if cfg.Content.Sanitize {
+		sanitizer, err := article.NewSanitizer(cfg.Content.Policy)
+		if err != nil {
+			log.Fatal("invalid content.policy: ", err)
+		}
+		serviceOpts = append(serviceOpts, article.WithSanitizer(sanitizer))
+	}
+	// 多写入节点部署时由应用生成 id，不依赖数据库自增
+	if cfg.ID.Generator == "snowflake" {
+		idGen, err := article.NewSnowflake(cfg.ID.Node)
+		if err != nil {
+			log.Fatal("invalid id.node: ", err)
+		}
+		serviceOpts = append(serviceOpts, article.WithIDGenerator(idGen))
+	}
+	articleSvc := article.NewService(articleRepo, authorRepo, serviceOpts...)
+	var svc handler.ArticleService = articleSvc
+	// 列表页与单篇文章短时缓存，任何写操作都会清空
+	if cfg.Cache.Enabled {
+		svc = article.NewCachedService(articleSvc, cfg.Cache.ListTTL)
+	}
+	// 审计日志：记录每次创建、更新、删除的操作者及变更前后的内容
+	if cfg.Audit.Enabled {
+		svc = article.NewAuditedService(svc, auditLogger)
+	}
+	// 功能开关，可通过 /admin/features 在运行时调整
+	features := middleware.NewFeatureFlags(cfg.Features, cfg.API.DisabledFeatureStatus)
+	handlerOpts := []handler.Option{
+		handler.WithLogger(appLogger),
+		handler.WithPrefix(cfg.Server.BasePath),
+		handler.WithMaxPageSize(cfg.Pagination.MaxSize),
+		handler.WithDefaultPageSize(cfg.Pagination.DefaultSize),
+		handler.WithImportMaxBytes(cfg.Import.MaxBytes),
+		handler.WithMaxMetadataBytes(cfg.Content.MaxMetadataBytes),
+		handler.WithCacheMaxAge(cfg.Cache.MaxAge),
+		handler.WithExcerptLength(cfg.API.ExcerptLength),
+		handler.WithTimeFormat(cfg.API.TimeFormat),
+		handler.WithFeatureFlags(features),
+	}
+	// 使用 JSON Schema 代替结构体标签校验请求体
+	if cfg.Validation.JSONSchema {
+		handlerOpts = append(handlerOpts, handler.WithJSONSchema())
+	}
+	// 超过 2^53 的 id 在 JS 中会丢失精度，按字符串输出
+	if cfg.API.StringIDs {
+		handlerOpts = append(handlerOpts, handler.WithStringIDs())
+	}
+	handler.NewArticleHandler(r, svc, handlerOpts...)
+
+	// 运维接口（运行时调整日志级别等），未配置 admin.token 时不开放
+	if cfg.Admin.Token != "" {
+		adminOpts = append(adminOpts, handler.WithArticleStats(svc), handler.WithArticleLookup(svc, handlerOpts...), handler.WithFeatureToggles(features))
+		handler.NewAdminHandler(r, appLogger, cfg.Admin.Token, adminOpts...)
+	}
 
 	// 健康检查端点
 	r.GET("/health", func(c *gin.Context) {
@@ -121,14 +338,102 @@ func main() {
 		})
 	})
 
+	// 就绪检查端点，必需的依赖不可用时返回 503
+	handler.NewHealthHandler(r, checker, appLogger)
+
+	// 版本信息端点，便于确认当前运行的构建
+	handler.NewVersionHandler(r, build)
+
 	// 启动服务器
-	address := viper.GetString("server.address")
-	if address == "" {
-		address = defaultAddress
+	// 收到退出信号后立即停止接收新连接，并在 drain_timeout 内等待处理中的请求完成
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Infof("服务器启动在端口 %s", cfg.Server.Address)
+	if err := srv.Run(ctx); err != nil {
+		log.Error("服务器运行失败:", err)
+	}
+	log.Info("服务器已关闭")
+}
+
+// initLogConfig 通过 load 加载日志配置文件。加载失败时，require 为 true 则返回错误由调用方终止启动，
+// 否则记录警告并使用默认配置继续运行
+func initLogConfig(load func(path string) error, path string, require bool) error {
+	if err := load(path); err != nil {
+		if require {
+			return fmt.Errorf("load log config %s: %w", path, err)
+		}
+		log.Warn("日志配置文件不存在，使用默认配置:", err)
+	}
+	return nil
+}
+
+// pingWithRetry 最多尝试 attempts 次 ping，失败后按指数退避等待，便于容器先于数据库启动。
+// 每次 ping 最多等待 timeout（为 0 时不限制），避免数据库地址不可达但不拒绝连接时启动一直挂起
+func pingWithRetry(ping func(ctx context.Context) error, attempts int, backoff, timeout time.Duration) error {
+	attempts = max(attempts, 1)
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = pingWithTimeout(ping, timeout); err == nil {
+			return nil
+		}
+		if attempt == attempts {
+			break
+		}
+		log.Warnf("数据库连接失败（第 %d/%d 次），%s 后重试: %v", attempt, attempts, backoff, err)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, maxConnectBackoff)
+	}
+	return fmt.Errorf("database unreachable after %d attempts: %w", attempts, err)
+}
+
+// pingWithTimeout 执行一次 ping，超时的错误中注明等待时长
+func pingWithTimeout(ping func(ctx context.Context) error, timeout time.Duration) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	err := ping(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("no response within %s: %w", timeout, err)
+	}
+	return err
+}
+
+// mysqlDSN 由 host、port 等配置拼出主库 DSN
+func mysqlDSN(cfg config.DatabaseConfig) string {
+	connection := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name)
+	val := url.Values{}
+	val.Add("parseTime", "1")
+	val.Add("loc", "Asia/Jakarta")
+	if tlsParam := mysqlTLSParam(cfg.TLS.Mode); tlsParam != "" {
+		val.Add("tls", tlsParam)
 	}
+	return fmt.Sprintf("%s?%s", connection, val.Encode())
+}
 
-	log.Infof("服务器启动在端口 %s", address)
-	if err := r.Run(address); err != nil {
-		log.Error("服务器启动失败:", err)
+func openMySQL(dsn string, cfg config.DatabaseConfig) *sql.DB {
+	dbConn, err := sql.Open(`mysql`, dsn)
+	if err != nil {
+		log.Fatal("failed to open connection to database", err)
+	}
+	err = pingWithRetry(dbConn.PingContext, cfg.ConnectAttempts, cfg.ConnectBackoff, cfg.ConnectTimeout)
+	if err != nil {
+		log.Fatal("failed to ping database", err)
+	}
+	return dbConn
+}
+
+func openMongo(cfg config.DatabaseConfig) *mongo.Client {
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(cfg.URI))
+	if err != nil {
+		log.Fatal("failed to open connection to database", err)
+	}
+	err = pingWithRetry(func(ctx context.Context) error { return client.Ping(ctx, nil) }, cfg.ConnectAttempts, cfg.ConnectBackoff, cfg.ConnectTimeout)
+	if err != nil {
+		log.Fatal("failed to ping database", err)
 	}
+	return client
 }
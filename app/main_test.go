@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPingWithRetry(t *testing.T) {
+	t.Run("fails-twice-then-succeeds", func(t *testing.T) {
+		calls := 0
+		ping := func(context.Context) error {
+			calls++
+			if calls <= 2 {
+				return errors.New("connection refused")
+			}
+			return nil
+		}
+
+		err := pingWithRetry(ping, 5, time.Millisecond, time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("gives-up", func(t *testing.T) {
+		calls := 0
+		refused := errors.New("connection refused")
+		ping := func(context.Context) error {
+			calls++
+			return refused
+		}
+
+		err := pingWithRetry(ping, 3, time.Millisecond, time.Second)
+		assert.ErrorIs(t, err, refused)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("times-out", func(t *testing.T) {
+		calls := 0
+		// an unreachable host that drops the packets instead of refusing the connection
+		ping := func(ctx context.Context) error {
+			calls++
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		start := time.Now()
+		err := pingWithRetry(ping, 2, time.Millisecond, 20*time.Millisecond)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, "database unreachable after 2 attempts: no response within 20ms")
+		assert.Equal(t, 2, calls)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
+
+func TestInitLogConfig(t *testing.T) {
+	missing := errors.New("open ./configs/log.conf.yaml: no such file or directory")
+	load := func(err error) func(string) error {
+		return func(path string) error {
+			assert.Equal(t, logConfigPath, path)
+			return err
+		}
+	}
+
+	t.Run("lenient-continues", func(t *testing.T) {
+		assert.NoError(t, initLogConfig(load(missing), logConfigPath, false))
+	})
+
+	t.Run("required-fails", func(t *testing.T) {
+		err := initLogConfig(load(missing), logConfigPath, true)
+		assert.ErrorIs(t, err, missing)
+		assert.ErrorContains(t, err, "load log config ./configs/log.conf.yaml")
+	})
+
+	t.Run("present", func(t *testing.T) {
+		assert.NoError(t, initLogConfig(load(nil), logConfigPath, false))
+		assert.NoError(t, initLogConfig(load(nil), logConfigPath, true))
+	})
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/bxcodec/go-clean-arch/internal/config"
+)
+
+const (
+	// mysqlTLSName 注册到 mysql 驱动的自定义 TLS 配置名，主库 DSN 中以 tls=custom 引用
+	mysqlTLSName = "custom"
+	// mysqlReplicaTLSName 只读副本使用的 TLS 配置名，与主库分开注册以便按副本主机名校验证书
+	mysqlReplicaTLSName = "custom-replica"
+)
+
+// mysqlTLSParam 返回 DSN 的 tls 参数，disabled 时为空；preferred 使用驱动内置配置（服务端支持时加密，不校验证书）
+func mysqlTLSParam(mode string) string {
+	switch mode {
+	case "", "disabled":
+		return ""
+	case "preferred":
+		return "preferred"
+	default:
+		return mysqlTLSName
+	}
+}
+
+// registerMySQLTLS 在打开连接前向驱动注册 database.tls 对应的 TLS 配置，配置了只读副本时一并注册副本的配置
+func registerMySQLTLS(cfg config.DatabaseConfig) error {
+	conf, err := mysqlTLSConfig(cfg, cfg.Host)
+	if err != nil || conf == nil {
+		return err
+	}
+	if err := mysql.RegisterTLSConfig(mysqlTLSName, conf); err != nil {
+		return err
+	}
+	if cfg.Replica == "" {
+		return nil
+	}
+	host, err := mysqlReplicaHost(cfg.Replica)
+	if err != nil {
+		return err
+	}
+	conf, err = mysqlTLSConfig(cfg, host)
+	if err != nil {
+		return err
+	}
+	return mysql.RegisterTLSConfig(mysqlReplicaTLSName, conf)
+}
+
+// mysqlReplicaDSN 为只读副本的 DSN 补上 database.tls 对应的 tls 参数，DSN 中已显式指定 tls 时保持不变
+func mysqlReplicaDSN(cfg config.DatabaseConfig) (string, error) {
+	dsn, err := mysql.ParseDSN(cfg.Replica)
+	if err != nil {
+		return "", fmt.Errorf("parse replica dsn: %w", err)
+	}
+	if dsn.TLSConfig != "" {
+		return cfg.Replica, nil
+	}
+	switch param := mysqlTLSParam(cfg.TLS.Mode); param {
+	case "":
+		return cfg.Replica, nil
+	case mysqlTLSName:
+		dsn.TLSConfig = mysqlReplicaTLSName
+	default:
+		dsn.TLSConfig = param
+	}
+	return dsn.FormatDSN(), nil
+}
+
+// mysqlReplicaHost 返回只读副本 DSN 中的主机名，用于 verify-identity 校验证书
+func mysqlReplicaHost(replica string) (string, error) {
+	dsn, err := mysql.ParseDSN(replica)
+	if err != nil {
+		return "", fmt.Errorf("parse replica dsn: %w", err)
+	}
+	host, _, err := net.SplitHostPort(dsn.Addr)
+	if err != nil {
+		// 未带端口的地址整体即为主机名
+		return dsn.Addr, nil
+	}
+	return host, nil
+}
+
+// mysqlTLSConfig 按 database.tls 为连接到 host 的实例构造 tls.Config，不需要自定义配置的模式返回 nil：
+//   - required 只加密，不校验服务端证书
+//   - verify-ca 校验证书链但不校验主机名，适用于证书名与连接地址不一致的托管实例
+//   - verify-identity 同时校验证书链与主机名
+func mysqlTLSConfig(cfg config.DatabaseConfig, host string) (*tls.Config, error) {
+	if mysqlTLSParam(cfg.TLS.Mode) != mysqlTLSName {
+		return nil, nil
+	}
+
+	conf := &tls.Config{}
+	if cfg.TLS.Cert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.TLS.Cert, cfg.TLS.Key)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+	// 未配置 CA 时 roots 为 nil，校验使用系统根证书
+	var roots *x509.CertPool
+	if cfg.TLS.CA != "" {
+		pem, err := os.ReadFile(cfg.TLS.CA)
+		if err != nil {
+			return nil, fmt.Errorf("read ca: %w", err)
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", cfg.TLS.CA)
+		}
+	}
+
+	switch cfg.TLS.Mode {
+	case "required":
+		conf.InsecureSkipVerify = true
+	case "verify-ca":
+		// 跳过标准库的校验（其中包含主机名），改为只校验证书链
+		conf.InsecureSkipVerify = true
+		conf.VerifyPeerCertificate = verifyCertChain(roots)
+	case "verify-identity":
+		conf.RootCAs = roots
+		conf.ServerName = host
+	}
+	return conf, nil
+}
+
+// verifyCertChain 校验服务端证书由 roots 签发，不检查主机名
+func verifyCertChain(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("server sent no certificate")
+		}
+		certs := make([]*x509.Certificate, 0, len(rawCerts))
+		for _, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return err
+			}
+			certs = append(certs, cert)
+		}
+		opts := x509.VerifyOptions{Roots: roots, Intermediates: x509.NewCertPool()}
+		for _, cert := range certs[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		_, err := certs[0].Verify(opts)
+		return err
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/internal/config"
+)
+
+// writeCert creates a certificate signed by parent, self-signed when parent is nil, and writes it
+// and its key as PEM files into dir
+func writeCert(t *testing.T, dir, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  parent == nil,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+"-key.pem"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func TestMySQLTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := writeCert(t, dir, "ca", nil, nil)
+	server, _ := writeCert(t, dir, "server", ca, caKey)
+	writeCert(t, dir, "client", ca, caKey)
+	other, _ := writeCert(t, dir, "other", nil, nil)
+
+	base := config.DatabaseConfig{Host: "db.example.com", Port: "3306", User: "user", Password: "password", Name: "article"}
+	caPath := filepath.Join(dir, "ca.pem")
+
+	tests := []struct {
+		name     string
+		tls      config.TLSConfig
+		param    string
+		noConfig bool
+		check    func(t *testing.T, cfg config.DatabaseConfig)
+	}{
+		{name: "unset", noConfig: true},
+		{name: "disabled", tls: config.TLSConfig{Mode: "disabled"}, noConfig: true},
+		{name: "preferred", tls: config.TLSConfig{Mode: "preferred"}, param: "preferred", noConfig: true},
+		{
+			name:  "required",
+			tls:   config.TLSConfig{Mode: "required"},
+			param: mysqlTLSName,
+			check: func(t *testing.T, cfg config.DatabaseConfig) {
+				conf, err := mysqlTLSConfig(cfg, cfg.Host)
+				require.NoError(t, err)
+				assert.True(t, conf.InsecureSkipVerify)
+				assert.Nil(t, conf.VerifyPeerCertificate)
+				assert.Empty(t, conf.Certificates)
+			},
+		},
+		{
+			name:  "verify-ca",
+			tls:   config.TLSConfig{Mode: "verify-ca", CA: caPath},
+			param: mysqlTLSName,
+			check: func(t *testing.T, cfg config.DatabaseConfig) {
+				conf, err := mysqlTLSConfig(cfg, cfg.Host)
+				require.NoError(t, err)
+				assert.True(t, conf.InsecureSkipVerify)
+				require.NotNil(t, conf.VerifyPeerCertificate)
+				assert.NoError(t, conf.VerifyPeerCertificate([][]byte{server.Raw}, nil))
+				assert.Error(t, conf.VerifyPeerCertificate([][]byte{other.Raw}, nil))
+				assert.Error(t, conf.VerifyPeerCertificate(nil, nil))
+			},
+		},
+		{
+			name:  "verify-identity",
+			tls:   config.TLSConfig{Mode: "verify-identity", CA: caPath},
+			param: mysqlTLSName,
+			check: func(t *testing.T, cfg config.DatabaseConfig) {
+				conf, err := mysqlTLSConfig(cfg, cfg.Host)
+				require.NoError(t, err)
+				assert.False(t, conf.InsecureSkipVerify)
+				assert.Equal(t, "db.example.com", conf.ServerName)
+				require.NotNil(t, conf.RootCAs)
+				_, err = server.Verify(x509.VerifyOptions{Roots: conf.RootCAs})
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "client-certificate",
+			tls: config.TLSConfig{
+				Mode: "verify-identity",
+				Cert: filepath.Join(dir, "client.pem"),
+				Key:  filepath.Join(dir, "client-key.pem"),
+			},
+			param: mysqlTLSName,
+			check: func(t *testing.T, cfg config.DatabaseConfig) {
+				conf, err := mysqlTLSConfig(cfg, cfg.Host)
+				require.NoError(t, err)
+				assert.Len(t, conf.Certificates, 1)
+				// without a CA the system roots are used
+				assert.Nil(t, conf.RootCAs)
+			},
+		},
+		{
+			name:  "missing-ca",
+			tls:   config.TLSConfig{Mode: "verify-ca", CA: filepath.Join(dir, "missing.pem")},
+			param: mysqlTLSName,
+			check: func(t *testing.T, cfg config.DatabaseConfig) {
+				_, err := mysqlTLSConfig(cfg, cfg.Host)
+				assert.ErrorContains(t, err, "read ca")
+			},
+		},
+		{
+			name:  "invalid-ca",
+			tls:   config.TLSConfig{Mode: "verify-ca", CA: filepath.Join(dir, "ca-key.pem")},
+			param: mysqlTLSName,
+			check: func(t *testing.T, cfg config.DatabaseConfig) {
+				_, err := mysqlTLSConfig(cfg, cfg.Host)
+				assert.ErrorContains(t, err, "no certificate found")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base
+			cfg.TLS = tt.tls
+
+			dsn := mysqlDSN(cfg)
+			assert.True(t, strings.HasPrefix(dsn, "user:password@tcp(db.example.com:3306)/article?"))
+			query, err := url.ParseQuery(dsn[strings.Index(dsn, "?")+1:])
+			require.NoError(t, err)
+			assert.Equal(t, tt.param, query.Get("tls"))
+			assert.Equal(t, tt.param != "", query.Has("tls"))
+
+			if tt.noConfig {
+				conf, err := mysqlTLSConfig(cfg, cfg.Host)
+				assert.NoError(t, err)
+				assert.Nil(t, conf)
+				return
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestMySQLReplicaTLS(t *testing.T) {
+	dir := t.TempDir()
+	writeCert(t, dir, "ca", nil, nil)
+
+	base := config.DatabaseConfig{
+		Host:    "db.example.com",
+		Replica: "user:password@tcp(replica.example.com:3306)/article?parseTime=true",
+		TLS:     config.TLSConfig{Mode: "verify-identity", CA: filepath.Join(dir, "ca.pem")},
+	}
+
+	t.Run("verify-identity", func(t *testing.T) {
+		require.NoError(t, registerMySQLTLS(base))
+		t.Cleanup(func() {
+			mysql.DeregisterTLSConfig(mysqlTLSName)
+			mysql.DeregisterTLSConfig(mysqlReplicaTLSName)
+		})
+
+		dsn, err := mysqlReplicaDSN(base)
+		require.NoError(t, err)
+		parsed, err := mysql.ParseDSN(dsn)
+		require.NoError(t, err)
+		assert.Equal(t, mysqlReplicaTLSName, parsed.TLSConfig)
+		assert.Equal(t, "replica.example.com:3306", parsed.Addr)
+		assert.True(t, parsed.ParseTime)
+		require.NotNil(t, parsed.TLS)
+		assert.Equal(t, "replica.example.com", parsed.TLS.ServerName)
+
+		primary, err := mysql.ParseDSN(mysqlDSN(base))
+		require.NoError(t, err)
+		require.NotNil(t, primary.TLS)
+		assert.Equal(t, "db.example.com", primary.TLS.ServerName)
+	})
+
+	t.Run("preferred", func(t *testing.T) {
+		cfg := base
+		cfg.TLS = config.TLSConfig{Mode: "preferred"}
+		dsn, err := mysqlReplicaDSN(cfg)
+		require.NoError(t, err)
+		assert.Contains(t, dsn, "tls=preferred")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		cfg := base
+		cfg.TLS = config.TLSConfig{Mode: "disabled"}
+		dsn, err := mysqlReplicaDSN(cfg)
+		require.NoError(t, err)
+		assert.Equal(t, cfg.Replica, dsn)
+	})
+
+	t.Run("explicit-tls", func(t *testing.T) {
+		cfg := base
+		cfg.Replica = "user:password@tcp(replica.example.com:3306)/article?tls=skip-verify"
+		dsn, err := mysqlReplicaDSN(cfg)
+		require.NoError(t, err)
+		assert.Equal(t, cfg.Replica, dsn)
+	})
+
+	t.Run("invalid-dsn", func(t *testing.T) {
+		cfg := base
+		cfg.Replica = "replica.example.com"
+		_, err := mysqlReplicaDSN(cfg)
+		assert.ErrorContains(t, err, "parse replica dsn")
+		assert.ErrorContains(t, registerMySQLTLS(cfg), "parse replica dsn")
+	})
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	faker "github.com/go-faker/faker/v4"
+
+	"github.com/bxcodec/go-clean-arch/article"
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// fakeSeedData 生成 authors 位作者与 articles 篇文章，标题追加序号以保证标题与 slug 不重复，
+// 每四篇中有一篇为草稿
+func fakeSeedData(authors, articles int) ([]domain.Author, []*domain.Article) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	authorList := make([]domain.Author, 0, authors)
+	for range authors {
+		authorList = append(authorList, domain.Author{Name: faker.Name()})
+	}
+
+	articleList := make([]*domain.Article, 0, articles)
+	for i := range articles {
+		title := fmt.Sprintf("%s %d", strings.TrimSuffix(faker.Sentence(), "."), i+1)
+		status := domain.StatusPublished
+		if i%4 == 3 {
+			status = domain.StatusDraft
+		}
+		// 创建时间依次递增，分页顺序与序号一致
+		createdAt := now.Add(time.Duration(i-articles) * time.Minute)
+		articleList = append(articleList, &domain.Article{
+			Title:     title,
+			Slug:      article.Slugify(title),
+			Status:    status,
+			Content:   faker.Paragraph(),
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		})
+	}
+	return authorList, articleList
+}
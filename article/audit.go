@@ -0,0 +1,169 @@
+package article
+
+import (
+	"context"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/requestctx"
+	log "github.com/lingdongomg/g-lib/logger"
+)
+
+// AuditLogger represent the append-only store of the audit entries
+//
+//go:generate mockery --name AuditLogger
+type AuditLogger interface {
+	Record(ctx context.Context, entry domain.AuditEntry) error
+}
+
+// ArticleService is the set of usecases AuditedService decorates, both Service and CachedService implement it
+type ArticleService interface {
+	Fetch(ctx context.Context, cursor string, direction domain.PageDirection, num int64, filter domain.ArticleFilter) ([]domain.Article, domain.PageCursors, error)
+	FetchRecent(ctx context.Context, limit int64) ([]domain.Article, error)
+	FetchAll(ctx context.Context) (<-chan domain.Article, <-chan error)
+	FetchChanges(ctx context.Context, since time.Time, cursor string, num int64) ([]domain.Article, string, error)
+	Count(ctx context.Context, filter domain.ArticleFilter) (int64, error)
+	CountByAuthor(ctx context.Context, authorID int64) (int64, error)
+	ListAuthorsWithCounts(ctx context.Context) ([]domain.AuthorStat, error)
+	GetByID(ctx context.Context, id int64) (domain.Article, error)
+	GetByIDIncludingDeleted(ctx context.Context, id int64) (domain.Article, error)
+	GetByIDs(ctx context.Context, ids []int64) ([]domain.Article, []int64, error)
+	Update(ctx context.Context, ar *domain.Article) error
+	IncrementViewCount(ctx context.Context, id int64) error
+	UpdateStatus(ctx context.Context, id int64, status domain.ArticleStatus) (domain.Article, error)
+	GetByTitle(ctx context.Context, title string) (domain.Article, error)
+	FindByTitle(ctx context.Context, title string) ([]domain.Article, error)
+	GetBySlug(ctx context.Context, slug string) (domain.Article, error)
+	GetNeighbors(ctx context.Context, id int64) (prev, next *domain.Article, err error)
+	Store(context.Context, *domain.Article) error
+	Clone(ctx context.Context, id int64) (domain.Article, error)
+	StoreBatch(ctx context.Context, articles []*domain.Article) error
+	Upsert(ctx context.Context, ar *domain.Article) (created bool, err error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// AnonymousActor is recorded for the mutations of a request without an authenticated caller
+const AnonymousActor = "anonymous"
+
+// AuditedService decorates an ArticleService, recording an audit entry for every successful
+// create, update and delete. The caller is read with requestctx.ActorFromContext.
+type AuditedService struct {
+	ArticleService
+
+	audit AuditLogger
+	now   func() time.Time
+}
+
+// NewAuditedService wraps s so that its mutations are recorded to audit
+func NewAuditedService(s ArticleService, audit AuditLogger) *AuditedService {
+	return &AuditedService{
+		ArticleService: s,
+		audit:          audit,
+		now:            time.Now,
+	}
+}
+
+func (s *AuditedService) Store(ctx context.Context, m *domain.Article) error {
+	if err := s.ArticleService.Store(ctx, m); err != nil {
+		return err
+	}
+	after := *m
+	s.record(ctx, domain.AuditCreate, m.ID, nil, &after)
+	return nil
+}
+
+func (s *AuditedService) StoreBatch(ctx context.Context, articles []*domain.Article) error {
+	if err := s.ArticleService.StoreBatch(ctx, articles); err != nil {
+		return err
+	}
+	for _, m := range articles {
+		after := *m
+		s.record(ctx, domain.AuditCreate, m.ID, nil, &after)
+	}
+	return nil
+}
+
+func (s *AuditedService) Clone(ctx context.Context, id int64) (domain.Article, error) {
+	res, err := s.ArticleService.Clone(ctx, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	after := res
+	s.record(ctx, domain.AuditCreate, res.ID, nil, &after)
+	return res, nil
+}
+
+func (s *AuditedService) Update(ctx context.Context, ar *domain.Article) error {
+	before := s.before(ctx, ar.ID)
+	if err := s.ArticleService.Update(ctx, ar); err != nil {
+		return err
+	}
+	after := *ar
+	s.record(ctx, domain.AuditUpdate, ar.ID, before, &after)
+	return nil
+}
+
+func (s *AuditedService) UpdateStatus(ctx context.Context, id int64, status domain.ArticleStatus) (domain.Article, error) {
+	before := s.before(ctx, id)
+	res, err := s.ArticleService.UpdateStatus(ctx, id, status)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	after := res
+	s.record(ctx, domain.AuditUpdate, id, before, &after)
+	return res, nil
+}
+
+func (s *AuditedService) Upsert(ctx context.Context, ar *domain.Article) (bool, error) {
+	before := s.before(ctx, ar.ID)
+	created, err := s.ArticleService.Upsert(ctx, ar)
+	if err != nil {
+		return false, err
+	}
+	after := *ar
+	if created {
+		s.record(ctx, domain.AuditCreate, ar.ID, nil, &after)
+	} else {
+		s.record(ctx, domain.AuditUpdate, ar.ID, before, &after)
+	}
+	return created, nil
+}
+
+func (s *AuditedService) Delete(ctx context.Context, id int64) error {
+	before := s.before(ctx, id)
+	if err := s.ArticleService.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.record(ctx, domain.AuditDelete, id, before, nil)
+	return nil
+}
+
+// before loads the state of the article ahead of a mutation, nil when it cannot be read: the
+// mutation itself reports a missing article
+func (s *AuditedService) before(ctx context.Context, id int64) *domain.Article {
+	ar, err := s.ArticleService.GetByIDIncludingDeleted(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return &ar
+}
+
+// record writes the entry after the mutation succeeded. The write is not undone when recording
+// fails, so the failure is logged; the request cancellation does not cut the entry short.
+func (s *AuditedService) record(ctx context.Context, operation string, id int64, before, after *domain.Article) {
+	actor := requestctx.ActorFromContext(ctx)
+	if actor == "" {
+		actor = AnonymousActor
+	}
+	entry := domain.AuditEntry{
+		OccurredAt: s.now(),
+		Actor:      actor,
+		Operation:  operation,
+		ArticleID:  id,
+		Before:     before,
+		After:      after,
+	}
+	if err := s.audit.Record(context.WithoutCancel(ctx), entry); err != nil {
+		log.Error("Failed to record "+operation+" audit entry:", err)
+	}
+}
@@ -0,0 +1,145 @@
+package article_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/article"
+	"github.com/bxcodec/go-clean-arch/article/mocks"
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/requestctx"
+)
+
+// auditEntry matches the entry of one mutation, checking which states it carries
+func auditEntry(operation, actor string, id int64, hasBefore, hasAfter bool) interface{} {
+	return mock.MatchedBy(func(e domain.AuditEntry) bool {
+		return e.Operation == operation && e.Actor == actor && e.ArticleID == id &&
+			(e.Before != nil) == hasBefore && (e.After != nil) == hasAfter && !e.OccurredAt.IsZero()
+	})
+}
+
+func TestAuditedService(t *testing.T) {
+	existing := domain.Article{ID: 23, Title: "Hello", Content: "Content", Status: domain.StatusDraft}
+	ctx := requestctx.WithActor(context.TODO(), "alice")
+
+	t.Run("store", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Article).ID = 23
+		}).Return(nil).Once()
+		audit := mocks.NewAuditLogger(t)
+		audit.On("Record", mock.Anything, auditEntry(domain.AuditCreate, "alice", 23, false, true)).Return(nil).Once()
+		svc := article.NewAuditedService(article.NewService(mockArticleRepo, anyAuthorRepo()), audit)
+
+		err := svc.Store(ctx, &domain.Article{Title: "Hello", Content: "Content"})
+		require.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("store-batch", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(domain.Article{}, domain.ErrNotFound).Twice()
+		mockArticleRepo.On("GetBySlug", mock.Anything, mock.AnythingOfType("string")).Return(domain.Article{}, domain.ErrNotFound).Twice()
+		mockArticleRepo.On("StoreBatch", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			for i, a := range args.Get(1).([]*domain.Article) {
+				a.ID = int64(30 + i)
+			}
+		}).Return(nil).Once()
+		audit := mocks.NewAuditLogger(t)
+		audit.On("Record", mock.Anything, auditEntry(domain.AuditCreate, "alice", 30, false, true)).Return(nil).Once()
+		audit.On("Record", mock.Anything, auditEntry(domain.AuditCreate, "alice", 31, false, true)).Return(nil).Once()
+		svc := article.NewAuditedService(article.NewService(mockArticleRepo, anyAuthorRepo()), audit)
+
+		err := svc.StoreBatch(ctx, []*domain.Article{{Title: "One", Content: "1"}, {Title: "Two", Content: "2"}})
+		require.NoError(t, err)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByIDIncludingDeleted", mock.Anything, int64(23)).Return(existing, nil).Once()
+		mockArticleRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		audit := mocks.NewAuditLogger(t)
+		audit.On("Record", mock.Anything, mock.MatchedBy(func(e domain.AuditEntry) bool {
+			return e.Operation == domain.AuditUpdate && e.Actor == "alice" && e.ArticleID == 23 &&
+				e.Before.Title == "Hello" && e.After.Title == "Hello again"
+		})).Return(nil).Once()
+		svc := article.NewAuditedService(article.NewService(mockArticleRepo, anyAuthorRepo()), audit)
+
+		err := svc.Update(ctx, &domain.Article{ID: 23, Title: "Hello again", Content: "Content"})
+		require.NoError(t, err)
+	})
+
+	t.Run("update-status", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByIDIncludingDeleted", mock.Anything, int64(23)).Return(existing, nil).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(existing, nil).Once()
+		mockArticleRepo.On("UpdateStatus", mock.Anything, mock.AnythingOfType("*domain.Article"), domain.StatusDraft).Return(nil).Once()
+		audit := mocks.NewAuditLogger(t)
+		audit.On("Record", mock.Anything, mock.MatchedBy(func(e domain.AuditEntry) bool {
+			return e.Operation == domain.AuditUpdate && e.Actor == "alice" &&
+				e.Before.Status == domain.StatusDraft && e.After.Status == domain.StatusPublished
+		})).Return(nil).Once()
+		svc := article.NewAuditedService(article.NewService(mockArticleRepo, anyAuthorRepo()), audit)
+
+		_, err := svc.UpdateStatus(ctx, 23, domain.StatusPublished)
+		require.NoError(t, err)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByIDIncludingDeleted", mock.Anything, int64(23)).Return(existing, nil).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(existing, nil).Once()
+		mockArticleRepo.On("Delete", mock.Anything, int64(23)).Return(nil).Once()
+		audit := mocks.NewAuditLogger(t)
+		audit.On("Record", mock.Anything, auditEntry(domain.AuditDelete, "alice", 23, true, false)).Return(nil).Once()
+		svc := article.NewAuditedService(article.NewService(mockArticleRepo, anyAuthorRepo()), audit)
+
+		err := svc.Delete(ctx, 23)
+		require.NoError(t, err)
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByIDIncludingDeleted", mock.Anything, int64(23)).Return(existing, nil).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(existing, nil).Once()
+		mockArticleRepo.On("Delete", mock.Anything, int64(23)).Return(nil).Once()
+		audit := mocks.NewAuditLogger(t)
+		audit.On("Record", mock.Anything, auditEntry(domain.AuditDelete, article.AnonymousActor, 23, true, false)).Return(nil).Once()
+		svc := article.NewAuditedService(article.NewService(mockArticleRepo, anyAuthorRepo()), audit)
+
+		err := svc.Delete(context.TODO(), 23)
+		require.NoError(t, err)
+	})
+
+	t.Run("failed-mutation-is-not-recorded", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByIDIncludingDeleted", mock.Anything, int64(23)).Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(domain.Article{}, domain.ErrNotFound).Once()
+		audit := mocks.NewAuditLogger(t)
+		svc := article.NewAuditedService(article.NewService(mockArticleRepo, anyAuthorRepo()), audit)
+
+		err := svc.Delete(ctx, 23)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		audit.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+	})
+
+	t.Run("record-failure-does-not-fail-the-write", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByIDIncludingDeleted", mock.Anything, int64(23)).Return(existing, nil).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(existing, nil).Once()
+		mockArticleRepo.On("Delete", mock.Anything, int64(23)).Return(nil).Once()
+		audit := mocks.NewAuditLogger(t)
+		audit.On("Record", mock.Anything, mock.Anything).Return(errors.New("unexpected")).Once()
+		svc := article.NewAuditedService(article.NewService(mockArticleRepo, anyAuthorRepo()), audit)
+
+		err := svc.Delete(ctx, 23)
+		assert.NoError(t, err)
+	})
+}
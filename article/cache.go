@@ -0,0 +1,218 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/requestctx"
+)
+
+// DefaultListCacheTTL is how long a cached list page stays valid when no TTL is configured
+const DefaultListCacheTTL = 5 * time.Second
+
+// CachedService decorates a Service with a short-lived read-through cache for list pages and single
+// articles. Any successful write drops every cached entry, so readers see their own writes. A view is not
+// such a write: the view count of a cached article may lag by up to the TTL.
+type CachedService struct {
+	*Service
+
+	ttl time.Duration
+	now func() time.Time
+
+	mu         sync.Mutex
+	pages      map[string]listPage
+	articles   map[int64]cachedArticle
+	generation uint64
+
+	// loads coalesces the concurrent GetByID misses of one article into a single repository call
+	loads singleflight.Group
+}
+
+type cachedArticle struct {
+	article   domain.Article
+	expiresAt time.Time
+}
+
+type listPage struct {
+	articles  []domain.Article
+	cursors   domain.PageCursors
+	expiresAt time.Time
+}
+
+// NewCachedService wraps s with a list page cache, ttl <= 0 means DefaultListCacheTTL
+func NewCachedService(s *Service, ttl time.Duration) *CachedService {
+	if ttl <= 0 {
+		ttl = DefaultListCacheTTL
+	}
+	return &CachedService{
+		Service:  s,
+		ttl:      ttl,
+		now:      time.Now,
+		pages:    make(map[string]listPage),
+		articles: make(map[int64]cachedArticle),
+	}
+}
+
+// listCacheKey quotes the string parts so that no two parameter combinations share a key
+func listCacheKey(cursor string, direction domain.PageDirection, num int64, filter domain.ArticleFilter) string {
+	return fmt.Sprintf("%q|%q|%d|%q|%d|%s|%s|%q|%q", cursor, direction, num, filter.Status, filter.AuthorID,
+		filter.CreatedFrom.Format(time.RFC3339Nano), filter.CreatedTo.Format(time.RFC3339Nano), filter.Tags, filter.TagMatch)
+}
+
+// Fetch serves the page from the cache when possible and fills the cache on a miss
+func (c *CachedService) Fetch(ctx context.Context, cursor string, direction domain.PageDirection, num int64, filter domain.ArticleFilter) ([]domain.Article, domain.PageCursors, error) {
+	key := listCacheKey(cursor, direction, num, filter)
+
+	c.mu.Lock()
+	page, ok := c.pages[key]
+	if ok && c.now().Before(page.expiresAt) {
+		c.mu.Unlock()
+		return slices.Clone(page.articles), page.cursors, nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	res, cursors, err := c.Service.Fetch(ctx, cursor, direction, num, filter)
+	if err != nil {
+		return nil, domain.PageCursors{}, err
+	}
+
+	c.mu.Lock()
+	// a write that happened while we were fetching makes this result stale, so don't cache it
+	if c.generation == generation {
+		c.pages[key] = listPage{
+			articles:  slices.Clone(res),
+			cursors:   cursors,
+			expiresAt: c.now().Add(c.ttl),
+		}
+	}
+	c.mu.Unlock()
+	return res, cursors, nil
+}
+
+// GetByID serves the article from the cache when possible. When a hot article is missing or expired only
+// one caller loads it, the concurrent callers wait for and share its result instead of all hitting the
+// database; they also share its error, including one caused by the loading caller's ctx.
+// A ctx marked with requestctx.WithBypassCache always reads the database and refreshes the cached entry.
+func (c *CachedService) GetByID(ctx context.Context, id int64) (domain.Article, error) {
+	if requestctx.BypassCacheFromContext(ctx) {
+		// not coalesced, a load already in flight may have started before the change the caller expects
+		return c.load(ctx, id)
+	}
+
+	c.mu.Lock()
+	entry, ok := c.articles[id]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expiresAt) {
+		return entry.article, nil
+	}
+
+	res, err, _ := c.loads.Do(strconv.FormatInt(id, 10), func() (interface{}, error) {
+		return c.load(ctx, id)
+	})
+	return res.(domain.Article), err
+}
+
+// load reads the article from the Service and caches it, an article found missing is dropped from the cache
+func (c *CachedService) load(ctx context.Context, id int64) (domain.Article, error) {
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+
+	res, err := c.Service.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.mu.Lock()
+			delete(c.articles, id)
+			c.mu.Unlock()
+		}
+		return domain.Article{}, err
+	}
+
+	c.mu.Lock()
+	// a write that happened while we were loading makes this result stale, so don't cache it
+	if c.generation == generation {
+		c.articles[id] = cachedArticle{article: res, expiresAt: c.now().Add(c.ttl)}
+	}
+	c.mu.Unlock()
+	return res, nil
+}
+
+// Store stores the article and invalidates the cache
+func (c *CachedService) Store(ctx context.Context, m *domain.Article) error {
+	err := c.Service.Store(ctx, m)
+	if err == nil {
+		c.invalidate()
+	}
+	return err
+}
+
+// StoreBatch stores the articles and invalidates the cache
+func (c *CachedService) StoreBatch(ctx context.Context, articles []*domain.Article) error {
+	err := c.Service.StoreBatch(ctx, articles)
+	if err == nil {
+		c.invalidate()
+	}
+	return err
+}
+
+// Clone clones the article and invalidates the cache
+func (c *CachedService) Clone(ctx context.Context, id int64) (domain.Article, error) {
+	res, err := c.Service.Clone(ctx, id)
+	if err == nil {
+		c.invalidate()
+	}
+	return res, err
+}
+
+// Update updates the article and invalidates the cache
+func (c *CachedService) Update(ctx context.Context, ar *domain.Article) error {
+	err := c.Service.Update(ctx, ar)
+	if err == nil {
+		c.invalidate()
+	}
+	return err
+}
+
+// UpdateStatus changes the article status and invalidates the cache
+func (c *CachedService) UpdateStatus(ctx context.Context, id int64, status domain.ArticleStatus) (domain.Article, error) {
+	res, err := c.Service.UpdateStatus(ctx, id, status)
+	if err == nil {
+		c.invalidate()
+	}
+	return res, err
+}
+
+// Upsert creates or replaces the article and invalidates the cache
+func (c *CachedService) Upsert(ctx context.Context, ar *domain.Article) (bool, error) {
+	created, err := c.Service.Upsert(ctx, ar)
+	if err == nil {
+		c.invalidate()
+	}
+	return created, err
+}
+
+// Delete deletes the article and invalidates the cache
+func (c *CachedService) Delete(ctx context.Context, id int64) error {
+	err := c.Service.Delete(ctx, id)
+	if err == nil {
+		c.invalidate()
+	}
+	return err
+}
+
+func (c *CachedService) invalidate() {
+	c.mu.Lock()
+	c.generation++
+	clear(c.pages)
+	clear(c.articles)
+	c.mu.Unlock()
+}
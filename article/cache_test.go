@@ -0,0 +1,236 @@
+package article_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/article"
+	"github.com/bxcodec/go-clean-arch/article/mocks"
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/requestctx"
+)
+
+func anyAuthorRepo() *mocks.AuthorRepository {
+	mockAuthorrepo := new(mocks.AuthorRepository)
+	mockAuthorrepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(domain.Author{}, nil).Maybe()
+	return mockAuthorrepo
+}
+
+func TestCachedServiceFetch(t *testing.T) {
+	published := domain.ArticleFilter{Status: domain.StatusPublished}
+	drafts := domain.ArticleFilter{Status: domain.StatusDraft}
+	list := []domain.Article{{ID: 1, Title: "Hello"}}
+
+	t.Run("repeated-fetch-hits-cache", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Fetch", mock.Anything, "", domain.PageNext, int64(10), published).Return(list, domain.PageCursors{Next: "next"}, nil).Once()
+		svc := article.NewCachedService(article.NewService(mockArticleRepo, anyAuthorRepo()), time.Minute)
+
+		first, firstCursor, err := svc.Fetch(context.TODO(), "", domain.PageNext, 10, published)
+		require.NoError(t, err)
+		second, secondCursor, err := svc.Fetch(context.TODO(), "", domain.PageNext, 10, published)
+		require.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.Equal(t, firstCursor, secondCursor)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("different-params-do-not-collide", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Fetch", mock.Anything, "", domain.PageNext, int64(10), published).Return(list, domain.PageCursors{}, nil).Once()
+		mockArticleRepo.On("Fetch", mock.Anything, "", domain.PageNext, int64(10), drafts).Return([]domain.Article{}, domain.PageCursors{}, nil).Once()
+		mockArticleRepo.On("Fetch", mock.Anything, "", domain.PageNext, int64(5), published).Return(list, domain.PageCursors{}, nil).Once()
+		mockArticleRepo.On("Fetch", mock.Anything, "", domain.PagePrev, int64(10), published).Return(list, domain.PageCursors{}, nil).Once()
+		svc := article.NewCachedService(article.NewService(mockArticleRepo, anyAuthorRepo()), time.Minute)
+
+		for i := 0; i < 2; i++ {
+			_, _, err := svc.Fetch(context.TODO(), "", domain.PageNext, 10, published)
+			require.NoError(t, err)
+			res, _, err := svc.Fetch(context.TODO(), "", domain.PageNext, 10, drafts)
+			require.NoError(t, err)
+			assert.Empty(t, res)
+			_, _, err = svc.Fetch(context.TODO(), "", domain.PageNext, 5, published)
+			require.NoError(t, err)
+			_, _, err = svc.Fetch(context.TODO(), "", domain.PagePrev, 10, published)
+			require.NoError(t, err)
+		}
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("store-invalidates", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Fetch", mock.Anything, "", domain.PageNext, int64(10), published).Return(list, domain.PageCursors{}, nil).Twice()
+		mockArticleRepo.On("GetByTitle", mock.Anything, "New").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "new").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		svc := article.NewCachedService(article.NewService(mockArticleRepo, anyAuthorRepo()), time.Minute)
+
+		_, _, err := svc.Fetch(context.TODO(), "", domain.PageNext, 10, published)
+		require.NoError(t, err)
+		require.NoError(t, svc.Store(context.TODO(), &domain.Article{Title: "New"}))
+		_, _, err = svc.Fetch(context.TODO(), "", domain.PageNext, 10, published)
+		require.NoError(t, err)
+
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("store-batch-invalidates", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Fetch", mock.Anything, "", domain.PageNext, int64(10), published).Return(list, domain.PageCursors{}, nil).Twice()
+		mockArticleRepo.On("GetByTitle", mock.Anything, "New").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "new").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("StoreBatch", mock.Anything, mock.AnythingOfType("[]*domain.Article")).Return(nil).Once()
+		svc := article.NewCachedService(article.NewService(mockArticleRepo, anyAuthorRepo()), time.Minute)
+
+		_, _, err := svc.Fetch(context.TODO(), "", domain.PageNext, 10, published)
+		require.NoError(t, err)
+		require.NoError(t, svc.StoreBatch(context.TODO(), []*domain.Article{{Title: "New"}}))
+		_, _, err = svc.Fetch(context.TODO(), "", domain.PageNext, 10, published)
+		require.NoError(t, err)
+
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("expired-entry-refetches", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Fetch", mock.Anything, "", domain.PageNext, int64(10), published).Return(list, domain.PageCursors{}, nil).Twice()
+		svc := article.NewCachedService(article.NewService(mockArticleRepo, anyAuthorRepo()), time.Millisecond)
+
+		_, _, err := svc.Fetch(context.TODO(), "", domain.PageNext, 10, published)
+		require.NoError(t, err)
+		time.Sleep(5 * time.Millisecond)
+		_, _, err = svc.Fetch(context.TODO(), "", domain.PageNext, 10, published)
+		require.NoError(t, err)
+
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
+
+func TestCachedServiceGetByID(t *testing.T) {
+	ar := domain.Article{ID: 1, Title: "Hello"}
+
+	t.Run("concurrent-misses-load-once", func(t *testing.T) {
+		release := make(chan struct{})
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).
+			Run(func(mock.Arguments) { <-release }).Return(ar, nil).Once()
+		svc := article.NewCachedService(article.NewService(mockArticleRepo, anyAuthorRepo()), time.Minute)
+
+		const callers = 50
+		var (
+			wg      sync.WaitGroup
+			started sync.WaitGroup
+			results = make([]domain.Article, callers)
+			errs    = make([]error, callers)
+		)
+		started.Add(callers)
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				started.Done()
+				results[i], errs[i] = svc.GetByID(context.TODO(), 1)
+			}(i)
+		}
+		// hold the repository call until every caller is running, so they all miss the cold cache together
+		started.Wait()
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		for i := 0; i < callers; i++ {
+			require.NoError(t, errs[i])
+			assert.Equal(t, ar, results[i])
+		}
+		mockArticleRepo.AssertNumberOfCalls(t, "GetByID", 1)
+	})
+
+	t.Run("repeated-get-hits-cache", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(ar, nil).Once()
+		svc := article.NewCachedService(article.NewService(mockArticleRepo, anyAuthorRepo()), time.Minute)
+
+		for i := 0; i < 3; i++ {
+			res, err := svc.GetByID(context.TODO(), 1)
+			require.NoError(t, err)
+			assert.Equal(t, ar, res)
+		}
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("errors-are-not-cached", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(ar, nil).Once()
+		svc := article.NewCachedService(article.NewService(mockArticleRepo, anyAuthorRepo()), time.Minute)
+
+		_, err := svc.GetByID(context.TODO(), 1)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		res, err := svc.GetByID(context.TODO(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, ar, res)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("bypass-cache", func(t *testing.T) {
+		updated := domain.Article{ID: 1, Title: "Hello again"}
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(ar, nil).Once()
+		// changed in the database behind the cache's back
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(updated, nil).Once()
+		svc := article.NewCachedService(article.NewService(mockArticleRepo, anyAuthorRepo()), time.Minute)
+
+		res, err := svc.GetByID(context.TODO(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, ar, res)
+
+		// the cache still holds ar, a fresh read goes to the repository anyway
+		res, err = svc.GetByID(requestctx.WithBypassCache(context.TODO()), 1)
+		require.NoError(t, err)
+		assert.Equal(t, updated, res)
+
+		// and the fresh value replaced the cached one
+		res, err = svc.GetByID(context.TODO(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, updated, res)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("bypass-cache-not-found-evicts", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(ar, nil).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{}, domain.ErrNotFound).Twice()
+		svc := article.NewCachedService(article.NewService(mockArticleRepo, anyAuthorRepo()), time.Minute)
+
+		_, err := svc.GetByID(context.TODO(), 1)
+		require.NoError(t, err)
+		_, err = svc.GetByID(requestctx.WithBypassCache(context.TODO()), 1)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = svc.GetByID(context.TODO(), 1)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("delete-invalidates", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		// Delete looks the article up itself before deleting it
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(ar, nil).Twice()
+		mockArticleRepo.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{}, domain.ErrNotFound).Once()
+		svc := article.NewCachedService(article.NewService(mockArticleRepo, anyAuthorRepo()), time.Minute)
+
+		_, err := svc.GetByID(context.TODO(), 1)
+		require.NoError(t, err)
+		require.NoError(t, svc.Delete(context.TODO(), 1))
+		_, err = svc.GetByID(context.TODO(), 1)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,63 @@
+package article
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const cursorSeparator = "."
+
+// newCursorSecret generates a random per-process secret, used when none is configured
+func newCursorSecret() []byte {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(err)
+	}
+	return secret
+}
+
+// EncodeCursor wraps the repository cursor into an opaque, signed token
+func (a *Service) EncodeCursor(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	payload := base64.RawURLEncoding.EncodeToString([]byte(raw))
+	sig := base64.RawURLEncoding.EncodeToString(a.sign([]byte(raw)))
+	return payload + cursorSeparator + sig
+}
+
+// DecodeCursor verifies a token produced by EncodeCursor and returns the repository cursor.
+// An empty token means the first page and decodes to an empty cursor.
+func (a *Service) DecodeCursor(token string) (string, error) {
+	if token == "" {
+		return "", nil
+	}
+
+	payload, sig, ok := strings.Cut(token, cursorSeparator)
+	if !ok {
+		return "", domain.ErrBadParamInput
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", domain.ErrBadParamInput
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return "", domain.ErrBadParamInput
+	}
+	if !hmac.Equal(mac, a.sign(raw)) {
+		return "", domain.ErrBadParamInput
+	}
+	return string(raw), nil
+}
+
+func (a *Service) sign(raw []byte) []byte {
+	h := hmac.New(sha256.New, a.cursorSecret)
+	h.Write(raw)
+	return h.Sum(nil)
+}
@@ -0,0 +1,70 @@
+package article
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// IDGenerator assigns article ids application-side, before the article reaches the repository.
+// A zero id leaves the assignment to the repository (auto-increment column or counter).
+type IDGenerator interface {
+	NextID() (int64, error)
+}
+
+// AutoIncrement is the default IDGenerator, it always lets the repository pick the id
+type AutoIncrement struct{}
+
+func (AutoIncrement) NextID() (int64, error) { return 0, nil }
+
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+	snowflakeMaxNode      = 1<<snowflakeNodeBits - 1
+	snowflakeMaxSequence  = 1<<snowflakeSequenceBits - 1
+)
+
+// snowflakeEpoch keeps the 41 bit millisecond timestamp valid until 2093
+var snowflakeEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Snowflake generates time ordered ids made of a 41 bit millisecond timestamp, a 10 bit node id
+// and a 12 bit per-millisecond sequence. Every writer must be configured with a distinct node id.
+type Snowflake struct {
+	mu       sync.Mutex
+	node     int64
+	lastMS   int64
+	sequence int64
+	now      func() time.Time
+}
+
+// NewSnowflake creates a snowflake generator for the given node, between 0 and 1023
+func NewSnowflake(node int64) (*Snowflake, error) {
+	if node < 0 || node > snowflakeMaxNode {
+		return nil, fmt.Errorf("snowflake node must be between 0 and %d, got %d", snowflakeMaxNode, node)
+	}
+	return &Snowflake{node: node, now: time.Now}, nil
+}
+
+// NextID returns an id strictly greater than every id previously returned by this generator.
+// When the clock moves backwards the generator keeps counting from the last timestamp instead of going back.
+func (s *Snowflake) NextID() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ms := s.now().Sub(snowflakeEpoch).Milliseconds()
+	if ms < s.lastMS {
+		ms = s.lastMS
+	}
+	if ms == s.lastMS {
+		s.sequence = (s.sequence + 1) & snowflakeMaxSequence
+		if s.sequence == 0 {
+			// sequence exhausted for this millisecond, borrow the next one; the clock catches up later
+			ms++
+		}
+	} else {
+		s.sequence = 0
+	}
+	s.lastMS = ms
+
+	return ms<<(snowflakeNodeBits+snowflakeSequenceBits) | s.node<<snowflakeSequenceBits | s.sequence, nil
+}
@@ -0,0 +1,55 @@
+package article_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/article"
+)
+
+func TestSnowflakeConcurrent(t *testing.T) {
+	gen, err := article.NewSnowflake(7)
+	require.NoError(t, err)
+
+	const workers, perWorker = 8, 2000
+	results := make([][]int64, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			ids := make([]int64, 0, perWorker)
+			for i := 0; i < perWorker; i++ {
+				id, err := gen.NextID()
+				assert.NoError(t, err)
+				ids = append(ids, id)
+			}
+			results[w] = ids
+		}(w)
+	}
+	wg.Wait()
+
+	seen := make(map[int64]bool, workers*perWorker)
+	for _, ids := range results {
+		// every caller observes strictly increasing ids
+		for i := 1; i < len(ids); i++ {
+			assert.Less(t, ids[i-1], ids[i])
+		}
+		for _, id := range ids {
+			assert.False(t, seen[id], "duplicate id %d", id)
+			seen[id] = true
+			assert.Positive(t, id)
+		}
+	}
+	assert.Len(t, seen, workers*perWorker)
+}
+
+func TestNewSnowflakeInvalidNode(t *testing.T) {
+	_, err := article.NewSnowflake(-1)
+	assert.Error(t, err)
+	_, err = article.NewSnowflake(1024)
+	assert.Error(t, err)
+}
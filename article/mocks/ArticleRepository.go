@@ -7,6 +7,8 @@ import (
 
 	domain "github.com/bxcodec/go-clean-arch/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // ArticleRepository is an autogenerated mock type for the ArticleRepository type
@@ -14,6 +16,62 @@ type ArticleRepository struct {
 	mock.Mock
 }
 
+// Count provides a mock function with given fields: ctx, filter
+func (_m *ArticleRepository) Count(ctx context.Context, filter domain.ArticleFilter) (int64, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Count")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ArticleFilter) (int64, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ArticleFilter) int64); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ArticleFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountByAuthor provides a mock function with given fields: ctx, authorID
+func (_m *ArticleRepository) CountByAuthor(ctx context.Context, authorID int64) (int64, error) {
+	ret := _m.Called(ctx, authorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountByAuthor")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (int64, error)); ok {
+		return rf(ctx, authorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) int64); ok {
+		r0 = rf(ctx, authorID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, authorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ctx, id
 func (_m *ArticleRepository) Delete(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
@@ -32,36 +90,133 @@ func (_m *ArticleRepository) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *ArticleRepository) Fetch(ctx context.Context, cursor string, num int64) ([]domain.Article, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// Exists provides a mock function with given fields: ctx, id
+func (_m *ArticleRepository) Exists(ctx context.Context, id int64) (bool, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Exists")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (bool, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) bool); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Fetch provides a mock function with given fields: ctx, cursor, direction, num, filter
+func (_m *ArticleRepository) Fetch(ctx context.Context, cursor string, direction domain.PageDirection, num int64, filter domain.ArticleFilter) ([]domain.Article, domain.PageCursors, error) {
+	ret := _m.Called(ctx, cursor, direction, num, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Fetch")
 	}
 
+	var r0 []domain.Article
+	var r1 domain.PageCursors
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.PageDirection, int64, domain.ArticleFilter) ([]domain.Article, domain.PageCursors, error)); ok {
+		return rf(ctx, cursor, direction, num, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.PageDirection, int64, domain.ArticleFilter) []domain.Article); ok {
+		r0 = rf(ctx, cursor, direction, num, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.PageDirection, int64, domain.ArticleFilter) domain.PageCursors); ok {
+		r1 = rf(ctx, cursor, direction, num, filter)
+	} else {
+		r1 = ret.Get(1).(domain.PageCursors)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, domain.PageDirection, int64, domain.ArticleFilter) error); ok {
+		r2 = rf(ctx, cursor, direction, num, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchAll provides a mock function with given fields: ctx
+func (_m *ArticleRepository) FetchAll(ctx context.Context) (<-chan domain.Article, <-chan error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchAll")
+	}
+
+	var r0 <-chan domain.Article
+	var r1 <-chan error
+	if rf, ok := ret.Get(0).(func(context.Context) (<-chan domain.Article, <-chan error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) <-chan domain.Article); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) <-chan error); ok {
+		r1 = rf(ctx)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(<-chan error)
+		}
+	}
+
+	return r0, r1
+}
+
+// FetchChanges provides a mock function with given fields: ctx, since, cursor, num
+func (_m *ArticleRepository) FetchChanges(ctx context.Context, since time.Time, cursor string, num int64) ([]domain.Article, string, error) {
+	ret := _m.Called(ctx, since, cursor, num)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchChanges")
+	}
+
 	var r0 []domain.Article
 	var r1 string
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) ([]domain.Article, string, error)); ok {
-		return rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string, int64) ([]domain.Article, string, error)); ok {
+		return rf(ctx, since, cursor, num)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []domain.Article); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string, int64) []domain.Article); ok {
+		r0 = rf(ctx, since, cursor, num)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Article)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
-		r1 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, string, int64) string); ok {
+		r1 = rf(ctx, since, cursor, num)
 	} else {
 		r1 = ret.Get(1).(string)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
-		r2 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(2).(func(context.Context, time.Time, string, int64) error); ok {
+		r2 = rf(ctx, since, cursor, num)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -69,6 +224,66 @@ func (_m *ArticleRepository) Fetch(ctx context.Context, cursor string, num int64
 	return r0, r1, r2
 }
 
+// FetchRecent provides a mock function with given fields: ctx, num, filter
+func (_m *ArticleRepository) FetchRecent(ctx context.Context, num int64, filter domain.ArticleFilter) ([]domain.Article, error) {
+	ret := _m.Called(ctx, num, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchRecent")
+	}
+
+	var r0 []domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.ArticleFilter) ([]domain.Article, error)); ok {
+		return rf(ctx, num, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.ArticleFilter) []domain.Article); ok {
+		r0 = rf(ctx, num, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.ArticleFilter) error); ok {
+		r1 = rf(ctx, num, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByTitle provides a mock function with given fields: ctx, title
+func (_m *ArticleRepository) FindByTitle(ctx context.Context, title string) ([]domain.Article, error) {
+	ret := _m.Called(ctx, title)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByTitle")
+	}
+
+	var r0 []domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Article, error)); ok {
+		return rf(ctx, title)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Article); ok {
+		r0 = rf(ctx, title)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, title)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByID provides a mock function with given fields: ctx, id
 func (_m *ArticleRepository) GetByID(ctx context.Context, id int64) (domain.Article, error) {
 	ret := _m.Called(ctx, id)
@@ -97,6 +312,92 @@ func (_m *ArticleRepository) GetByID(ctx context.Context, id int64) (domain.Arti
 	return r0, r1
 }
 
+// GetByIDIncludingDeleted provides a mock function with given fields: ctx, id
+func (_m *ArticleRepository) GetByIDIncludingDeleted(ctx context.Context, id int64) (domain.Article, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByIDIncludingDeleted")
+	}
+
+	var r0 domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (domain.Article, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) domain.Article); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByIDs provides a mock function with given fields: ctx, ids
+func (_m *ArticleRepository) GetByIDs(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByIDs")
+	}
+
+	var r0 []domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) ([]domain.Article, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []domain.Article); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBySlug provides a mock function with given fields: ctx, slug
+func (_m *ArticleRepository) GetBySlug(ctx context.Context, slug string) (domain.Article, error) {
+	ret := _m.Called(ctx, slug)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBySlug")
+	}
+
+	var r0 domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Article, error)); ok {
+		return rf(ctx, slug)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Article); ok {
+		r0 = rf(ctx, slug)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByTitle provides a mock function with given fields: ctx, title
 func (_m *ArticleRepository) GetByTitle(ctx context.Context, title string) (domain.Article, error) {
 	ret := _m.Called(ctx, title)
@@ -125,6 +426,80 @@ func (_m *ArticleRepository) GetByTitle(ctx context.Context, title string) (doma
 	return r0, r1
 }
 
+// GetNext provides a mock function with given fields: ctx, ar, filter
+func (_m *ArticleRepository) GetNext(ctx context.Context, ar domain.Article, filter domain.ArticleFilter) (domain.Article, error) {
+	ret := _m.Called(ctx, ar, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNext")
+	}
+
+	var r0 domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Article, domain.ArticleFilter) (domain.Article, error)); ok {
+		return rf(ctx, ar, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Article, domain.ArticleFilter) domain.Article); ok {
+		r0 = rf(ctx, ar, filter)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Article, domain.ArticleFilter) error); ok {
+		r1 = rf(ctx, ar, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPrevious provides a mock function with given fields: ctx, ar, filter
+func (_m *ArticleRepository) GetPrevious(ctx context.Context, ar domain.Article, filter domain.ArticleFilter) (domain.Article, error) {
+	ret := _m.Called(ctx, ar, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPrevious")
+	}
+
+	var r0 domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Article, domain.ArticleFilter) (domain.Article, error)); ok {
+		return rf(ctx, ar, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Article, domain.ArticleFilter) domain.Article); ok {
+		r0 = rf(ctx, ar, filter)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Article, domain.ArticleFilter) error); ok {
+		r1 = rf(ctx, ar, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IncrementViewCount provides a mock function with given fields: ctx, id
+func (_m *ArticleRepository) IncrementViewCount(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrementViewCount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Store provides a mock function with given fields: ctx, a
 func (_m *ArticleRepository) Store(ctx context.Context, a *domain.Article) error {
 	ret := _m.Called(ctx, a)
@@ -143,6 +518,24 @@ func (_m *ArticleRepository) Store(ctx context.Context, a *domain.Article) error
 	return r0
 }
 
+// StoreBatch provides a mock function with given fields: ctx, articles
+func (_m *ArticleRepository) StoreBatch(ctx context.Context, articles []*domain.Article) error {
+	ret := _m.Called(ctx, articles)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreBatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*domain.Article) error); ok {
+		r0 = rf(ctx, articles)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, ar
 func (_m *ArticleRepository) Update(ctx context.Context, ar *domain.Article) error {
 	ret := _m.Called(ctx, ar)
@@ -161,6 +554,52 @@ func (_m *ArticleRepository) Update(ctx context.Context, ar *domain.Article) err
 	return r0
 }
 
+// UpdateStatus provides a mock function with given fields: ctx, ar, from
+func (_m *ArticleRepository) UpdateStatus(ctx context.Context, ar *domain.Article, from domain.ArticleStatus) error {
+	ret := _m.Called(ctx, ar, from)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Article, domain.ArticleStatus) error); ok {
+		r0 = rf(ctx, ar, from)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Upsert provides a mock function with given fields: ctx, ar
+func (_m *ArticleRepository) Upsert(ctx context.Context, ar *domain.Article) (bool, error) {
+	ret := _m.Called(ctx, ar)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Upsert")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Article) (bool, error)); ok {
+		return rf(ctx, ar)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Article) bool); ok {
+		r0 = rf(ctx, ar)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Article) error); ok {
+		r1 = rf(ctx, ar)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewArticleRepository creates a new instance of ArticleRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewArticleRepository(t interface {
@@ -42,6 +42,36 @@ func (_m *AuthorRepository) GetByID(ctx context.Context, id int64) (domain.Autho
 	return r0, r1
 }
 
+// ListAuthorsWithCounts provides a mock function with given fields: ctx
+func (_m *AuthorRepository) ListAuthorsWithCounts(ctx context.Context) ([]domain.AuthorStat, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAuthorsWithCounts")
+	}
+
+	var r0 []domain.AuthorStat
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.AuthorStat, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.AuthorStat); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AuthorStat)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewAuthorRepository creates a new instance of AuthorRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewAuthorRepository(t interface {
@@ -0,0 +1,34 @@
+package article
+
+import (
+	"fmt"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// Sanitizer cleans the content of an article before it is stored, so content rendered as HTML by
+// other systems cannot carry stored XSS
+type Sanitizer interface {
+	Sanitize(content string) string
+}
+
+// Sanitizer policies, see NewSanitizer
+const (
+	// PolicyUGC keeps the formatting of user generated content (paragraphs, emphasis, links, lists,
+	// tables, images...) and removes scripts, styles, iframes and event handler attributes
+	PolicyUGC = "ugc"
+	// PolicyStrict removes every HTML tag and keeps the text only
+	PolicyStrict = "strict"
+)
+
+// NewSanitizer returns the bluemonday policy named policy, an empty policy means PolicyUGC
+func NewSanitizer(policy string) (Sanitizer, error) {
+	switch policy {
+	case "", PolicyUGC:
+		return bluemonday.UGCPolicy(), nil
+	case PolicyStrict:
+		return bluemonday.StrictPolicy(), nil
+	default:
+		return nil, fmt.Errorf("unsupported sanitize policy: %s", policy)
+	}
+}
@@ -0,0 +1,98 @@
+package article_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/article"
+	"github.com/bxcodec/go-clean-arch/article/mocks"
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+func TestNewSanitizer(t *testing.T) {
+	content := `<p>Hello <strong>bold</strong> <a href="https://example.com">link</a></p><script>alert("xss")</script>` +
+		`<img src="x.png" onerror="alert(1)">`
+
+	tests := []struct {
+		policy    string
+		sanitized string
+	}{
+		{policy: article.PolicyUGC, sanitized: `<p>Hello <strong>bold</strong> <a href="https://example.com" rel="nofollow">link</a></p><img src="x.png">`},
+		{policy: "", sanitized: `<p>Hello <strong>bold</strong> <a href="https://example.com" rel="nofollow">link</a></p><img src="x.png">`},
+		{policy: article.PolicyStrict, sanitized: `Hello bold link`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			s, err := article.NewSanitizer(tt.policy)
+			require.NoError(t, err)
+			assert.Equal(t, tt.sanitized, s.Sanitize(content))
+		})
+	}
+
+	_, err := article.NewSanitizer("permissive")
+	assert.EqualError(t, err, "unsupported sanitize policy: permissive")
+}
+
+func TestStoreSanitizesContent(t *testing.T) {
+	ugc, err := article.NewSanitizer(article.PolicyUGC)
+	require.NoError(t, err)
+
+	t.Run("script-removed", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.MatchedBy(func(a *domain.Article) bool {
+			return a.Content == "<p><em>Hi</em></p>"
+		})).Return(nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository), article.WithSanitizer(ugc))
+
+		ar := domain.Article{Title: "Hello", Content: `<p><em>Hi</em></p><script>alert("xss")</script>`}
+		require.NoError(t, u.Store(context.TODO(), &ar))
+
+		assert.Equal(t, "<p><em>Hi</em></p>", ar.Content)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("nothing-left", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository), article.WithSanitizer(ugc))
+
+		err := u.Store(context.TODO(), &domain.Article{Title: "Hello", Content: `<script>alert("xss")</script>`})
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		mockArticleRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Update", mock.Anything, mock.MatchedBy(func(a *domain.Article) bool {
+			return a.Content == "<b>safe</b>"
+		})).Return(nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository), article.WithSanitizer(ugc))
+
+		ar := domain.Article{ID: 1, Title: "Hello", Content: `<b onclick="steal()">safe</b>`}
+		require.NoError(t, u.Update(context.TODO(), &ar))
+
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("disabled-by-default", func(t *testing.T) {
+		raw := `<script>alert("xss")</script>`
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.MatchedBy(func(a *domain.Article) bool {
+			return a.Content == raw
+		})).Return(nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		require.NoError(t, u.Store(context.TODO(), &domain.Article{Title: "Hello", Content: raw}))
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
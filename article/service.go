@@ -2,10 +2,16 @@ package article
 
 import (
 	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"unicode/utf8"
 
 	"time"
 
 	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/tracing"
 	log "github.com/lingdongomg/g-lib/logger"
 	"golang.org/x/sync/errgroup"
 )
@@ -14,11 +20,46 @@ import (
 //
 //go:generate mockery --name ArticleRepository
 type ArticleRepository interface {
-	Fetch(ctx context.Context, cursor string, num int64) (res []domain.Article, nextCursor string, err error)
+	// Fetch returns up to num articles after the cursor, or before it with domain.PagePrev, in created_at order
+	Fetch(ctx context.Context, cursor string, direction domain.PageDirection, num int64, filter domain.ArticleFilter) (res []domain.Article, page domain.PageCursors, err error)
+	// FetchRecent returns the num most recently created articles matching filter, newest first
+	FetchRecent(ctx context.Context, num int64, filter domain.ArticleFilter) ([]domain.Article, error)
+	FetchAll(ctx context.Context) (<-chan domain.Article, <-chan error)
+	// FetchChanges returns up to num articles whose updated_at is after since in (updated_at, id) order,
+	// soft-deleted ones included with DeletedAt set; cursor continues after the last article of the previous page
+	FetchChanges(ctx context.Context, since time.Time, cursor string, num int64) (res []domain.Article, nextCursor string, err error)
+	Count(ctx context.Context, filter domain.ArticleFilter) (int64, error)
+	CountByAuthor(ctx context.Context, authorID int64) (int64, error)
+	// Exists reports whether a non-deleted article has the id without fetching it
+	Exists(ctx context.Context, id int64) (bool, error)
 	GetByID(ctx context.Context, id int64) (domain.Article, error)
+	// GetByIDIncludingDeleted is GetByID without hiding soft-deleted articles, read from the primary
+	GetByIDIncludingDeleted(ctx context.Context, id int64) (domain.Article, error)
+	// GetByIDs returns the existing, non-deleted articles among ids, ids that do not exist are left out
+	GetByIDs(ctx context.Context, ids []int64) ([]domain.Article, error)
+	// GetByTitle returns one of the articles titled title.
+	//
+	// Deprecated: titles are not unique and which match is returned is unspecified, use FindByTitle.
 	GetByTitle(ctx context.Context, title string) (domain.Article, error)
+	// FindByTitle returns every non-deleted article titled title in id order, an empty slice when there is none
+	FindByTitle(ctx context.Context, title string) ([]domain.Article, error)
+	GetBySlug(ctx context.Context, slug string) (domain.Article, error)
+	// GetPrevious and GetNext return the article right before/after ar in created_at order among those
+	// matching filter, or domain.ErrNotFound at the boundary
+	GetPrevious(ctx context.Context, ar domain.Article, filter domain.ArticleFilter) (domain.Article, error)
+	GetNext(ctx context.Context, ar domain.Article, filter domain.ArticleFilter) (domain.Article, error)
 	Update(ctx context.Context, ar *domain.Article) error
+	// IncrementViewCount adds one view to the non-deleted article atomically, domain.ErrNotFound if there is none
+	IncrementViewCount(ctx context.Context, id int64) error
+	// UpdateStatus persists ar.Status only if the stored status is still from, otherwise it fails with domain.ErrConflict
+	UpdateStatus(ctx context.Context, ar *domain.Article, from domain.ArticleStatus) error
 	Store(ctx context.Context, a *domain.Article) error
+	// StoreBatch stores every article in a single transaction, either all of them are stored or none
+	StoreBatch(ctx context.Context, articles []*domain.Article) error
+	// Upsert inserts ar at ar.ID, or when that id exists replaces its title, content and author and restores it
+	// if it was soft-deleted; the slug, status and created_at of ar are only used on insert
+	Upsert(ctx context.Context, ar *domain.Article) (created bool, err error)
+	// Delete soft-deletes the article, it stays visible to GetByIDIncludingDeleted only
 	Delete(ctx context.Context, id int64) error
 }
 
@@ -27,21 +68,162 @@ type ArticleRepository interface {
 //go:generate mockery --name AuthorRepository
 type AuthorRepository interface {
 	GetByID(ctx context.Context, id int64) (domain.Author, error)
+	// ListAuthorsWithCounts returns every author ordered by id, authors without articles have a zero count
+	ListAuthorsWithCounts(ctx context.Context) ([]domain.AuthorStat, error)
 }
 
+// EventPublisher represent the contract to notify other systems about article changes
+//
+//go:generate mockery --name EventPublisher
+type EventPublisher interface {
+	Publish(ctx context.Context, event domain.ArticleEvent) error
+}
+
+type noopPublisher struct{}
+
+func (noopPublisher) Publish(context.Context, domain.ArticleEvent) error { return nil }
+
 type Service struct {
-	articleRepo ArticleRepository
-	authorRepo  AuthorRepository
+	articleRepo  ArticleRepository
+	authorRepo   AuthorRepository
+	publisher    EventPublisher
+	idGenerator  IDGenerator
+	sanitizer    Sanitizer
+	cursorSecret []byte
+	uniqueTitles bool
 }
 
 // NewService will create a new article service object
-func NewService(a ArticleRepository, ar AuthorRepository) *Service {
-	return &Service{
-		articleRepo: a,
-		authorRepo:  ar,
+func NewService(a ArticleRepository, ar AuthorRepository, opts ...Option) *Service {
+	s := &Service{
+		articleRepo:  a,
+		authorRepo:   ar,
+		publisher:    noopPublisher{},
+		idGenerator:  AutoIncrement{},
+		uniqueTitles: true,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.cursorSecret == nil {
+		s.cursorSecret = newCursorSecret()
+	}
+	return s
+}
+
+// Option configures optional behaviour of the Service
+type Option func(*Service)
+
+// WithCursorSecret sets the key used to sign the pagination cursors handed out to clients.
+// Every instance serving the same clients must share the same secret.
+func WithCursorSecret(secret []byte) Option {
+	return func(s *Service) {
+		if len(secret) > 0 {
+			s.cursorSecret = secret
+		}
+	}
+}
+
+// WithEventPublisher sets the publisher notified after every successful write. Publish is called
+// inline by the write, so a publisher doing network I/O should queue the event (see event.AsyncPublisher).
+func WithEventPublisher(p EventPublisher) Option {
+	return func(s *Service) {
+		if p != nil {
+			s.publisher = p
+		}
+	}
+}
+
+// WithIDGenerator makes Store assign ids application-side instead of relying on the database
+func WithIDGenerator(g IDGenerator) Option {
+	return func(s *Service) {
+		if g != nil {
+			s.idGenerator = g
+		}
+	}
+}
+
+// WithSanitizer cleans the content of every stored or updated article with s, see NewSanitizer
+func WithSanitizer(s Sanitizer) Option {
+	return func(svc *Service) {
+		svc.sanitizer = s
+	}
+}
+
+// WithUniqueTitles sets whether Store, StoreBatch and Upsert reject a title another article already has
+// with domain.ErrConflict, which they do by default. Only live articles count, a soft-deleted article's
+// title may be reused. The database has no unique index on the title, so this check is the only guard.
+func WithUniqueTitles(unique bool) Option {
+	return func(s *Service) {
+		s.uniqueTitles = unique
 	}
 }
 
+// existingTitle returns the live article already titled title, ok is false when there is none or titles need
+// not be unique. Only domain.ErrNotFound means the title is free, any other lookup error is returned, as the
+// title would otherwise pass unchecked. Concurrent writes of one title still collide on the slug derived
+// from it, which the database keeps unique among the live articles.
+func (a *Service) existingTitle(ctx context.Context, title string) (existed domain.Article, ok bool, err error) {
+	if !a.uniqueTitles {
+		return domain.Article{}, false, nil
+	}
+	existed, err = a.articleRepo.GetByTitle(ctx, title)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Article{}, false, nil
+	}
+	if err != nil {
+		return domain.Article{}, false, err
+	}
+	return existed, true, nil
+}
+
+// sanitize normalizes the tags of m and applies the configured Sanitizer to its content. Content that was
+// nothing but disallowed markup is rejected with domain.ErrBadParamInput, like a missing content would be.
+func (a *Service) sanitize(m *domain.Article) error {
+	tags, err := normalizeTags(m.Tags)
+	if err != nil {
+		return err
+	}
+	m.Tags = tags
+	if a.sanitizer == nil {
+		return nil
+	}
+	m.Content = a.sanitizer.Sanitize(m.Content)
+	if strings.TrimSpace(m.Content) == "" {
+		return domain.ErrBadParamInput
+	}
+	return nil
+}
+
+const (
+	// maxTags is the number of tags an article may carry
+	maxTags = 10
+	// maxTagLength is the size of the tag column of article_tag
+	maxTagLength = 64
+)
+
+// normalizeTags trims the tags, drops blanks and duplicates and sorts them. More than maxTags tags, a tag
+// longer than maxTagLength or one containing a comma, which separates the tags of the ?tags= filter, is
+// rejected with domain.ErrBadParamInput.
+func normalizeTags(tags []string) ([]string, error) {
+	var res []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || slices.Contains(res, tag) {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength || strings.Contains(tag, ",") {
+			return nil, domain.ErrBadParamInput
+		}
+		res = append(res, tag)
+	}
+	if len(res) > maxTags {
+		return nil, domain.ErrBadParamInput
+	}
+	slices.Sort(res)
+	return res, nil
+}
+
 /*
 * In this function below, I'm using errgroup with the pipeline pattern
 * Look how this works in this package explanation
@@ -52,15 +234,18 @@ func (a *Service) fillAuthorDetails(ctx context.Context, data []domain.Article)
 	// Get the author's id
 	mapAuthors := map[int64]domain.Author{}
 
+	// articles without an author keep the empty author, as getAuthor returns for a single one
 	for _, article := range data { //nolint
-		mapAuthors[article.Author.ID] = domain.Author{}
+		if article.Author.ID != 0 {
+			mapAuthors[article.Author.ID] = domain.Author{}
+		}
 	}
 	// Using goroutine to fetch the author's detail
 	chanAuthor := make(chan domain.Author)
 	for authorID := range mapAuthors {
 		authorID := authorID
 		g.Go(func() error {
-			res, err := a.authorRepo.GetByID(ctx, authorID)
+			res, err := a.getAuthor(ctx, authorID)
 			if err != nil {
 				return err
 			}
@@ -98,26 +283,124 @@ func (a *Service) fillAuthorDetails(ctx context.Context, data []domain.Article)
 	return data, nil
 }
 
-func (a *Service) Fetch(ctx context.Context, cursor string, num int64) (res []domain.Article, nextCursor string, err error) {
-	res, nextCursor, err = a.articleRepo.Fetch(ctx, cursor, num)
+// Fetch returns the page after cursor, or before it with domain.PagePrev, and the signed cursors of the
+// pages around it. An unknown direction is rejected with domain.ErrBadParamInput.
+func (a *Service) Fetch(ctx context.Context, cursor string, direction domain.PageDirection, num int64, filter domain.ArticleFilter) (res []domain.Article, page domain.PageCursors, err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.Fetch")
+	defer func() { tracing.End(span, err) }()
+
+	if !direction.Valid() {
+		return nil, domain.PageCursors{}, domain.ErrBadParamInput
+	}
+	rawCursor, err := a.DecodeCursor(cursor)
+	if err != nil {
+		return nil, domain.PageCursors{}, err
+	}
+
+	res, page, err = a.articleRepo.Fetch(ctx, rawCursor, direction, num, filter)
+	if err != nil {
+		return nil, domain.PageCursors{}, err
+	}
+
+	res, err = a.fillAuthorDetails(ctx, res)
+	if err != nil {
+		return nil, domain.PageCursors{}, err
+	}
+	return res, domain.PageCursors{Next: a.EncodeCursor(page.Next), Prev: a.EncodeCursor(page.Prev)}, nil
+}
+
+// FetchRecent returns the limit most recently published articles, newest first, without a cursor to manage
+func (a *Service) FetchRecent(ctx context.Context, limit int64) (res []domain.Article, err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.FetchRecent")
+	defer func() { tracing.End(span, err) }()
+
+	res, err = a.articleRepo.FetchRecent(ctx, limit, domain.ArticleFilter{Status: domain.StatusPublished})
+	if err != nil {
+		return nil, err
+	}
+	return a.fillAuthorDetails(ctx, res)
+}
+
+// FetchAll streams every article, see ArticleRepository.FetchAll for the channel semantics
+func (a *Service) FetchAll(ctx context.Context) (<-chan domain.Article, <-chan error) {
+	return a.articleRepo.FetchAll(ctx)
+}
+
+// FetchChanges returns the articles updated after since, deleted ones included, for clients syncing incrementally.
+// Like Fetch the cursor is signed.
+func (a *Service) FetchChanges(ctx context.Context, since time.Time, cursor string, num int64) (res []domain.Article, nextCursor string, err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.FetchChanges")
+	defer func() { tracing.End(span, err) }()
+
+	rawCursor, err := a.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, nextCursor, err = a.articleRepo.FetchChanges(ctx, since, rawCursor, num)
 	if err != nil {
 		return nil, "", err
 	}
 
 	res, err = a.fillAuthorDetails(ctx, res)
 	if err != nil {
-		nextCursor = ""
+		return nil, "", err
 	}
-	return
+	return res, a.EncodeCursor(nextCursor), nil
+}
+
+// Count returns the number of articles matching filter
+func (a *Service) Count(ctx context.Context, filter domain.ArticleFilter) (total int64, err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.Count")
+	defer func() { tracing.End(span, err) }()
+
+	return a.articleRepo.Count(ctx, filter)
+}
+
+// ListAuthorsWithCounts returns every author with the number of articles it wrote, regardless of their status
+func (a *Service) ListAuthorsWithCounts(ctx context.Context) (res []domain.AuthorStat, err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.ListAuthorsWithCounts")
+	defer func() { tracing.End(span, err) }()
+
+	return a.authorRepo.ListAuthorsWithCounts(ctx)
+}
+
+// CountByAuthor returns how many articles the author wrote, regardless of their status
+func (a *Service) CountByAuthor(ctx context.Context, authorID int64) (total int64, err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.CountByAuthor")
+	defer func() { tracing.End(span, err) }()
+
+	return a.articleRepo.CountByAuthor(ctx, authorID)
 }
 
 func (a *Service) GetByID(ctx context.Context, id int64) (res domain.Article, err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.GetByID")
+	defer func() { tracing.End(span, err) }()
+
 	res, err = a.articleRepo.GetByID(ctx, id)
 	if err != nil {
 		return
 	}
 
-	resAuthor, err := a.authorRepo.GetByID(ctx, res.Author.ID)
+	resAuthor, err := a.getAuthor(ctx, res.Author.ID)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	res.Author = resAuthor
+	return
+}
+
+// GetByIDIncludingDeleted is GetByID for admins, it also returns soft-deleted articles
+func (a *Service) GetByIDIncludingDeleted(ctx context.Context, id int64) (res domain.Article, err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.GetByIDIncludingDeleted")
+	defer func() { tracing.End(span, err) }()
+
+	res, err = a.articleRepo.GetByIDIncludingDeleted(ctx, id)
+	if err != nil {
+		return
+	}
+
+	resAuthor, err := a.getAuthor(ctx, res.Author.ID)
 	if err != nil {
 		return domain.Article{}, err
 	}
@@ -125,18 +408,166 @@ func (a *Service) GetByID(ctx context.Context, id int64) (res domain.Article, er
 	return
 }
 
+// GetByIDs returns the articles with the given ids in the requested order, and the ids that do not exist
+// (or are deleted) in missing. Duplicated ids are looked up and returned once.
+func (a *Service) GetByIDs(ctx context.Context, ids []int64) (res []domain.Article, missing []int64, err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.GetByIDs")
+	defer func() { tracing.End(span, err) }()
+
+	unique := make([]int64, 0, len(ids))
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	found, err := a.articleRepo.GetByIDs(ctx, unique)
+	if err != nil {
+		return nil, nil, err
+	}
+	found, err = a.fillAuthorDetails(ctx, found)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byID := make(map[int64]domain.Article, len(found))
+	for _, ar := range found {
+		byID[ar.ID] = ar
+	}
+	res = make([]domain.Article, 0, len(found))
+	missing = []int64{}
+	for _, id := range unique {
+		if ar, ok := byID[id]; ok {
+			res = append(res, ar)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return res, missing, nil
+}
+
+// GetNeighbors returns the published articles right before and after the given one,
+// a nil prev or next means the article is at that boundary
+func (a *Service) GetNeighbors(ctx context.Context, id int64) (prev, next *domain.Article, err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.GetNeighbors")
+	defer func() { tracing.End(span, err) }()
+
+	ar, err := a.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	published := domain.ArticleFilter{Status: domain.StatusPublished}
+	prev, err = neighbor(a.articleRepo.GetPrevious(ctx, ar, published))
+	if err != nil {
+		return nil, nil, err
+	}
+	next, err = neighbor(a.articleRepo.GetNext(ctx, ar, published))
+	if err != nil {
+		return nil, nil, err
+	}
+	return prev, next, nil
+}
+
+// neighbor turns the not-found result of GetPrevious/GetNext into a nil article
+func neighbor(ar domain.Article, err error) (*domain.Article, error) {
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ar, nil
+}
+
+// getAuthor loads the author of a single article. Articles without an author, or whose
+// author no longer exists, get an empty author instead of failing the whole read.
+func (a *Service) getAuthor(ctx context.Context, id int64) (domain.Author, error) {
+	if id == 0 {
+		return domain.Author{}, nil
+	}
+	res, err := a.authorRepo.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Author{}, nil
+	}
+	return res, err
+}
+
 func (a *Service) Update(ctx context.Context, ar *domain.Article) (err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.Update")
+	defer func() { tracing.End(span, err) }()
+
+	if err = a.sanitize(ar); err != nil {
+		return
+	}
 	ar.UpdatedAt = time.Now()
-	return a.articleRepo.Update(ctx, ar)
+	err = a.articleRepo.Update(ctx, ar)
+	if err != nil {
+		return
+	}
+	a.publish(ctx, domain.EventArticleUpdated, *ar)
+	return
 }
 
+// IncrementViewCount records one view of the article. It is not an edit: updated_at stays as it is and
+// no event is published.
+func (a *Service) IncrementViewCount(ctx context.Context, id int64) (err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.IncrementViewCount")
+	defer func() { tracing.End(span, err) }()
+
+	return a.articleRepo.IncrementViewCount(ctx, id)
+}
+
+// UpdateStatus moves the article through its draft -> published -> archived lifecycle.
+// An unknown status is rejected with domain.ErrBadParamInput, a disallowed transition with domain.ErrConflict.
+func (a *Service) UpdateStatus(ctx context.Context, id int64, status domain.ArticleStatus) (res domain.Article, err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.UpdateStatus")
+	defer func() { tracing.End(span, err) }()
+
+	if !status.Valid() {
+		return domain.Article{}, domain.ErrBadParamInput
+	}
+
+	res, err = a.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	from := res.Status
+	if !from.CanTransitionTo(status) {
+		return domain.Article{}, domain.ErrConflict
+	}
+
+	res.Status = status
+	res.UpdatedAt = time.Now()
+	err = a.articleRepo.UpdateStatus(ctx, &res, from)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	a.publish(ctx, domain.EventArticleUpdated, res)
+
+	resAuthor, err := a.getAuthor(ctx, res.Author.ID)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	res.Author = resAuthor
+	return res, nil
+}
+
+// GetByTitle returns one of the articles titled title with its author.
+//
+// Deprecated: titles are not unique and which match is returned is unspecified, use FindByTitle.
 func (a *Service) GetByTitle(ctx context.Context, title string) (res domain.Article, err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.GetByTitle")
+	defer func() { tracing.End(span, err) }()
+
 	res, err = a.articleRepo.GetByTitle(ctx, title)
 	if err != nil {
 		return
 	}
 
-	resAuthor, err := a.authorRepo.GetByID(ctx, res.Author.ID)
+	resAuthor, err := a.getAuthor(ctx, res.Author.ID)
 	if err != nil {
 		return domain.Article{}, err
 	}
@@ -145,23 +576,234 @@ func (a *Service) GetByTitle(ctx context.Context, title string) (res domain.Arti
 	return
 }
 
+// FindByTitle returns every article titled title with its author, in id order
+func (a *Service) FindByTitle(ctx context.Context, title string) (res []domain.Article, err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.FindByTitle")
+	defer func() { tracing.End(span, err) }()
+
+	res, err = a.articleRepo.FindByTitle(ctx, title)
+	if err != nil {
+		return nil, err
+	}
+	return a.fillAuthorDetails(ctx, res)
+}
+
+func (a *Service) GetBySlug(ctx context.Context, slug string) (res domain.Article, err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.GetBySlug")
+	defer func() { tracing.End(span, err) }()
+
+	res, err = a.articleRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return
+	}
+
+	resAuthor, err := a.getAuthor(ctx, res.Author.ID)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	res.Author = resAuthor
+	return
+}
+
 func (a *Service) Store(ctx context.Context, m *domain.Article) (err error) {
-	existedArticle, _ := a.GetByTitle(ctx, m.Title) // ignore if any error
-	if existedArticle != (domain.Article{}) {
+	ctx, span := tracing.Start(ctx, "article.Service.Store")
+	defer func() { tracing.End(span, err) }()
+
+	_, taken, err := a.existingTitle(ctx, m.Title)
+	if err != nil {
+		return
+	}
+	if taken {
 		return domain.ErrConflict
 	}
+	if err = a.sanitize(m); err != nil {
+		return
+	}
+
+	// new articles always start as drafts and have to be published explicitly, without views
+	m.Status = domain.StatusDraft
+	m.ViewCount = 0
+	m.Slug, err = a.uniqueSlug(ctx, m.Title, nil)
+	if err != nil {
+		return
+	}
+	m.ID, err = a.idGenerator.NextID()
+	if err != nil {
+		return
+	}
 
+	// a concurrent Store of the same title passes the check above as well and gets the same slug,
+	// the repository reports the violated unique slug index as domain.ErrConflict
 	err = a.articleRepo.Store(ctx, m)
+	if err != nil {
+		return
+	}
+	a.publish(ctx, domain.EventArticleCreated, *m)
+	return
+}
+
+// cloneTitleSuffix is appended to the title of a cloned article
+const cloneTitleSuffix = " (Copy)"
+
+// Clone stores a new draft with the title, content, author and tags of the article id, the title gets
+// cloneTitleSuffix. It fails with domain.ErrNotFound when the source does not exist.
+func (a *Service) Clone(ctx context.Context, id int64) (res domain.Article, err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.Clone")
+	defer func() { tracing.End(span, err) }()
+
+	src, err := a.GetByID(ctx, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+
+	// id, slug, status and timestamps are assigned by Store
+	res = domain.Article{
+		Title:    src.Title + cloneTitleSuffix,
+		Content:  src.Content,
+		Author:   src.Author,
+		Metadata: maps.Clone(src.Metadata),
+		Tags:     slices.Clone(src.Tags),
+	}
+	if err = a.Store(ctx, &res); err != nil {
+		return domain.Article{}, err
+	}
+	return res, nil
+}
+
+// StoreBatch stores the articles atomically as drafts, failing with domain.ErrConflict if any title is already taken
+func (a *Service) StoreBatch(ctx context.Context, articles []*domain.Article) (err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.StoreBatch")
+	defer func() { tracing.End(span, err) }()
+
+	titles := make(map[string]bool, len(articles))
+	slugs := make(map[string]bool, len(articles))
+	for _, m := range articles {
+		if titles[m.Title] && a.uniqueTitles {
+			return domain.ErrConflict
+		}
+		titles[m.Title] = true
+		var taken bool
+		if _, taken, err = a.existingTitle(ctx, m.Title); err != nil {
+			return
+		}
+		if taken {
+			return domain.ErrConflict
+		}
+		if err = a.sanitize(m); err != nil {
+			return
+		}
+
+		m.Status = domain.StatusDraft
+		m.ViewCount = 0
+		m.Slug, err = a.uniqueSlug(ctx, m.Title, slugs)
+		if err != nil {
+			return
+		}
+		m.ID, err = a.idGenerator.NextID()
+		if err != nil {
+			return
+		}
+		slugs[m.Slug] = true
+	}
+
+	err = a.articleRepo.StoreBatch(ctx, articles)
+	if err != nil {
+		return
+	}
+	for _, m := range articles {
+		a.publish(ctx, domain.EventArticleCreated, *m)
+	}
 	return
 }
 
+// Upsert creates the article at ar.ID as a draft when no article has that id, otherwise it replaces the title,
+// content and author of the article (restoring it if it was soft-deleted); created reports which happened.
+// On success ar holds the stored article. A title taken by another article fails with domain.ErrConflict.
+func (a *Service) Upsert(ctx context.Context, ar *domain.Article) (created bool, err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.Upsert")
+	defer func() { tracing.End(span, err) }()
+
+	if ar.ID <= 0 {
+		return false, domain.ErrBadParamInput
+	}
+	existed, taken, err := a.existingTitle(ctx, ar.Title)
+	if err != nil {
+		return false, err
+	}
+	if taken && existed.ID != ar.ID {
+		return false, domain.ErrConflict
+	}
+	if err = a.sanitize(ar); err != nil {
+		return
+	}
+
+	// slug, status and created_at are only stored when the article is created, so the slug lookups are
+	// skipped when it already exists
+	exists, err := a.articleRepo.Exists(ctx, ar.ID)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	ar.Status = domain.StatusDraft
+	ar.UpdatedAt = now
+	ar.CreatedAt = now
+	if !exists {
+		ar.Slug, err = a.uniqueSlug(ctx, ar.Title, nil)
+		if err != nil {
+			return
+		}
+	}
+
+	created, err = a.articleRepo.Upsert(ctx, ar)
+	if err != nil {
+		return
+	}
+	// GetByID reads the replica, which may not have the write yet, so the row is read back from the primary
+	stored, err := a.articleRepo.GetByIDIncludingDeleted(ctx, ar.ID)
+	if err != nil {
+		return false, err
+	}
+	if stored.Author, err = a.getAuthor(ctx, stored.Author.ID); err != nil {
+		return false, err
+	}
+	*ar = stored
+
+	if created {
+		a.publish(ctx, domain.EventArticleCreated, *ar)
+	} else {
+		a.publish(ctx, domain.EventArticleUpdated, *ar)
+	}
+	return created, nil
+}
+
 func (a *Service) Delete(ctx context.Context, id int64) (err error) {
+	ctx, span := tracing.Start(ctx, "article.Service.Delete")
+	defer func() { tracing.End(span, err) }()
+
 	existedArticle, err := a.articleRepo.GetByID(ctx, id)
 	if err != nil {
 		return
 	}
-	if existedArticle == (domain.Article{}) {
+	if existedArticle.IsZero() {
 		return domain.ErrNotFound
 	}
-	return a.articleRepo.Delete(ctx, id)
+	err = a.articleRepo.Delete(ctx, id)
+	if err != nil {
+		return
+	}
+	a.publish(ctx, domain.EventArticleDeleted, existedArticle)
+	return
+}
+
+// publish notifies the subscribers after a successful write, a delivery failure does not undo the write
+func (a *Service) publish(ctx context.Context, eventType string, ar domain.Article) {
+	event := domain.ArticleEvent{
+		Type:       eventType,
+		ArticleID:  ar.ID,
+		Article:    ar,
+		OccurredAt: time.Now(),
+	}
+	if err := a.publisher.Publish(ctx, event); err != nil {
+		log.Error("Failed to publish "+eventType+" event:", err)
+	}
 }
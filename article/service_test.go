@@ -3,14 +3,19 @@ package article_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/bxcodec/go-clean-arch/article"
 	"github.com/bxcodec/go-clean-arch/article/mocks"
 	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/event"
 )
 
 func TestFetchArticle(t *testing.T) {
@@ -18,14 +23,16 @@ func TestFetchArticle(t *testing.T) {
 	mockArticle := domain.Article{
 		Title:   "Hello",
 		Content: "Content",
+		Author:  domain.Author{ID: 1},
 	}
 
 	mockListArtilce := make([]domain.Article, 0)
 	mockListArtilce = append(mockListArtilce, mockArticle)
 
 	t.Run("success", func(t *testing.T) {
-		mockArticleRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"),
-			mock.AnythingOfType("int64")).Return(mockListArtilce, "next-cursor", nil).Once()
+		mockArticleRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"), domain.PageNext,
+			mock.AnythingOfType("int64"), domain.ArticleFilter{Status: domain.StatusPublished}).
+			Return(mockListArtilce, domain.PageCursors{Next: "next-cursor", Prev: "prev-cursor"}, nil).Once()
 		mockAuthor := domain.Author{
 			ID:   1,
 			Name: "Iman Tumorang",
@@ -34,29 +41,34 @@ func TestFetchArticle(t *testing.T) {
 		mockAuthorrepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockAuthor, nil)
 		u := article.NewService(mockArticleRepo, mockAuthorrepo)
 		num := int64(1)
-		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
-		cursorExpected := "next-cursor"
-		assert.Equal(t, cursorExpected, nextCursor)
-		assert.NotEmpty(t, nextCursor)
+		cursor := u.EncodeCursor("12")
+		list, page, err := u.Fetch(context.TODO(), cursor, domain.PageNext, num, domain.ArticleFilter{Status: domain.StatusPublished})
+		assert.NotEmpty(t, page.Next)
 		assert.NoError(t, err)
 		assert.Len(t, list, len(mockListArtilce))
 
+		rawCursor, err := u.DecodeCursor(page.Next)
+		assert.NoError(t, err)
+		assert.Equal(t, "next-cursor", rawCursor)
+		rawCursor, err = u.DecodeCursor(page.Prev)
+		assert.NoError(t, err)
+		assert.Equal(t, "prev-cursor", rawCursor)
+
 		mockArticleRepo.AssertExpectations(t)
 		mockAuthorrepo.AssertExpectations(t)
 	})
 
 	t.Run("error-failed", func(t *testing.T) {
-		mockArticleRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"),
-			mock.AnythingOfType("int64")).Return(nil, "", errors.New("Unexpexted Error")).Once()
+		mockArticleRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"), domain.PageNext,
+			mock.AnythingOfType("int64"), mock.Anything).Return(nil, domain.PageCursors{}, errors.New("Unexpexted Error")).Once()
 
 		mockAuthorrepo := new(mocks.AuthorRepository)
 		u := article.NewService(mockArticleRepo, mockAuthorrepo)
 		num := int64(1)
-		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
+		cursor := u.EncodeCursor("12")
+		list, page, err := u.Fetch(context.TODO(), cursor, domain.PageNext, num, domain.ArticleFilter{Status: domain.StatusPublished})
 
-		assert.Empty(t, nextCursor)
+		assert.Empty(t, page)
 		assert.Error(t, err)
 		assert.Len(t, list, 0)
 		mockArticleRepo.AssertExpectations(t)
@@ -64,6 +76,106 @@ func TestFetchArticle(t *testing.T) {
 	})
 }
 
+func TestFetchArticleAuthorless(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockArticleRepo.On("Fetch", mock.Anything, mock.Anything, domain.PageNext, int64(10), domain.ArticleFilter{}).
+		Return([]domain.Article{
+			{ID: 1, Title: "no author"},
+			{ID: 2, Title: "author", Author: domain.Author{ID: 7}},
+			{ID: 3, Title: "deleted author", Author: domain.Author{ID: 8}},
+		}, domain.PageCursors{}, nil).Once()
+	mockAuthorRepo := new(mocks.AuthorRepository)
+	mockAuthorRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Author{ID: 7, Name: "Iman Tumorang"}, nil).Once()
+	mockAuthorRepo.On("GetByID", mock.Anything, int64(8)).Return(domain.Author{}, domain.ErrNotFound).Once()
+
+	// an article without an author, or whose author is gone, gets an empty author instead of failing the list
+	u := article.NewService(mockArticleRepo, mockAuthorRepo)
+	list, _, err := u.Fetch(context.TODO(), "", domain.PageNext, 10, domain.ArticleFilter{})
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	assert.Equal(t, domain.Author{}, list[0].Author)
+	assert.Equal(t, "Iman Tumorang", list[1].Author.Name)
+	assert.Equal(t, domain.Author{}, list[2].Author)
+	mockAuthorRepo.AssertNotCalled(t, "GetByID", mock.Anything, int64(0))
+
+	mockArticleRepo.On("GetByTitle", mock.Anything, "no author").Return(domain.Article{ID: 1, Title: "no author"}, nil).Once()
+	ar, err := u.GetByTitle(context.TODO(), "no author")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), ar.ID)
+
+	mockArticleRepo.AssertExpectations(t)
+	mockAuthorRepo.AssertExpectations(t)
+}
+
+func TestFetchArticleCursor(t *testing.T) {
+	t.Run("round-trip", func(t *testing.T) {
+		u := article.NewService(new(mocks.ArticleRepository), new(mocks.AuthorRepository), article.WithCursorSecret([]byte("secret")))
+
+		token := u.EncodeCursor("MjAyNC0wMS0wMlQxNTowNDowNVo=")
+		assert.NotContains(t, token, "MjAyNC0wMS0wMlQxNTowNDowNVo=")
+
+		raw, err := u.DecodeCursor(token)
+		assert.NoError(t, err)
+		assert.Equal(t, "MjAyNC0wMS0wMlQxNTowNDowNVo=", raw)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		u := article.NewService(mockArticleRepo, mockAuthorrepo, article.WithCursorSecret([]byte("secret")))
+		other := article.NewService(mockArticleRepo, mockAuthorrepo, article.WithCursorSecret([]byte("another-secret")))
+
+		for _, cursor := range []string{"2", other.EncodeCursor("12"), u.EncodeCursor("12") + "x"} {
+			list, page, err := u.Fetch(context.TODO(), cursor, domain.PageNext, int64(1), domain.ArticleFilter{})
+
+			assert.ErrorIs(t, err, domain.ErrBadParamInput)
+			assert.Empty(t, page)
+			assert.Len(t, list, 0)
+		}
+		mockArticleRepo.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("empty-cursor-is-start", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Fetch", mock.Anything, "", domain.PageNext, int64(1), domain.ArticleFilter{}).Return([]domain.Article{}, domain.PageCursors{}, nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		u := article.NewService(mockArticleRepo, mockAuthorrepo)
+
+		list, page, err := u.Fetch(context.TODO(), "", domain.PageNext, int64(1), domain.ArticleFilter{})
+
+		assert.NoError(t, err)
+		assert.Empty(t, page)
+		assert.Len(t, list, 0)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("previous-page", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Fetch", mock.Anything, "12", domain.PagePrev, int64(1), domain.ArticleFilter{}).
+			Return([]domain.Article{}, domain.PageCursors{Prev: "11"}, nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		_, page, err := u.Fetch(context.TODO(), u.EncodeCursor("12"), domain.PagePrev, int64(1), domain.ArticleFilter{})
+
+		require.NoError(t, err)
+		assert.Empty(t, page.Next)
+		raw, err := u.DecodeCursor(page.Prev)
+		require.NoError(t, err)
+		assert.Equal(t, "11", raw)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown-direction", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		_, _, err := u.Fetch(context.TODO(), "", domain.PageDirection("sideways"), int64(1), domain.ArticleFilter{})
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		mockArticleRepo.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
 func TestGetByID(t *testing.T) {
 	mockArticleRepo := new(mocks.ArticleRepository)
 	mockArticle := domain.Article{
@@ -76,15 +188,47 @@ func TestGetByID(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
+		withAuthor := mockArticle
+		withAuthor.Author = domain.Author{ID: mockAuthor.ID}
+		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(withAuthor, nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, mockAuthor.ID).Return(mockAuthor, nil)
+		u := article.NewService(mockArticleRepo, mockAuthorrepo)
+
+		a, err := u.GetByID(context.TODO(), mockArticle.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockAuthor, a.Author)
+
+		mockArticleRepo.AssertExpectations(t)
+		mockAuthorrepo.AssertExpectations(t)
+	})
+	t.Run("zero-author-id-skips-lookup", func(t *testing.T) {
 		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockArticle, nil).Once()
 		mockAuthorrepo := new(mocks.AuthorRepository)
-		mockAuthorrepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockAuthor, nil)
 		u := article.NewService(mockArticleRepo, mockAuthorrepo)
 
 		a, err := u.GetByID(context.TODO(), mockArticle.ID)
 
 		assert.NoError(t, err)
-		assert.NotNil(t, a)
+		assert.Equal(t, domain.Author{}, a.Author)
+
+		mockArticleRepo.AssertExpectations(t)
+		mockAuthorrepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+	t.Run("missing-author", func(t *testing.T) {
+		withAuthor := mockArticle
+		withAuthor.Author = domain.Author{ID: 2}
+		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(withAuthor, nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(2)).Return(domain.Author{}, domain.ErrNotFound)
+		u := article.NewService(mockArticleRepo, mockAuthorrepo)
+
+		a, err := u.GetByID(context.TODO(), mockArticle.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockArticle.Title, a.Title)
+		assert.Equal(t, domain.Author{}, a.Author)
 
 		mockArticleRepo.AssertExpectations(t)
 		mockAuthorrepo.AssertExpectations(t)
@@ -105,6 +249,96 @@ func TestGetByID(t *testing.T) {
 	})
 }
 
+func TestGetByIDs(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockAuthorrepo := new(mocks.AuthorRepository)
+	author := domain.Author{ID: 1, Name: "Iman Tumorang"}
+
+	// the repository returns id order, the service restores the requested order and reports 999
+	mockArticleRepo.On("GetByIDs", mock.Anything, []int64{3, 999, 1}).Return([]domain.Article{
+		{ID: 1, Title: "one", Author: domain.Author{ID: 1}},
+		{ID: 3, Title: "three", Author: domain.Author{ID: 1}},
+	}, nil).Once()
+	mockAuthorrepo.On("GetByID", mock.Anything, int64(1)).Return(author, nil)
+	u := article.NewService(mockArticleRepo, mockAuthorrepo)
+
+	res, missing, err := u.GetByIDs(context.TODO(), []int64{3, 999, 1, 3})
+
+	assert.NoError(t, err)
+	if assert.Len(t, res, 2) {
+		assert.Equal(t, int64(3), res[0].ID)
+		assert.Equal(t, int64(1), res[1].ID)
+		assert.Equal(t, author, res[0].Author)
+	}
+	assert.Equal(t, []int64{999}, missing)
+	mockArticleRepo.AssertExpectations(t)
+}
+
+func TestFetchRecent(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockAuthorrepo := new(mocks.AuthorRepository)
+	author := domain.Author{ID: 1, Name: "Iman Tumorang"}
+
+	// only published articles are recent ones
+	mockArticleRepo.On("FetchRecent", mock.Anything, int64(2), domain.ArticleFilter{Status: domain.StatusPublished}).
+		Return([]domain.Article{{ID: 2, Author: domain.Author{ID: 1}}, {ID: 1, Author: domain.Author{ID: 1}}}, nil).Once()
+	mockAuthorrepo.On("GetByID", mock.Anything, int64(1)).Return(author, nil)
+	u := article.NewService(mockArticleRepo, mockAuthorrepo)
+
+	res, err := u.FetchRecent(context.TODO(), 2)
+
+	assert.NoError(t, err)
+	if assert.Len(t, res, 2) {
+		assert.Equal(t, int64(2), res[0].ID)
+		assert.Equal(t, author, res[1].Author)
+	}
+	mockArticleRepo.AssertExpectations(t)
+}
+
+func TestClone(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	author := domain.Author{ID: 1, Name: "Iman Tumorang"}
+
+	t.Run("success", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{
+			ID: 7, Title: "Hello", Slug: "hello", Content: "Content", Status: domain.StatusPublished,
+			Author: domain.Author{ID: 1}, CreatedAt: createdAt, UpdatedAt: createdAt,
+		}, nil).Once()
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(1)).Return(author, nil)
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello (Copy)").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, mock.AnythingOfType("string")).Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Article).ID = 8
+		}).Return(nil).Once()
+		u := article.NewService(mockArticleRepo, mockAuthorrepo)
+
+		res, err := u.Clone(context.TODO(), 7)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(8), res.ID)
+		assert.Equal(t, "Hello (Copy)", res.Title)
+		assert.Equal(t, "Content", res.Content)
+		assert.Equal(t, domain.StatusDraft, res.Status)
+		assert.Equal(t, author, res.Author)
+		assert.NotEqual(t, "hello", res.Slug)
+		assert.True(t, res.CreatedAt.IsZero())
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("missing-source", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{}, domain.ErrNotFound).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		_, err := u.Clone(context.TODO(), 7)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockArticleRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
 func TestStore(t *testing.T) {
 	mockArticleRepo := new(mocks.ArticleRepository)
 	mockArticle := domain.Article{
@@ -116,6 +350,7 @@ func TestStore(t *testing.T) {
 		tempMockArticle := mockArticle
 		tempMockArticle.ID = 0
 		mockArticleRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello").Return(domain.Article{}, domain.ErrNotFound).Once()
 		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
 
 		mockAuthorrepo := new(mocks.AuthorRepository)
@@ -125,25 +360,325 @@ func TestStore(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.Equal(t, mockArticle.Title, tempMockArticle.Title)
+		assert.Equal(t, "hello", tempMockArticle.Slug)
+		assert.Equal(t, domain.StatusDraft, tempMockArticle.Status)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("generated-id", func(t *testing.T) {
+		tempMockArticle := mockArticle
+		mockArticleRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.MatchedBy(func(a *domain.Article) bool { return a.ID != 0 })).Return(nil).Once()
+
+		gen, err := article.NewSnowflake(1)
+		require.NoError(t, err)
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository), article.WithIDGenerator(gen))
+
+		err = u.Store(context.TODO(), &tempMockArticle)
+
+		assert.NoError(t, err)
+		assert.NotZero(t, tempMockArticle.ID)
 		mockArticleRepo.AssertExpectations(t)
 	})
 	t.Run("existing-title", func(t *testing.T) {
 		existingArticle := mockArticle
+		existingArticle.ID = 1
 		mockArticleRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(existingArticle, nil).Once()
-		mockAuthor := domain.Author{
-			ID:   1,
-			Name: "Iman Tumorang",
-		}
 		mockAuthorrepo := new(mocks.AuthorRepository)
-		mockAuthorrepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockAuthor, nil)
 
 		u := article.NewService(mockArticleRepo, mockAuthorrepo)
 
 		err := u.Store(context.TODO(), &mockArticle)
 
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		mockArticleRepo.AssertExpectations(t)
+		mockAuthorrepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+	t.Run("title-lookup-error", func(t *testing.T) {
+		// the title is not known to be free, so the article is not stored
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(domain.Article{}, errors.New("connection refused")).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		tempMockArticle := mockArticle
+		err := u.Store(context.TODO(), &tempMockArticle)
+
+		assert.EqualError(t, err, "connection refused")
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
+
+func TestStoreTags(t *testing.T) {
+	t.Run("normalized", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		ar := domain.Article{Title: "Hello", Content: "Content", Tags: []string{" web", "go", "", "web "}}
+		require.NoError(t, u.Store(context.TODO(), &ar))
+		assert.Equal(t, []string{"go", "web"}, ar.Tags)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	tooMany := make([]string, 11)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("tag%d", i)
+	}
+	tests := []struct {
+		name string
+		tags []string
+	}{
+		{name: "too-many", tags: tooMany},
+		{name: "too-long", tags: []string{strings.Repeat("x", 65)}},
+		{name: "comma", tags: []string{"go,web"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockArticleRepo := new(mocks.ArticleRepository)
+			mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+			u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+			err := u.Store(context.TODO(), &domain.Article{Title: "Hello", Content: "Content", Tags: tt.tags})
+			assert.ErrorIs(t, err, domain.ErrBadParamInput)
+			mockArticleRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetNeighbors(t *testing.T) {
+	published := domain.ArticleFilter{Status: domain.StatusPublished}
+	current := domain.Article{ID: 5, Title: "Current"}
+
+	t.Run("last-article", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(5)).Return(current, nil).Once()
+		mockArticleRepo.On("GetPrevious", mock.Anything, current, published).Return(domain.Article{ID: 4}, nil).Once()
+		mockArticleRepo.On("GetNext", mock.Anything, current, published).Return(domain.Article{}, domain.ErrNotFound).Once()
+
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+		prev, next, err := u.GetNeighbors(context.TODO(), 5)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), prev.ID)
+		assert.Nil(t, next)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown-article", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(5)).Return(domain.Article{}, domain.ErrNotFound).Once()
+
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+		_, _, err := u.GetNeighbors(context.TODO(), 5)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
+
+func TestStoreTitleUniqueness(t *testing.T) {
+	t.Run("pre-check", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		// the existing article has no author, the check does not depend on loading one
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(domain.Article{ID: 3, Title: "Hello"}, nil).Once()
+		mockAuthorRepo := new(mocks.AuthorRepository)
+
+		u := article.NewService(mockArticleRepo, mockAuthorRepo)
+		err := u.Store(context.TODO(), &domain.Article{Title: "Hello", Content: "Content"})
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		mockArticleRepo.AssertExpectations(t)
+		mockArticleRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+		mockAuthorRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("race-fallback", func(t *testing.T) {
+		// another request stores the same title between the pre-check and the insert
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.Anything).Return(domain.ErrConflict).Once()
+		mockPublisher := new(mocks.EventPublisher)
+
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository), article.WithEventPublisher(mockPublisher))
+		err := u.Store(context.TODO(), &domain.Article{Title: "Hello", Content: "Content"})
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		mockArticleRepo.AssertExpectations(t)
+		mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+
+	t.Run("not-unique", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello").Return(domain.Article{ID: 3}, nil).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello-2").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.Anything).Return(nil).Once()
+
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository), article.WithUniqueTitles(false))
+		ar := domain.Article{Title: "Hello", Content: "Content"}
+		err := u.Store(context.TODO(), &ar)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "hello-2", ar.Slug)
+		mockArticleRepo.AssertExpectations(t)
+		mockArticleRepo.AssertNotCalled(t, "GetByTitle", mock.Anything, mock.Anything)
+	})
+}
+
+func TestStoreBatch(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		batch := []*domain.Article{
+			{Title: "Hello", Content: "Content"},
+			{Title: "Hello!", Content: "Content"},
+		}
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(domain.Article{}, domain.ErrNotFound).Twice()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello-2").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("StoreBatch", mock.Anything, batch).Return(nil).Once()
+		mockPublisher := new(mocks.EventPublisher)
+		mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(e domain.ArticleEvent) bool {
+			return e.Type == domain.EventArticleCreated
+		})).Return(nil).Twice()
+
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository), article.WithEventPublisher(mockPublisher))
+		err := u.StoreBatch(context.TODO(), batch)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", batch[0].Slug)
+		assert.Equal(t, "hello-2", batch[1].Slug)
+		assert.Equal(t, domain.StatusDraft, batch[1].Status)
+		mockArticleRepo.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("duplicate-title-in-batch", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+		err := u.StoreBatch(context.TODO(), []*domain.Article{{Title: "Hello"}, {Title: "Hello"}})
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		mockArticleRepo.AssertNotCalled(t, "StoreBatch", mock.Anything, mock.Anything)
+	})
+}
+
+func TestStoreSlug(t *testing.T) {
+	t.Run("duplicate-slug-gets-suffix", func(t *testing.T) {
+		ar := domain.Article{Title: "Hello, World!", Content: "Content"}
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, ar.Title).Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello-world").Return(domain.Article{ID: 1}, nil).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello-world-2").Return(domain.Article{ID: 2}, nil).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello-world-3").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Store", mock.Anything, &ar).Return(nil).Once()
+
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		err := u.Store(context.TODO(), &ar)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello-world-3", ar.Slug)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("lookup-error", func(t *testing.T) {
+		ar := domain.Article{Title: "Hello", Content: "Content"}
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, ar.Title).Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello").Return(domain.Article{}, errors.New("Unexpected")).Once()
+
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		err := u.Store(context.TODO(), &ar)
 		assert.Error(t, err)
+		mockArticleRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		title    string
+		expected string
+	}{
+		{title: "Hello World", expected: "hello-world"},
+		{title: "  Go: Clean Architecture!  ", expected: "go-clean-architecture"},
+		{title: "Release 2.0 -- notes", expected: "release-2-0-notes"},
+		{title: "整洁架构 Go", expected: "整洁架构-go"},
+		{title: "!!!", expected: "article"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			assert.Equal(t, tt.expected, article.Slugify(tt.title))
+		})
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	transitions := []struct {
+		from domain.ArticleStatus
+		to   domain.ArticleStatus
+		err  error
+	}{
+		{from: domain.StatusDraft, to: domain.StatusPublished},
+		{from: domain.StatusPublished, to: domain.StatusArchived},
+		{from: domain.StatusDraft, to: domain.StatusArchived, err: domain.ErrConflict},
+		{from: domain.StatusDraft, to: domain.StatusDraft, err: domain.ErrConflict},
+		{from: domain.StatusPublished, to: domain.StatusDraft, err: domain.ErrConflict},
+		{from: domain.StatusPublished, to: domain.StatusPublished, err: domain.ErrConflict},
+		{from: domain.StatusArchived, to: domain.StatusDraft, err: domain.ErrConflict},
+		{from: domain.StatusArchived, to: domain.StatusPublished, err: domain.ErrConflict},
+		{from: domain.StatusArchived, to: domain.StatusArchived, err: domain.ErrConflict},
+	}
+
+	for _, tt := range transitions {
+		t.Run(string(tt.from)+"-to-"+string(tt.to), func(t *testing.T) {
+			mockArticleRepo := new(mocks.ArticleRepository)
+			mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1, Status: tt.from}, nil).Once()
+			if tt.err == nil {
+				mockArticleRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool {
+					return ar.ID == 1 && ar.Status == tt.to
+				}), tt.from).Return(nil).Once()
+			}
+			u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+			res, err := u.UpdateStatus(context.TODO(), 1, tt.to)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				mockArticleRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.to, res.Status)
+			}
+			mockArticleRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("unknown-status", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		_, err := u.UpdateStatus(context.TODO(), 1, "deleted")
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		mockArticleRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("concurrent-change", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1, Status: domain.StatusDraft}, nil).Once()
+		mockArticleRepo.On("UpdateStatus", mock.Anything, mock.Anything, domain.StatusDraft).Return(domain.ErrConflict).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		_, err := u.UpdateStatus(context.TODO(), 1, domain.StatusPublished)
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
 		mockArticleRepo.AssertExpectations(t)
-		mockAuthorrepo.AssertExpectations(t)
 	})
 }
 
@@ -213,3 +748,159 @@ func TestUpdate(t *testing.T) {
 		mockArticleRepo.AssertExpectations(t)
 	})
 }
+
+func TestUpsert(t *testing.T) {
+	stored := domain.Article{ID: 7, Title: "Hello", Content: "Content", Author: domain.Author{ID: 1}}
+
+	t.Run("existing-skips-slug-lookup", func(t *testing.T) {
+		ar := domain.Article{ID: 7, Title: "Hello", Content: "Content"}
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, ar.Title).Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Exists", mock.Anything, int64(7)).Return(true, nil).Once()
+		mockArticleRepo.On("Upsert", mock.Anything, &ar).Return(false, nil).Once()
+		mockArticleRepo.On("GetByIDIncludingDeleted", mock.Anything, int64(7)).Return(stored, nil).Once()
+		mockAuthorRepo := new(mocks.AuthorRepository)
+		mockAuthorRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Author{ID: 1}, nil).Once()
+
+		u := article.NewService(mockArticleRepo, mockAuthorRepo)
+
+		created, err := u.Upsert(context.TODO(), &ar)
+		require.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, stored, ar)
+		mockArticleRepo.AssertNotCalled(t, "GetBySlug", mock.Anything, mock.Anything)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("missing-gets-slug", func(t *testing.T) {
+		ar := domain.Article{ID: 7, Title: "Hello", Content: "Content"}
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, ar.Title).Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Exists", mock.Anything, int64(7)).Return(false, nil).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(a *domain.Article) bool {
+			return a.Slug == "hello"
+		})).Return(true, nil).Once()
+		mockArticleRepo.On("GetByIDIncludingDeleted", mock.Anything, int64(7)).Return(stored, nil).Once()
+		mockAuthorRepo := new(mocks.AuthorRepository)
+		mockAuthorRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Author{ID: 1}, nil).Once()
+
+		u := article.NewService(mockArticleRepo, mockAuthorRepo)
+
+		created, err := u.Upsert(context.TODO(), &ar)
+		require.NoError(t, err)
+		assert.True(t, created)
+		mockArticleRepo.AssertExpectations(t)
+		// the replica read of GetByID could miss the article that was just created
+		mockArticleRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("exists-error", func(t *testing.T) {
+		ar := domain.Article{ID: 7, Title: "Hello", Content: "Content"}
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, ar.Title).Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Exists", mock.Anything, int64(7)).Return(false, errors.New("Unexpected")).Once()
+
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		_, err := u.Upsert(context.TODO(), &ar)
+		assert.Error(t, err)
+		mockArticleRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
+
+func TestPublishEvents(t *testing.T) {
+	mockArticle := domain.Article{
+		ID:      23,
+		Title:   "Hello",
+		Content: "Content",
+	}
+
+	isEvent := func(eventType string) interface{} {
+		return mock.MatchedBy(func(e domain.ArticleEvent) bool {
+			return e.Type == eventType && e.ArticleID == mockArticle.ID && e.Article.Title == mockArticle.Title
+		})
+	}
+
+	t.Run("created", func(t *testing.T) {
+		tempMockArticle := mockArticle
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		// the repository assigns the id
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Article).ID = mockArticle.ID
+		}).Return(nil).Once()
+		mockPublisher := mocks.NewEventPublisher(t)
+		mockPublisher.On("Publish", mock.Anything, isEvent(domain.EventArticleCreated)).Return(nil).Once()
+
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository), article.WithEventPublisher(mockPublisher))
+
+		err := u.Store(context.TODO(), &tempMockArticle)
+		assert.NoError(t, err)
+	})
+
+	t.Run("updated", func(t *testing.T) {
+		tempMockArticle := mockArticle
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Update", mock.Anything, &tempMockArticle).Return(nil).Once()
+		mockPublisher := mocks.NewEventPublisher(t)
+		mockPublisher.On("Publish", mock.Anything, isEvent(domain.EventArticleUpdated)).Return(nil).Once()
+
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository), article.WithEventPublisher(mockPublisher))
+
+		err := u.Update(context.TODO(), &tempMockArticle)
+		assert.NoError(t, err)
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, mockArticle.ID).Return(mockArticle, nil).Once()
+		mockArticleRepo.On("Delete", mock.Anything, mockArticle.ID).Return(nil).Once()
+		mockPublisher := mocks.NewEventPublisher(t)
+		mockPublisher.On("Publish", mock.Anything, isEvent(domain.EventArticleDeleted)).Return(errors.New("webhook down")).Once()
+
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository), article.WithEventPublisher(mockPublisher))
+
+		err := u.Delete(context.TODO(), mockArticle.ID)
+		assert.NoError(t, err, "a failed delivery does not fail the write")
+	})
+
+	t.Run("slow-subscriber-does-not-delay-store", func(t *testing.T) {
+		tempMockArticle := mockArticle
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Article).ID = mockArticle.ID
+		}).Return(nil).Once()
+		release := make(chan time.Time)
+		mockPublisher := mocks.NewEventPublisher(t)
+		mockPublisher.On("Publish", mock.Anything, isEvent(domain.EventArticleCreated)).WaitUntil(release).Return(nil).Once()
+		publisher := event.NewAsyncPublisher(mockPublisher, 10, time.Minute)
+
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository), article.WithEventPublisher(publisher))
+
+		start := time.Now()
+		err := u.Store(context.TODO(), &tempMockArticle)
+		assert.NoError(t, err)
+		assert.Less(t, time.Since(start), time.Second, "Store returned before the subscriber answered")
+
+		close(release)
+		require.NoError(t, publisher.Close(context.Background()))
+	})
+
+	t.Run("failed-write-is-not-published", func(t *testing.T) {
+		tempMockArticle := mockArticle
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Update", mock.Anything, &tempMockArticle).Return(errors.New("Unexpected Error")).Once()
+		mockPublisher := mocks.NewEventPublisher(t)
+
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository), article.WithEventPublisher(mockPublisher))
+
+		err := u.Update(context.TODO(), &tempMockArticle)
+		assert.Error(t, err)
+		mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+}
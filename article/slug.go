@@ -0,0 +1,55 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// defaultSlug is used when the title has no letter or digit to build a slug from
+const defaultSlug = "article"
+
+// Slugify turns a title into a lowercase, hyphen separated slug
+func Slugify(title string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(r)
+			continue
+		}
+		pendingHyphen = true
+	}
+	if b.Len() == 0 {
+		return defaultSlug
+	}
+	return b.String()
+}
+
+// uniqueSlug returns the slug of title, suffixed with -2, -3, ... until it is neither taken nor reserved
+func (a *Service) uniqueSlug(ctx context.Context, title string, reserved map[string]bool) (string, error) {
+	base := Slugify(title)
+	slug := base
+	for i := 2; ; i++ {
+		if reserved[slug] {
+			slug = base + "-" + strconv.Itoa(i)
+			continue
+		}
+		_, err := a.articleRepo.GetBySlug(ctx, slug)
+		if errors.Is(err, domain.ErrNotFound) {
+			return slug, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		slug = base + "-" + strconv.Itoa(i)
+	}
+}
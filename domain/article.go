@@ -1,15 +1,140 @@
 package domain
 
 import (
+	"encoding/json"
+	"reflect"
 	"time"
 )
 
+// ArticleStatus is the lifecycle state of an article
+type ArticleStatus string
+
+const (
+	StatusDraft     ArticleStatus = "draft"
+	StatusPublished ArticleStatus = "published"
+	StatusArchived  ArticleStatus = "archived"
+)
+
+// articleTransitions lists the states each state may move to
+var articleTransitions = map[ArticleStatus][]ArticleStatus{
+	StatusDraft:     {StatusPublished},
+	StatusPublished: {StatusArchived},
+}
+
+// Valid reports whether s is a known status
+func (s ArticleStatus) Valid() bool {
+	switch s {
+	case StatusDraft, StatusPublished, StatusArchived:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo reports whether an article in state s may move to state to
+func (s ArticleStatus) CanTransitionTo(to ArticleStatus) bool {
+	for _, next := range articleTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
 // Article is representing the Article data struct
 type Article struct {
-	ID        int64     `json:"id"`
-	Title     string    `json:"title" validate:"required"`
-	Content   string    `json:"content" validate:"required"`
-	Author    Author    `json:"author"`
-	UpdatedAt time.Time `json:"updated_at"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        int64         `json:"id"`
+	Title     string        `json:"title" validate:"required"`
+	Slug      string        `json:"slug"`
+	Status    ArticleStatus `json:"status"`
+	Content   string        `json:"content" validate:"required"`
+	Author    Author        `json:"author"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	CreatedAt time.Time     `json:"created_at"`
+	// DeletedAt is set once the article is soft-deleted, deleted articles are hidden from every lookup
+	// except GetByIDIncludingDeleted
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Metadata holds free-form extra fields such as a cover image URL or SEO fields, stored as a JSON object
+	Metadata map[string]any `json:"metadata,omitempty"`
+	// ViewCount is how many times the article was viewed, it is only changed by IncrementViewCount
+	ViewCount int `json:"view_count"`
+	// Tags label the article for the ?tags= filter, the service keeps them trimmed, unique and sorted
+	Tags []string `json:"tags,omitempty"`
+}
+
+// IsZero reports whether a is the zero Article. Articles cannot be compared with == since Metadata is a map.
+func (a Article) IsZero() bool {
+	return reflect.ValueOf(a).IsZero()
+}
+
+// UnmarshalJSON accepts the id both as a number and as a string, clients that receive string ids
+// (api.string_ids) send them back unchanged
+func (a *Article) UnmarshalJSON(data []byte) error {
+	type article Article
+	aux := struct {
+		*article
+		ID json.Number `json:"id"`
+	}{article: (*article)(a)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	a.ID = 0
+	if aux.ID != "" {
+		id, err := aux.ID.Int64()
+		if err != nil {
+			return err
+		}
+		a.ID = id
+	}
+	return nil
+}
+
+// ArticleFilter narrows down the articles returned by Fetch and Count
+type ArticleFilter struct {
+	Status ArticleStatus
+	// AuthorID keeps only the articles of that author, zero means every author
+	AuthorID int64
+	// CreatedFrom and CreatedTo bound created_at inclusively, a zero value leaves that side open
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	// Tags keeps only the articles carrying the tags, TagMatch tells whether all of them or any one is enough
+	Tags     []string
+	TagMatch TagMatch
+}
+
+// TagMatch is how ArticleFilter.Tags combine
+type TagMatch string
+
+const (
+	// TagMatchAny keeps the articles carrying at least one of the tags (OR), it is the default
+	TagMatchAny TagMatch = "any"
+	// TagMatchAll keeps the articles carrying every one of the tags (AND)
+	TagMatchAll TagMatch = "all"
+)
+
+// Valid reports whether m is a known match mode
+func (m TagMatch) Valid() bool {
+	return m == TagMatchAny || m == TagMatchAll
+}
+
+// PageDirection tells Fetch on which side of the cursor the page is read
+type PageDirection string
+
+const (
+	// PageNext reads the articles after the cursor, it is the default
+	PageNext PageDirection = "next"
+	// PagePrev reads the articles before the cursor, an empty cursor reads the last page.
+	// The page is still returned in ascending order.
+	PagePrev PageDirection = "prev"
+)
+
+// Valid reports whether d is a known direction
+func (d PageDirection) Valid() bool {
+	return d == PageNext || d == PagePrev
+}
+
+// PageCursors are the cursors around a page returned by Fetch: Next is read with PageNext and Prev with
+// PagePrev. An empty cursor means there is no page on that side.
+type PageCursors struct {
+	Next string
+	Prev string
 }
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Audited operations
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+// AuditEntry records one mutation of an article: who did it, when, and the article before and after.
+// Before is nil for a create and After is nil for a delete.
+type AuditEntry struct {
+	OccurredAt time.Time `json:"occurred_at"`
+	Actor      string    `json:"actor"`
+	Operation  string    `json:"operation"`
+	ArticleID  int64     `json:"article_id"`
+	Before     *Article  `json:"before,omitempty"`
+	After      *Article  `json:"after,omitempty"`
+}
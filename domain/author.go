@@ -7,3 +7,9 @@ type Author struct {
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
+
+// AuthorStat is an author with the number of articles it wrote, deleted articles are not counted
+type AuthorStat struct {
+	Author       Author `json:"author"`
+	ArticleCount int64  `json:"article_count"`
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// Article lifecycle event types
+const (
+	EventArticleCreated = "article.created"
+	EventArticleUpdated = "article.updated"
+	EventArticleDeleted = "article.deleted"
+)
+
+// ArticleEvent is representing a change that happened to an article
+type ArticleEvent struct {
+	Type       string    `json:"type"`
+	ArticleID  int64     `json:"article_id"`
+	Article    Article   `json:"article"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
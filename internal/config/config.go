@@ -0,0 +1,379 @@
+// Package config loads the application configuration from viper into typed structs,
+// applying defaults for omitted keys and validating the keys required at startup.
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+// Config is the whole application configuration, see configs/config.example.yaml.
+// Durations are written in the config file as a number of seconds or as a Go duration string ("1m30s").
+type Config struct {
+	Debug      bool             `mapstructure:"debug"`
+	Server     ServerConfig     `mapstructure:"server"`
+	Context    ContextConfig    `mapstructure:"context"`
+	Log        LogConfig        `mapstructure:"log"`
+	Admin      AdminConfig      `mapstructure:"admin"`
+	Auth       AuthConfig       `mapstructure:"auth"`
+	API        APIConfig        `mapstructure:"api"`
+	Features   FeaturesConfig   `mapstructure:"features"`
+	CORS       CORSConfig       `mapstructure:"cors"`
+	Pagination PaginationConfig `mapstructure:"pagination"`
+	Validation ValidationConfig `mapstructure:"validation"`
+	Import     ImportConfig     `mapstructure:"import"`
+	Events     EventsConfig     `mapstructure:"events"`
+	Cursor     CursorConfig     `mapstructure:"cursor"`
+	Breaker    BreakerConfig    `mapstructure:"breaker"`
+	Cache      CacheConfig      `mapstructure:"cache"`
+	Content    ContentConfig    `mapstructure:"content"`
+	Audit      AuditConfig      `mapstructure:"audit"`
+	Health     HealthConfig     `mapstructure:"health"`
+	ID         IDConfig         `mapstructure:"id"`
+	Database   DatabaseConfig   `mapstructure:"database"`
+}
+
+type ServerConfig struct {
+	Address        string        `mapstructure:"address"`
+	BasePath       string        `mapstructure:"base_path"`
+	TrustedProxies []string      `mapstructure:"trusted_proxies"`
+	DrainTimeout   time.Duration `mapstructure:"drain_timeout"`
+	MaxInFlight    int           `mapstructure:"max_in_flight"`
+	// RequireHTTPS handles the requests not forwarded as https in release mode: off, redirect or reject
+	RequireHTTPS string `mapstructure:"require_https"`
+}
+
+type ContextConfig struct {
+	Timeout        time.Duration `mapstructure:"timeout"`
+	MaxTimeout     time.Duration `mapstructure:"max_timeout"`
+	InternalSecret string        `mapstructure:"internal_secret"`
+}
+
+type LogConfig struct {
+	Level          string   `mapstructure:"level"`
+	RequestBody    bool     `mapstructure:"request_body"`
+	RequestBodyMax int      `mapstructure:"request_body_max"`
+	RedactFields   []string `mapstructure:"redact_fields"`
+	// RequireConfig makes a missing or invalid configs/log.conf.yaml fatal at startup instead of falling back
+	// to the default logger settings
+	RequireConfig bool `mapstructure:"require_config"`
+	// SampleRate is the fraction of 2xx requests written to the access log, other statuses are always logged
+	SampleRate float64 `mapstructure:"sample_rate"`
+}
+
+type AdminConfig struct {
+	Token string `mapstructure:"token"`
+}
+
+type AuthConfig struct {
+	// JWTSecret verifies the HS256 bearer tokens whose sub claim is the actor of the audit log,
+	// empty treats every request as anonymous
+	JWTSecret string `mapstructure:"jwt_secret"`
+}
+
+type APIConfig struct {
+	Envelope  bool `mapstructure:"envelope"`
+	StringIDs bool `mapstructure:"string_ids"`
+	// TimeFormat encodes the article timestamps as rfc3339, unix (seconds) or unixmilli
+	TimeFormat string `mapstructure:"time_format"`
+	// ExcerptLength is the number of characters of the excerpt list responses carry instead of the content
+	ExcerptLength int `mapstructure:"excerpt_length"`
+	// Versions are the X-API-Version values accepted, oldest first; empty disables the check
+	Versions []string `mapstructure:"versions"`
+	// DefaultVersion is used when X-API-Version is absent, empty means the latest of Versions
+	DefaultVersion string `mapstructure:"default_version"`
+	// DisabledFeatureStatus answers the endpoints of a disabled feature, 404 or 503
+	DisabledFeatureStatus int `mapstructure:"disabled_feature_status"`
+	// DeprecatedParams are the query params answered with Deprecation, Sunset and Warning headers
+	DeprecatedParams []DeprecatedParamConfig `mapstructure:"deprecated_params"`
+}
+
+// DeprecatedParamConfig is a query param still accepted but announced as deprecated
+type DeprecatedParamConfig struct {
+	Name string `mapstructure:"name"`
+	// Replacement is the param clients should use instead, mentioned in the Warning header
+	Replacement string `mapstructure:"replacement"`
+	// Sunset is the date (2006-01-02) the param stops being accepted, zero sends no Sunset header
+	Sunset time.Time `mapstructure:"sunset"`
+}
+
+// FeaturesConfig switches optional endpoints on and off by name (recent, neighbors, export, import, clone),
+// a feature that is not listed is enabled
+type FeaturesConfig map[string]bool
+
+type CORSConfig struct {
+	MaxAge time.Duration `mapstructure:"max_age"`
+}
+
+type PaginationConfig struct {
+	// DefaultSize is the page size when num is absent, at most MaxSize
+	DefaultSize int `mapstructure:"default_size"`
+	MaxSize     int `mapstructure:"max_size"`
+}
+
+type ValidationConfig struct {
+	JSONSchema bool `mapstructure:"json_schema"`
+}
+
+type ImportConfig struct {
+	// MaxBytes is the largest CSV upload accepted by the import endpoint, bigger uploads get a 413
+	MaxBytes int64 `mapstructure:"max_bytes"`
+}
+
+type EventsConfig struct {
+	WebhookURL string `mapstructure:"webhook_url"`
+	// QueueSize is the number of events buffered for delivery in the background, a full queue drops events
+	QueueSize int `mapstructure:"queue_size"`
+	// Timeout bounds the delivery of one event, retries included
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+type CursorConfig struct {
+	Secret string `mapstructure:"secret"`
+}
+
+type BreakerConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	MaxFailures int           `mapstructure:"max_failures"`
+	Cooldown    time.Duration `mapstructure:"cooldown"`
+}
+
+type CacheConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	ListTTL time.Duration `mapstructure:"list_ttl"`
+	// MaxAge is the Cache-Control max-age per endpoint (get_by_id, get_by_slug), other responses are no-store
+	MaxAge map[string]time.Duration `mapstructure:"max_age"`
+}
+
+type ContentConfig struct {
+	// Sanitize strips the disallowed HTML from the content of stored and updated articles
+	Sanitize bool `mapstructure:"sanitize"`
+	// Policy is the allowed HTML: ugc keeps the formatting tags, strict removes every tag
+	Policy string `mapstructure:"policy"`
+	// UniqueTitles rejects a new article whose title is taken with a 409 before trying to insert it
+	UniqueTitles bool `mapstructure:"unique_titles"`
+	// MaxMetadataBytes is the largest JSON encoding of the article metadata accepted, bigger metadata gets a 400
+	MaxMetadataBytes int `mapstructure:"max_metadata_bytes"`
+}
+
+type AuditConfig struct {
+	// Enabled records every create, update and delete of an article to the audit_log table or collection
+	Enabled bool `mapstructure:"enabled"`
+}
+
+type HealthConfig struct {
+	// Timeout bounds each readiness check of GET /readyz, a check still running is reported failed
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+type IDConfig struct {
+	Generator string `mapstructure:"generator"`
+	Node      int64  `mapstructure:"node"`
+}
+
+type DatabaseConfig struct {
+	Driver          string        `mapstructure:"driver"`
+	URI             string        `mapstructure:"uri"`
+	Host            string        `mapstructure:"host"`
+	Port            string        `mapstructure:"port"`
+	User            string        `mapstructure:"user"`
+	Password        string        `mapstructure:"password"`
+	Name            string        `mapstructure:"name"`
+	ConnectAttempts int           `mapstructure:"connect_attempts"`
+	ConnectBackoff  time.Duration `mapstructure:"connect_backoff"`
+	ConnectTimeout  time.Duration `mapstructure:"connect_timeout"`
+	// Replica is the DSN of a read replica serving the list and lookup queries, mysql only
+	Replica string `mapstructure:"replica"`
+	// SlowQueryThreshold logs a warning for every repository call slower than it, 0 disables the check
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold"`
+	// TLS secures the connections to the primary and the replica, mysql only. A tls parameter
+	// already present in the replica DSN takes precedence
+	TLS TLSConfig `mapstructure:"tls"`
+}
+
+// TLSConfig secures the mysql connection, Mode follows the --ssl-mode of the mysql client:
+// disabled, preferred, required, verify-ca or verify-identity
+type TLSConfig struct {
+	Mode string `mapstructure:"mode"`
+	// CA verifies the server certificate instead of the system roots
+	CA string `mapstructure:"ca"`
+	// Cert and Key are the client certificate, for servers requiring X.509 authentication
+	Cert string `mapstructure:"cert"`
+	Key  string `mapstructure:"key"`
+}
+
+// defaults are applied to every key the config file omits
+var defaults = map[string]interface{}{
+	"server.address":                ":9090",
+	"server.base_path":              "/api/v1",
+	"server.drain_timeout":          10,
+	"server.require_https":          "off",
+	"context.timeout":               30,
+	"log.level":                     "info",
+	"log.request_body_max":          1024,
+	"log.redact_fields":             []string{"password", "token", "secret"},
+	"log.sample_rate":               1.0,
+	"pagination.default_size":       10,
+	"pagination.max_size":           100,
+	"import.max_bytes":              10 << 20,
+	"api.excerpt_length":            200,
+	"api.time_format":               "rfc3339",
+	"api.disabled_feature_status":   404,
+	"breaker.max_failures":          5,
+	"breaker.cooldown":              30,
+	"cache.list_ttl":                5,
+	"health.timeout":                2,
+	"events.queue_size":             1024,
+	"events.timeout":                10,
+	"id.generator":                  "auto",
+	"content.policy":                "ugc",
+	"content.unique_titles":         true,
+	"content.max_metadata_bytes":    4 << 10,
+	"database.driver":               "mysql",
+	"database.connect_attempts":     5,
+	"database.connect_backoff":      1,
+	"database.connect_timeout":      5,
+	"database.tls.mode":             "disabled",
+	"database.slow_query_threshold": 0.5,
+}
+
+// Load reads config.yaml from ./configs, ../configs or the working directory into the global viper
+// instance and returns the validated configuration
+func Load() (*Config, error) {
+	viper.SetConfigName("config")
+	viper.SetConfigType("yaml")
+	viper.AddConfigPath("../configs")
+	viper.AddConfigPath("./configs")
+	viper.AddConfigPath(".")
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+	return LoadFrom(viper.GetViper())
+}
+
+// LoadFrom unmarshals an already populated viper instance, applying defaults and validation
+func LoadFrom(v *viper.Viper) (*Config, error) {
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+
+	var cfg Config
+	hook := mapstructure.ComposeDecodeHookFunc(secondsToDuration, mapstructure.StringToTimeHookFunc(time.DateOnly))
+	if err := v.Unmarshal(&cfg, viper.DecodeHook(hook)); err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+	if cfg.Database.Driver == "" {
+		cfg.Database.Driver = "mysql"
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// requiredKeys are the keys each database driver needs before connecting
+var requiredKeys = map[string][]struct {
+	key   string
+	value func(*DatabaseConfig) string
+}{
+	"mysql": {
+		{"database.host", func(d *DatabaseConfig) string { return d.Host }},
+		{"database.port", func(d *DatabaseConfig) string { return d.Port }},
+		{"database.user", func(d *DatabaseConfig) string { return d.User }},
+		{"database.name", func(d *DatabaseConfig) string { return d.Name }},
+	},
+	"mongo": {
+		{"database.uri", func(d *DatabaseConfig) string { return d.URI }},
+		{"database.name", func(d *DatabaseConfig) string { return d.Name }},
+	},
+}
+
+// Validate reports every missing required key at once instead of failing on the first one
+func (c *Config) Validate() error {
+	keys, ok := requiredKeys[c.Database.Driver]
+	if !ok {
+		return fmt.Errorf("unsupported database driver: %s", c.Database.Driver)
+	}
+	switch c.ID.Generator {
+	case "", "auto", "snowflake":
+	default:
+		return fmt.Errorf("unsupported id generator: %s", c.ID.Generator)
+	}
+	switch c.Content.Policy {
+	case "", "ugc", "strict":
+	default:
+		return fmt.Errorf("unsupported content policy: %s", c.Content.Policy)
+	}
+	switch c.Database.TLS.Mode {
+	case "", "disabled", "preferred", "required", "verify-ca", "verify-identity":
+	default:
+		return fmt.Errorf("unsupported database tls mode: %s", c.Database.TLS.Mode)
+	}
+	switch c.Server.RequireHTTPS {
+	case "", "off", "redirect", "reject":
+	default:
+		return fmt.Errorf("unsupported server.require_https: %s", c.Server.RequireHTTPS)
+	}
+	switch c.API.TimeFormat {
+	case "", "rfc3339", "unix", "unixmilli":
+	default:
+		return fmt.Errorf("unsupported api.time_format: %s", c.API.TimeFormat)
+	}
+	switch c.API.DisabledFeatureStatus {
+	case 0, 404, 503:
+	default:
+		return fmt.Errorf("unsupported api.disabled_feature_status: %d", c.API.DisabledFeatureStatus)
+	}
+	if c.API.DefaultVersion != "" && !slices.Contains(c.API.Versions, c.API.DefaultVersion) {
+		return fmt.Errorf("api.default_version %s is not one of api.versions", c.API.DefaultVersion)
+	}
+	for i, param := range c.API.DeprecatedParams {
+		if param.Name == "" {
+			return fmt.Errorf("api.deprecated_params[%d].name is required", i)
+		}
+	}
+	if c.Log.SampleRate < 0 || c.Log.SampleRate > 1 {
+		return fmt.Errorf("log.sample_rate %v must be between 0 and 1", c.Log.SampleRate)
+	}
+	if c.Pagination.DefaultSize > c.Pagination.MaxSize && c.Pagination.MaxSize > 0 {
+		return fmt.Errorf("pagination.default_size %d exceeds pagination.max_size %d", c.Pagination.DefaultSize, c.Pagination.MaxSize)
+	}
+	if (c.Database.TLS.Cert == "") != (c.Database.TLS.Key == "") {
+		return fmt.Errorf("database.tls.cert and database.tls.key must be set together")
+	}
+
+	var missing []string
+	for _, k := range keys {
+		if k.value(&c.Database) == "" {
+			missing = append(missing, k.key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required config keys: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// secondsToDuration decodes plain numbers into time.Duration as seconds, and strings with time.ParseDuration
+func secondsToDuration(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if to != reflect.TypeOf(time.Duration(0)) {
+		return data, nil
+	}
+	switch v := data.(type) {
+	case int:
+		return time.Duration(v) * time.Second, nil
+	case int64:
+		return time.Duration(v) * time.Second, nil
+	case float64:
+		return time.Duration(v * float64(time.Second)), nil
+	case string:
+		return time.ParseDuration(v)
+	}
+	return data, nil
+}
@@ -0,0 +1,246 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/internal/config"
+)
+
+const sampleConfig = `
+debug: true
+server:
+  address: ":8080"
+  trusted_proxies: ["10.0.0.0/8"]
+context:
+  timeout: 2
+  max_timeout: "1m30s"
+breaker:
+  enabled: true
+features:
+  export: false
+api:
+  deprecated_params:
+    - name: "page"
+      replacement: "cursor"
+      sunset: "2027-06-30"
+    - name: "offset"
+      sunset: 2027-03-31
+database:
+  host: "localhost"
+  port: "3306"
+  user: "user"
+  password: "password"
+  name: "article"
+`
+
+func TestLoadFrom(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(strings.NewReader(sampleConfig)))
+
+	cfg, err := config.LoadFrom(v)
+	require.NoError(t, err)
+
+	// explicit values
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, ":8080", cfg.Server.Address)
+	assert.Equal(t, []string{"10.0.0.0/8"}, cfg.Server.TrustedProxies)
+	assert.Equal(t, 2*time.Second, cfg.Context.Timeout)
+	assert.Equal(t, 90*time.Second, cfg.Context.MaxTimeout)
+	assert.True(t, cfg.Breaker.Enabled)
+	assert.Equal(t, "article", cfg.Database.Name)
+	assert.Equal(t, config.FeaturesConfig{"export": false}, cfg.Features)
+	require.Len(t, cfg.API.DeprecatedParams, 2)
+	assert.Equal(t, "cursor", cfg.API.DeprecatedParams[0].Replacement)
+	assert.True(t, time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC).Equal(cfg.API.DeprecatedParams[0].Sunset))
+	assert.True(t, time.Date(2027, 3, 31, 0, 0, 0, 0, time.UTC).Equal(cfg.API.DeprecatedParams[1].Sunset))
+
+	// defaults for omitted keys
+	assert.Equal(t, "/api/v1", cfg.Server.BasePath)
+	assert.Equal(t, 10*time.Second, cfg.Server.DrainTimeout)
+	assert.Equal(t, "off", cfg.Server.RequireHTTPS)
+	assert.Equal(t, "info", cfg.Log.Level)
+	assert.Equal(t, 10, cfg.Pagination.DefaultSize)
+	assert.Equal(t, 100, cfg.Pagination.MaxSize)
+	assert.Equal(t, 200, cfg.API.ExcerptLength)
+	assert.Equal(t, 404, cfg.API.DisabledFeatureStatus)
+	assert.Equal(t, 5, cfg.Breaker.MaxFailures)
+	assert.Equal(t, 30*time.Second, cfg.Breaker.Cooldown)
+	assert.Equal(t, 5*time.Second, cfg.Cache.ListTTL)
+	assert.Equal(t, "mysql", cfg.Database.Driver)
+	assert.Equal(t, 5, cfg.Database.ConnectAttempts)
+	assert.Equal(t, time.Second, cfg.Database.ConnectBackoff)
+	assert.Equal(t, 5*time.Second, cfg.Database.ConnectTimeout)
+	assert.Equal(t, "disabled", cfg.Database.TLS.Mode)
+	assert.Equal(t, 500*time.Millisecond, cfg.Database.SlowQueryThreshold)
+	assert.False(t, cfg.Content.Sanitize)
+	assert.Equal(t, "ugc", cfg.Content.Policy)
+	assert.True(t, cfg.Content.UniqueTitles)
+	assert.Equal(t, 4<<10, cfg.Content.MaxMetadataBytes)
+	assert.Equal(t, "rfc3339", cfg.API.TimeFormat)
+	assert.Equal(t, int64(10<<20), cfg.Import.MaxBytes)
+	assert.False(t, cfg.Audit.Enabled)
+	assert.Equal(t, 2*time.Second, cfg.Health.Timeout)
+	assert.Empty(t, cfg.Auth.JWTSecret)
+}
+
+func TestLoadFromValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		missing string
+	}{
+		{
+			name: "mysql-complete",
+			config: map[string]interface{}{"database": map[string]interface{}{
+				"host": "localhost", "port": "3306", "user": "user", "name": "article",
+			}},
+		},
+		{
+			name: "mysql-incomplete",
+			config: map[string]interface{}{"database": map[string]interface{}{
+				"port": "3306", "user": "user",
+			}},
+			missing: "missing required config keys: database.host, database.name",
+		},
+		{
+			name:    "empty",
+			config:  map[string]interface{}{},
+			missing: "missing required config keys: database.host, database.port, database.user, database.name",
+		},
+		{
+			name: "mongo-incomplete",
+			config: map[string]interface{}{"database": map[string]interface{}{
+				"driver": "mongo", "host": "localhost",
+			}},
+			missing: "missing required config keys: database.uri, database.name",
+		},
+		{
+			name: "unknown-driver",
+			config: map[string]interface{}{"database": map[string]interface{}{
+				"driver": "oracle",
+			}},
+			missing: "unsupported database driver: oracle",
+		},
+		{
+			name: "unknown-id-generator",
+			config: map[string]interface{}{
+				"database": map[string]interface{}{"host": "localhost", "port": "3306", "user": "user", "name": "article"},
+				"id":       map[string]interface{}{"generator": "uuid"},
+			},
+			missing: "unsupported id generator: uuid",
+		},
+		{
+			name: "unknown-content-policy",
+			config: map[string]interface{}{
+				"database": map[string]interface{}{"host": "localhost", "port": "3306", "user": "user", "name": "article"},
+				"content":  map[string]interface{}{"sanitize": true, "policy": "permissive"},
+			},
+			missing: "unsupported content policy: permissive",
+		},
+		{
+			name: "unsupported-default-version",
+			config: map[string]interface{}{
+				"database": map[string]interface{}{"host": "localhost", "port": "3306", "user": "user", "name": "article"},
+				"api":      map[string]interface{}{"versions": []string{"1", "2"}, "default_version": "3"},
+			},
+			missing: "api.default_version 3 is not one of api.versions",
+		},
+		{
+			name: "default-page-size-over-max",
+			config: map[string]interface{}{
+				"database":   map[string]interface{}{"host": "localhost", "port": "3306", "user": "user", "name": "article"},
+				"pagination": map[string]interface{}{"default_size": 50, "max_size": 20},
+			},
+			missing: "pagination.default_size 50 exceeds pagination.max_size 20",
+		},
+		{
+			name: "unsupported-require-https",
+			config: map[string]interface{}{
+				"database": map[string]interface{}{"host": "localhost", "port": "3306", "user": "user", "name": "article"},
+				"server":   map[string]interface{}{"require_https": "always"},
+			},
+			missing: "unsupported server.require_https: always",
+		},
+		{
+			name: "unsupported-time-format",
+			config: map[string]interface{}{
+				"database": map[string]interface{}{"host": "localhost", "port": "3306", "user": "user", "name": "article"},
+				"api":      map[string]interface{}{"time_format": "iso"},
+			},
+			missing: "unsupported api.time_format: iso",
+		},
+		{
+			name: "unsupported-disabled-feature-status",
+			config: map[string]interface{}{
+				"database": map[string]interface{}{"host": "localhost", "port": "3306", "user": "user", "name": "article"},
+				"api":      map[string]interface{}{"disabled_feature_status": 410},
+			},
+			missing: "unsupported api.disabled_feature_status: 410",
+		},
+		{
+			name: "deprecated-param-without-name",
+			config: map[string]interface{}{
+				"database": map[string]interface{}{"host": "localhost", "port": "3306", "user": "user", "name": "article"},
+				"api":      map[string]interface{}{"deprecated_params": []interface{}{map[string]interface{}{"replacement": "cursor"}}},
+			},
+			missing: "api.deprecated_params[0].name is required",
+		},
+		{
+			name: "sample-rate-out-of-range",
+			config: map[string]interface{}{
+				"database": map[string]interface{}{"host": "localhost", "port": "3306", "user": "user", "name": "article"},
+				"log":      map[string]interface{}{"sample_rate": 1.5},
+			},
+			missing: "log.sample_rate 1.5 must be between 0 and 1",
+		},
+		{
+			name: "unknown-tls-mode",
+			config: map[string]interface{}{"database": map[string]interface{}{
+				"host": "localhost", "port": "3306", "user": "user", "name": "article",
+				"tls": map[string]interface{}{"mode": "true"},
+			}},
+			missing: "unsupported database tls mode: true",
+		},
+		{
+			name: "tls-cert-without-key",
+			config: map[string]interface{}{"database": map[string]interface{}{
+				"host": "localhost", "port": "3306", "user": "user", "name": "article",
+				"tls": map[string]interface{}{"mode": "verify-identity", "cert": "client.pem"},
+			}},
+			missing: "database.tls.cert and database.tls.key must be set together",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := viper.New()
+			require.NoError(t, v.MergeConfigMap(tt.config))
+
+			_, err := config.LoadFrom(v)
+
+			if tt.missing == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.missing)
+		})
+	}
+}
+
+func TestLoadExampleConfig(t *testing.T) {
+	v := viper.New()
+	v.SetConfigFile("../../configs/config.example.yaml")
+	require.NoError(t, v.ReadInConfig())
+
+	cfg, err := config.LoadFrom(v)
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, cfg.CORS.MaxAge)
+	assert.Equal(t, map[string]time.Duration{"get_by_id": time.Minute, "get_by_slug": time.Minute}, cfg.Cache.MaxAge)
+}
@@ -0,0 +1,116 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	log "github.com/lingdongomg/g-lib/logger"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	// DefaultQueueSize is the number of events AsyncPublisher buffers when no size is configured
+	DefaultQueueSize = 1024
+	// DefaultDeliveryTimeout bounds the delivery of one event, retries included, when none is configured
+	DefaultDeliveryTimeout = 10 * time.Second
+)
+
+// ErrQueueFull is returned by AsyncPublisher.Publish when the event is dropped because the queue is full
+var ErrQueueFull = errors.New("event queue is full")
+
+// ErrPublisherClosed is returned by AsyncPublisher.Publish after Close
+var ErrPublisherClosed = errors.New("event publisher is closed")
+
+// Publisher delivers an article event, WebhookPublisher is one
+type Publisher interface {
+	Publish(ctx context.Context, event domain.ArticleEvent) error
+}
+
+// AsyncPublisher decorates a Publisher so that Publish only queues the event and returns at once.
+// A single worker delivers the queued events in order. Each delivery keeps the values of the request
+// context (trace, request id) but not its cancellation, and has its own deadline, so a slow or
+// unreachable subscriber never holds up the write that produced the event.
+type AsyncPublisher struct {
+	next    Publisher
+	timeout time.Duration
+	queue   chan queuedEvent
+	done    chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+type queuedEvent struct {
+	ctx   context.Context
+	event domain.ArticleEvent
+}
+
+// NewAsyncPublisher starts the worker delivering to next. queueSize <= 0 means DefaultQueueSize and
+// timeout <= 0 means DefaultDeliveryTimeout.
+func NewAsyncPublisher(next Publisher, queueSize int, timeout time.Duration) *AsyncPublisher {
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	if timeout <= 0 {
+		timeout = DefaultDeliveryTimeout
+	}
+	p := &AsyncPublisher{
+		next:    next,
+		timeout: timeout,
+		queue:   make(chan queuedEvent, queueSize),
+		done:    make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Publish queues the event without waiting for its delivery. A full queue drops the event with
+// ErrQueueFull rather than blocking the caller.
+func (p *AsyncPublisher) Publish(ctx context.Context, event domain.ArticleEvent) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPublisherClosed
+	}
+	select {
+	case p.queue <- queuedEvent{ctx: context.WithoutCancel(ctx), event: event}:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting events and waits until the queued ones are delivered or ctx is done
+func (p *AsyncPublisher) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *AsyncPublisher) run() {
+	defer close(p.done)
+	for q := range p.queue {
+		p.deliver(q)
+	}
+}
+
+func (p *AsyncPublisher) deliver(q queuedEvent) {
+	ctx, cancel := context.WithTimeout(q.ctx, p.timeout)
+	defer cancel()
+	if err := p.next.Publish(ctx, q.event); err != nil {
+		log.Error("Failed to deliver "+q.event.Type+" event:", err)
+	}
+}
@@ -0,0 +1,90 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/event"
+)
+
+// blockingPublisher records the events it receives and blocks each delivery until release is closed
+type blockingPublisher struct {
+	release   chan struct{}
+	delivered chan domain.ArticleEvent
+	ctxErr    chan error
+}
+
+func newBlockingPublisher() *blockingPublisher {
+	return &blockingPublisher{
+		release:   make(chan struct{}),
+		delivered: make(chan domain.ArticleEvent, 10),
+		ctxErr:    make(chan error, 10),
+	}
+}
+
+func (p *blockingPublisher) Publish(ctx context.Context, e domain.ArticleEvent) error {
+	select {
+	case <-p.release:
+	case <-ctx.Done():
+		p.ctxErr <- ctx.Err()
+		return ctx.Err()
+	}
+	p.delivered <- e
+	return nil
+}
+
+func TestAsyncPublisher(t *testing.T) {
+	t.Run("does-not-wait-for-delivery", func(t *testing.T) {
+		next := newBlockingPublisher()
+		p := event.NewAsyncPublisher(next, 10, time.Minute)
+
+		// the request is over by the time the event is delivered
+		ctx, cancel := context.WithCancel(context.Background())
+		start := time.Now()
+		require.NoError(t, p.Publish(ctx, domain.ArticleEvent{Type: domain.EventArticleCreated, ArticleID: 1}))
+		require.NoError(t, p.Publish(ctx, domain.ArticleEvent{Type: domain.EventArticleUpdated, ArticleID: 1}))
+		assert.Less(t, time.Since(start), time.Second)
+		cancel()
+
+		close(next.release)
+		require.NoError(t, p.Close(context.Background()))
+		assert.Equal(t, domain.EventArticleCreated, (<-next.delivered).Type)
+		assert.Equal(t, domain.EventArticleUpdated, (<-next.delivered).Type)
+	})
+
+	t.Run("drops-when-full", func(t *testing.T) {
+		next := newBlockingPublisher()
+		p := event.NewAsyncPublisher(next, 1, time.Minute)
+
+		// the worker holds the first event, the second one fills the queue
+		require.NoError(t, p.Publish(context.Background(), domain.ArticleEvent{ArticleID: 1}))
+		require.Eventually(t, func() bool {
+			return p.Publish(context.Background(), domain.ArticleEvent{ArticleID: 2}) == nil
+		}, time.Second, time.Millisecond)
+		assert.ErrorIs(t, p.Publish(context.Background(), domain.ArticleEvent{ArticleID: 3}), event.ErrQueueFull)
+
+		close(next.release)
+		require.NoError(t, p.Close(context.Background()))
+		assert.ErrorIs(t, p.Publish(context.Background(), domain.ArticleEvent{ArticleID: 4}), event.ErrPublisherClosed)
+	})
+
+	t.Run("delivery-deadline", func(t *testing.T) {
+		next := newBlockingPublisher()
+		p := event.NewAsyncPublisher(next, 1, 10*time.Millisecond)
+
+		require.NoError(t, p.Publish(context.Background(), domain.ArticleEvent{ArticleID: 1}))
+		select {
+		case err := <-next.ctxErr:
+			assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		case <-time.After(time.Second):
+			t.Fatal("the delivery was not cancelled")
+		}
+		require.NoError(t, p.Close(context.Background()))
+	})
+}
@@ -0,0 +1,86 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	defaultMaxAttempts = 3
+	defaultBackoff     = 200 * time.Millisecond
+)
+
+// WebhookPublisher POSTs every article event as JSON to a configured URL
+type WebhookPublisher struct {
+	URL         string
+	Client      *http.Client
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// NewWebhookPublisher will create a publisher that delivers events to the given URL
+func NewWebhookPublisher(url string, client *http.Client) *WebhookPublisher {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &WebhookPublisher{
+		URL:         url,
+		Client:      client,
+		MaxAttempts: defaultMaxAttempts,
+		Backoff:     defaultBackoff,
+	}
+}
+
+// Publish delivers the event, retrying with an exponential backoff on network errors and 5xx responses
+func (p *WebhookPublisher) Publish(ctx context.Context, event domain.ArticleEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	backoff := p.Backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := p.send(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= p.MaxAttempts {
+			return fmt.Errorf("webhook delivery failed after %d attempt(s): %w", attempt, err)
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (p *WebhookPublisher) send(ctx context.Context, body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return true, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return false, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return false, nil
+}
@@ -0,0 +1,57 @@
+package event_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/event"
+)
+
+func TestWebhookPublisher(t *testing.T) {
+	var calls atomic.Int32
+	var received domain.ArticleEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	p := event.NewWebhookPublisher(srv.URL, srv.Client())
+	p.Backoff = time.Millisecond
+
+	err := p.Publish(context.TODO(), domain.ArticleEvent{Type: domain.EventArticleCreated, ArticleID: 7})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, domain.EventArticleCreated, received.Type)
+	assert.Equal(t, int64(7), received.ArticleID)
+}
+
+func TestWebhookPublisherGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	p := event.NewWebhookPublisher(srv.URL, srv.Client())
+	p.Backoff = time.Millisecond
+
+	err := p.Publish(context.TODO(), domain.ArticleEvent{Type: domain.EventArticleDeleted, ArticleID: 7})
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load(), "4xx responses are not retried")
+}
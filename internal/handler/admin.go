@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"database/sql"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/logger"
+)
+
+// LogLevelRequest is the body of PUT /admin/loglevel
+type LogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// LogLevelResponse reports the current log level
+type LogLevelResponse struct {
+	Level string `json:"level"`
+}
+
+// statsRateLimit bounds how often GET /admin/stats may run its count queries
+const statsRateLimit = 10
+
+// AdminHandler serves the operational endpoints under /admin
+type AdminHandler struct {
+	logger   *logger.Logger
+	counter  ArticleCounter
+	articles ArticleLookup
+	// responses builds the article responses of the admin article endpoint, see WithArticleLookup
+	responses *ArticleHandler
+	dbStats   func() sql.DBStats
+	features  *middleware.FeatureFlags
+	startedAt time.Time
+}
+
+// NewAdminHandler will initialize the /admin resources endpoint, every route requires the bearer token
+func NewAdminHandler(r *gin.Engine, l *logger.Logger, token string, opts ...AdminOption) {
+	handler := &AdminHandler{logger: l, startedAt: time.Now()}
+	for _, opt := range opts {
+		opt(handler)
+	}
+
+	admin := r.Group("/admin")
+	admin.Use(middleware.BearerAuth(token), middleware.RequireJSON())
+	{
+		admin.GET("/loglevel", handler.GetLogLevel)
+		admin.PUT("/loglevel", handler.SetLogLevel)
+		admin.GET("/stats", middleware.RateLimit(statsRateLimit, time.Minute), handler.GetStats)
+		if handler.articles != nil {
+			admin.GET("/articles/:id", handler.GetArticle)
+		}
+		if handler.features != nil {
+			admin.GET("/features", handler.GetFeatures)
+			admin.PUT("/features/:name", handler.SetFeature)
+		}
+	}
+}
+
+// GetLogLevel returns the current level of the injected logger
+func (h *AdminHandler) GetLogLevel(c *gin.Context) {
+	respondJSON(c, http.StatusOK, LogLevelResponse{Level: h.logger.Level().String()})
+}
+
+// SetLogLevel changes the level of the injected logger at runtime
+func (h *AdminHandler) SetLogLevel(c *gin.Context) {
+	var req LogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(http.StatusBadRequest, "请求参数错误", err))
+		return
+	}
+	level, err := logger.ParseLevel(req.Level)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(http.StatusBadRequest, "level 参数错误", err))
+		return
+	}
+
+	previous := h.logger.Level()
+	h.logger.SetLevel(level)
+	h.logger.Warnf("log level changed from %s to %s", previous, level)
+
+	respondJSON(c, http.StatusOK, LogLevelResponse{Level: level.String()})
+}
+
+// WithFeatureToggles enables GET /admin/features and PUT /admin/features/:name
+func WithFeatureToggles(flags *middleware.FeatureFlags) AdminOption {
+	return func(h *AdminHandler) {
+		h.features = flags
+	}
+}
+
+// FeatureRequest is the body of PUT /admin/features/:name
+type FeatureRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// GetFeatures lists the configured feature flags, features that are not listed are enabled
+func (h *AdminHandler) GetFeatures(c *gin.Context) {
+	respondJSON(c, http.StatusOK, h.features.All())
+}
+
+// SetFeature switches a feature on or off at runtime, the change is lost on restart.
+// A name that is not one of the Feature constants is answered with 404, a typo would otherwise guard nothing.
+func (h *AdminHandler) SetFeature(c *gin.Context) {
+	name := c.Param("name")
+	if !slices.Contains(knownFeatures, name) {
+		middleware.HandleError(c, middleware.NewAppError(http.StatusNotFound, "功能不存在", name))
+		return
+	}
+
+	var req FeatureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(http.StatusBadRequest, "请求参数错误", err))
+		return
+	}
+
+	h.features.Set(name, *req.Enabled)
+	h.logger.Warnf("feature %s set to enabled=%t", name, *req.Enabled)
+
+	respondJSON(c, http.StatusOK, h.features.All())
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+)
+
+// ArticleLookup is the part of the ArticleService the admin article endpoint needs
+type ArticleLookup interface {
+	GetByID(ctx context.Context, id int64) (domain.Article, error)
+	GetByIDIncludingDeleted(ctx context.Context, id int64) (domain.Article, error)
+}
+
+// WithArticleLookup enables GET /admin/articles/:id. opts are the options of the article endpoints, so
+// the article is encoded the same way (WithStringIDs, WithTimeFormat) as by GET /articles/:id.
+func WithArticleLookup(lookup ArticleLookup, opts ...Option) AdminOption {
+	return func(h *AdminHandler) {
+		h.articles = lookup
+		h.responses = newArticleHandler(nil, opts...)
+	}
+}
+
+// GetArticle returns the article by id, with ?include_deleted=true soft-deleted articles are returned too
+// so admins can decide on restoring them
+func (h *AdminHandler) GetArticle(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	ctx := c.Request.Context()
+	var art domain.Article
+	if c.Query("include_deleted") == "true" {
+		art, err = h.articles.GetByIDIncludingDeleted(ctx, id)
+	} else {
+		art, err = h.articles.GetByID(ctx, id)
+	}
+	if err != nil {
+		h.logger.Error("Error occurred while processing request ", err)
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(statusCode(err), "获取文章失败", err))
+		return
+	}
+
+	respondJSON(c, http.StatusOK, h.responses.articleResponse(art))
+}
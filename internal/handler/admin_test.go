@@ -0,0 +1,252 @@
+package handler_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/handler"
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+	"github.com/bxcodec/go-clean-arch/internal/handler/mocks"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/logger"
+)
+
+const adminToken = "admin-token"
+
+func newAdminRequest(method, body string) *http.Request {
+	req := httptest.NewRequest(method, "/admin/loglevel", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req
+}
+
+func TestGetLogLevel(t *testing.T) {
+	r := setupRouter()
+	handler.NewAdminHandler(r, logger.New(logger.WarnLevel, func(logger.Level, string) {}), adminToken)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAdminRequest(http.MethodGet, ""))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var res handler.LogLevelResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, "warn", res.Level)
+}
+
+func TestSetLogLevel(t *testing.T) {
+	var debugLogs []string
+	l := logger.New(logger.InfoLevel, func(level logger.Level, msg string) {
+		if level == logger.DebugLevel {
+			debugLogs = append(debugLogs, msg)
+		}
+	})
+	r := setupRouter()
+	handler.NewAdminHandler(r, l, adminToken)
+
+	l.Debug("before")
+	assert.Empty(t, debugLogs)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAdminRequest(http.MethodPut, `{"level":"debug"}`))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, logger.DebugLevel, l.Level())
+	l.Debug("after")
+	assert.Equal(t, []string{"after"}, debugLogs)
+}
+
+func TestSetLogLevelInvalid(t *testing.T) {
+	l := logger.New(logger.InfoLevel, func(logger.Level, string) {})
+	r := setupRouter()
+	handler.NewAdminHandler(r, l, adminToken)
+
+	for _, body := range []string{`{"level":"verbose"}`, `{}`, `not-json`} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, newAdminRequest(http.MethodPut, body))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	assert.Equal(t, logger.InfoLevel, l.Level())
+}
+
+func TestLogLevelRequiresToken(t *testing.T) {
+	r := setupRouter()
+	handler.NewAdminHandler(r, logger.New(logger.InfoLevel, func(logger.Level, string) {}), adminToken)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/loglevel", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestGetStats(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("Count", mock.Anything, domain.ArticleFilter{}).Return(int64(10), nil).Once()
+	mockUCase.On("Count", mock.Anything, domain.ArticleFilter{Status: domain.StatusDraft}).Return(int64(3), nil).Once()
+	mockUCase.On("Count", mock.Anything, domain.ArticleFilter{Status: domain.StatusPublished}).Return(int64(6), nil).Once()
+	mockUCase.On("Count", mock.Anything, domain.ArticleFilter{Status: domain.StatusArchived}).Return(int64(1), nil).Once()
+
+	r := setupRouter()
+	handler.NewAdminHandler(r, logger.New(logger.InfoLevel, func(logger.Level, string) {}), adminToken,
+		handler.WithArticleStats(mockUCase),
+		handler.WithDBStats(func() sql.DBStats { return sql.DBStats{OpenConnections: 4, InUse: 1, Idle: 3} }),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/stats", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var res map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, map[string]interface{}{
+		"total":     float64(10),
+		"by_status": map[string]interface{}{"draft": float64(3), "published": float64(6), "archived": float64(1)},
+	}, res["articles"])
+	db := res["db"].(map[string]interface{})
+	assert.Equal(t, float64(4), db["open_connections"])
+	assert.Equal(t, float64(1), db["in_use"])
+	assert.Contains(t, res, "uptime_seconds")
+	mockUCase.AssertExpectations(t)
+}
+
+func TestGetStatsRequiresToken(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+
+	r := setupRouter()
+	handler.NewAdminHandler(r, logger.New(logger.InfoLevel, func(logger.Level, string) {}), adminToken,
+		handler.WithArticleStats(mockUCase))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/stats", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockUCase.AssertNotCalled(t, "Count", mock.Anything, mock.Anything)
+}
+
+func TestAdminGetArticleIncludingDeleted(t *testing.T) {
+	deletedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("GetByIDIncludingDeleted", mock.Anything, int64(7)).Return(domain.Article{ID: 7, DeletedAt: &deletedAt}, nil).Once()
+	mockUCase.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{}, domain.ErrNotFound).Once()
+
+	r := setupRouter()
+	handler.NewAdminHandler(r, logger.New(logger.InfoLevel, func(logger.Level, string) {}), adminToken, handler.WithArticleLookup(mockUCase))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/articles/7?include_deleted=true", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var res handler.ArticleResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	require.NotNil(t, res.DeletedAt)
+	assert.True(t, deletedAt.Equal(*res.DeletedAt))
+
+	// without the parameter deleted articles stay hidden
+	req = httptest.NewRequest(http.MethodGet, "/admin/articles/7", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestAdminGetArticleResponseOptions(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("GetByID", mock.Anything, int64(7)).
+		Return(domain.Article{ID: 7, Content: "one two three", CreatedAt: created, UpdatedAt: created}, nil).Once()
+
+	// the article is encoded with the options of the article endpoints
+	r := setupRouter()
+	handler.NewAdminHandler(r, logger.New(logger.InfoLevel, func(logger.Level, string) {}), adminToken,
+		handler.WithArticleLookup(mockUCase, handler.WithStringIDs(), handler.WithTimeFormat(handler.TimeFormatUnix)))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/articles/7", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var res map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, "7", res["id"])
+	assert.Equal(t, float64(created.Unix()), res["created_at"])
+	assert.Equal(t, float64(3), res["word_count"])
+	mockUCase.AssertExpectations(t)
+}
+
+func TestAdminGetArticleRequiresToken(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	r := setupRouter()
+	handler.NewAdminHandler(r, logger.New(logger.InfoLevel, func(logger.Level, string) {}), adminToken, handler.WithArticleLookup(mockUCase))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/articles/7?include_deleted=true", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockUCase.AssertNotCalled(t, "GetByIDIncludingDeleted", mock.Anything, mock.Anything)
+}
+
+func TestAdminFeatures(t *testing.T) {
+	flags := middleware.NewFeatureFlags(map[string]bool{"export": true}, http.StatusNotFound)
+	r := setupRouter()
+	handler.NewAdminHandler(r, logger.New(logger.InfoLevel, func(logger.Level, string) {}), adminToken, handler.WithFeatureToggles(flags))
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/features/export", strings.NewReader(`{"enabled":false}`))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"export":false}`, w.Body.String())
+	assert.False(t, flags.Enabled("export"))
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/features", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.JSONEq(t, `{"export":false}`, w.Body.String())
+
+	// enabled is required, an empty body must not switch the feature off
+	req = httptest.NewRequest(http.MethodPut, "/admin/features/import", strings.NewReader(`{}`))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.True(t, flags.Enabled("import"))
+
+	// an unknown name is rejected instead of adding a flag that guards nothing
+	req = httptest.NewRequest(http.MethodPut, "/admin/features/exprot", strings.NewReader(`{"enabled":false}`))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.NotContains(t, flags.All(), "exprot")
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/features", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -3,15 +3,27 @@ package handler
 import (
 	"context"
 
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"path"
+	"reflect"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/santhosh-tekuri/jsonschema/v6"
 
 	"github.com/bxcodec/go-clean-arch/domain"
 	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
-	log "github.com/lingdongomg/g-lib/logger"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/logger"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/requestctx"
 )
 
 // ResponseError represent the response error struct
@@ -19,62 +31,630 @@ type ResponseError struct {
 	Message string `json:"message"`
 }
 
+// FieldError represent a single invalid field of the request body
+type FieldError struct {
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+}
+
+// ValidationResult represent the response body of a validate-only request
+type ValidationResult struct {
+	Valid  bool         `json:"valid"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
 // ArticleService represent the article's usecases
 //
 //go:generate mockery --name ArticleService
 type ArticleService interface {
-	Fetch(ctx context.Context, cursor string, num int64) ([]domain.Article, string, error)
+	Fetch(ctx context.Context, cursor string, direction domain.PageDirection, num int64, filter domain.ArticleFilter) ([]domain.Article, domain.PageCursors, error)
+	FetchRecent(ctx context.Context, limit int64) ([]domain.Article, error)
+	FetchAll(ctx context.Context) (<-chan domain.Article, <-chan error)
+	FetchChanges(ctx context.Context, since time.Time, cursor string, num int64) ([]domain.Article, string, error)
+	Count(ctx context.Context, filter domain.ArticleFilter) (int64, error)
+	CountByAuthor(ctx context.Context, authorID int64) (int64, error)
+	ListAuthorsWithCounts(ctx context.Context) ([]domain.AuthorStat, error)
 	GetByID(ctx context.Context, id int64) (domain.Article, error)
+	GetByIDIncludingDeleted(ctx context.Context, id int64) (domain.Article, error)
+	GetByIDs(ctx context.Context, ids []int64) ([]domain.Article, []int64, error)
 	Update(ctx context.Context, ar *domain.Article) error
+	IncrementViewCount(ctx context.Context, id int64) error
+	UpdateStatus(ctx context.Context, id int64, status domain.ArticleStatus) (domain.Article, error)
 	GetByTitle(ctx context.Context, title string) (domain.Article, error)
+	FindByTitle(ctx context.Context, title string) ([]domain.Article, error)
+	GetBySlug(ctx context.Context, slug string) (domain.Article, error)
+	GetNeighbors(ctx context.Context, id int64) (prev, next *domain.Article, err error)
 	Store(context.Context, *domain.Article) error
+	Clone(ctx context.Context, id int64) (domain.Article, error)
+	StoreBatch(ctx context.Context, articles []*domain.Article) error
+	Upsert(ctx context.Context, ar *domain.Article) (created bool, err error)
 	Delete(ctx context.Context, id int64) error
 }
 
 // ArticleHandler  represent the httphandler for article
 type ArticleHandler struct {
-	Service   ArticleService
-	validator *validator.Validate
+	Service         ArticleService
+	validator       *validator.Validate
+	logger          *logger.Logger
+	maxPageSize     int
+	defaultPageSize int
+	prefix          string
+	importMaxBytes  int64
+	schema          *jsonschema.Schema
+	stringIDs       bool
+	timeFormat      string
+	maxAge          map[string]time.Duration
+	excerptLen      int
+	maxMetadata     int
+	features        *middleware.FeatureFlags
 }
 
-const defaultNum = 10
+// Option configures optional behaviour of the ArticleHandler
+type Option func(*ArticleHandler)
 
-// NewArticleHandler will initialize the articles/ resources endpoint
-func NewArticleHandler(r *gin.Engine, svc ArticleService) {
-	handler := &ArticleHandler{
-		Service:   svc,
-		validator: validator.New(),
+// WithLogger sets the logger used by the handler, defaults to logger.Default()
+func WithLogger(l *logger.Logger) Option {
+	return func(h *ArticleHandler) {
+		h.logger = l
+	}
+}
+
+// WithPrefix sets the path the article routes are mounted under, defaults to defaultPrefix
+func WithPrefix(prefix string) Option {
+	return func(h *ArticleHandler) {
+		if prefix != "" {
+			h.prefix = prefix
+		}
+	}
+}
+
+// WithMaxPageSize sets the upper bound of the num query param, defaults to defaultMaxPageSize
+func WithMaxPageSize(n int) Option {
+	return func(h *ArticleHandler) {
+		if n > 0 {
+			h.maxPageSize = n
+		}
+	}
+}
+
+// WithDefaultPageSize sets the page size used when num is absent, defaults to defaultNum.
+// It is capped by the max page size.
+func WithDefaultPageSize(n int) Option {
+	return func(h *ArticleHandler) {
+		if n > 0 {
+			h.defaultPageSize = n
+		}
+	}
+}
+
+// WithJSONSchema validates the Store request body against the embedded JSON Schema
+// (schema/article.json) instead of the struct tags, the schema is compiled once here
+func WithJSONSchema() Option {
+	return func(h *ArticleHandler) {
+		h.schema = mustCompileSchema("article.json", articleSchemaJSON)
+	}
+}
+
+// WithStringIDs encodes article ids as JSON strings in responses, string ids are always accepted on input
+func WithStringIDs() Option {
+	return func(h *ArticleHandler) {
+		h.stringIDs = true
+	}
+}
+
+// Endpoints whose responses may be cached, see WithCacheMaxAge
+const (
+	EndpointGetByID   = "get_by_id"
+	EndpointGetBySlug = "get_by_slug"
+)
+
+// WithTimeFormat sets how the timestamps of the article responses are encoded, one of TimeFormatRFC3339
+// (the default), TimeFormatUnix and TimeFormatUnixMilli. Requests may send timestamps in the same format.
+func WithTimeFormat(format string) Option {
+	return func(h *ArticleHandler) {
+		if ValidTimeFormat(format) {
+			h.timeFormat = format
+		}
+	}
+}
+
+// WithCacheMaxAge lets browsers and CDNs cache the responses of the given endpoints (EndpointGetByID,
+// EndpointGetBySlug) for their max-age, every other article response is sent with Cache-Control: no-store
+func WithCacheMaxAge(maxAge map[string]time.Duration) Option {
+	return func(h *ArticleHandler) {
+		h.maxAge = maxAge
 	}
+}
+
+// WithImportMaxBytes sets the largest import request accepted, bigger uploads are rejected with 413.
+// Defaults to defaultImportMaxBytes.
+func WithImportMaxBytes(n int64) Option {
+	return func(h *ArticleHandler) {
+		if n > 0 {
+			h.importMaxBytes = n
+		}
+	}
+}
+
+// WithMaxMetadataBytes sets the largest JSON encoding of the article metadata accepted by Store and Upsert,
+// defaults to defaultMaxMetadataBytes
+func WithMaxMetadataBytes(n int) Option {
+	return func(h *ArticleHandler) {
+		if n > 0 {
+			h.maxMetadata = n
+		}
+	}
+}
+
+// WithExcerptLength sets the number of characters of the excerpt returned by the list endpoints,
+// defaults to defaultExcerptLength
+func WithExcerptLength(n int) Option {
+	return func(h *ArticleHandler) {
+		if n > 0 {
+			h.excerptLen = n
+		}
+	}
+}
+
+// Features that can be switched off at runtime, see WithFeatureFlags
+const (
+	FeatureRecent    = "recent"
+	FeatureNeighbors = "neighbors"
+	FeatureExport    = "export"
+	FeatureImport    = "import"
+	FeatureClone     = "clone"
+)
+
+// knownFeatures are the names PUT /admin/features/:name accepts
+var knownFeatures = []string{FeatureRecent, FeatureNeighbors, FeatureExport, FeatureImport, FeatureClone}
+
+// WithFeatureFlags guards the optional endpoints (FeatureRecent, FeatureExport, ...) with flags,
+// a disabled feature answers with the status configured in flags
+func WithFeatureFlags(flags *middleware.FeatureFlags) Option {
+	return func(h *ArticleHandler) {
+		h.features = flags
+	}
+}
+
+const (
+	defaultNum           = 10
+	defaultMaxPageSize   = 100
+	defaultPrefix        = "/api/v1"
+	defaultExcerptLength = 200
+	defaultRecentLimit   = 5
+	// defaultImportMaxBytes bounds the size of an uploaded CSV file, see WithImportMaxBytes
+	defaultImportMaxBytes = 10 << 20
+	// defaultMaxMetadataBytes bounds the JSON encoding of the article metadata, see WithMaxMetadataBytes
+	defaultMaxMetadataBytes = 4 << 10
+)
+
+// fetchQueryParams are the FetchArticle query params that must appear at most once
+var fetchQueryParams = []string{"num", "cursor", "direction", "status", "from", "to", "with_total", "ids", "fields", "author_id", "tags", "match", "title"}
+
+// changesQueryParams are the FetchChanges query params that must appear at most once
+var changesQueryParams = []string{"since", "cursor", "num"}
+
+// NewArticleHandler will initialize the articles/ resources endpoint
+func NewArticleHandler(r *gin.Engine, svc ArticleService, opts ...Option) {
+	handler := newArticleHandler(svc, opts...)
 
 	// 注册路由
-	v1 := r.Group("/api/v1")
+	v1 := r.Group(handler.prefix)
+	v1.Use(middleware.RequireJSON())
+	v1.Use(middleware.NoStore())
 	{
-		v1.GET("/articles", handler.FetchArticle)
+		v1.GET("/articles", middleware.UniqueQueryParams(fetchQueryParams...), handler.FetchArticle)
+		v1.GET("/articles/recent", handler.feature(FeatureRecent), handler.FetchRecent)
+		v1.GET("/articles/changes", middleware.UniqueQueryParams(changesQueryParams...), handler.FetchChanges)
+		v1.GET("/articles/export", handler.feature(FeatureExport), handler.Export)
+		v1.GET("/articles/export.csv", handler.feature(FeatureExport), handler.ExportCSV)
 		v1.POST("/articles", handler.Store)
 		v1.GET("/articles/:id", handler.GetByID)
+		// HEAD 与 GET 返回相同的状态码和响应头（ETag 等），只是不带响应体
+		v1.HEAD("/articles/:id", headOnly(handler.GetByID))
+		v1.PUT("/articles/:id", handler.Upsert)
+		v1.GET("/articles/slug/:slug", handler.GetBySlug)
+		v1.GET("/articles/:id/neighbors", handler.feature(FeatureNeighbors), handler.GetNeighbors)
+		v1.PUT("/articles/:id/status", handler.UpdateStatus)
 		v1.DELETE("/articles/:id", handler.Delete)
+		v1.GET("/authors", handler.ListAuthors)
+		v1.GET("/authors/:id/articles/count", handler.CountByAuthor)
+	}
+
+	// 导入接口接收 multipart 上传，复制和浏览计数接口没有请求体，均不经过 RequireJSON
+	upload := r.Group(handler.prefix)
+	upload.Use(middleware.NoStore())
+	{
+		upload.POST("/articles/import", handler.feature(FeatureImport), middleware.BodyLimit(handler.importMaxBytes), handler.Import)
+		upload.POST("/articles/:id/clone", handler.feature(FeatureClone), handler.Clone)
+		upload.POST("/articles/:id/view", handler.View)
 	}
 }
 
+// newArticleHandler applies opts to the defaults without registering any route
+func newArticleHandler(svc ArticleService, opts ...Option) *ArticleHandler {
+	handler := &ArticleHandler{
+		Service:         svc,
+		validator:       newValidator(),
+		logger:          logger.Default(),
+		maxPageSize:     defaultMaxPageSize,
+		defaultPageSize: defaultNum,
+		prefix:          defaultPrefix,
+		importMaxBytes:  defaultImportMaxBytes,
+		excerptLen:      defaultExcerptLength,
+		maxMetadata:     defaultMaxMetadataBytes,
+	}
+	for _, opt := range opts {
+		opt(handler)
+	}
+	handler.defaultPageSize = min(handler.defaultPageSize, handler.maxPageSize)
+	return handler
+}
+
+// feature is the route middleware checking that the feature is enabled, every feature is on without flags
+func (a *ArticleHandler) feature(name string) gin.HandlerFunc {
+	return middleware.RequireFeature(a.features, name)
+}
+
 // FetchArticle will fetch the article based on given params
 func (a *ArticleHandler) FetchArticle(c *gin.Context) {
-	numS := c.DefaultQuery("num", "10")
-	num, err := strconv.Atoi(numS)
-	if err != nil || num == 0 {
-		num = defaultNum
+	// ?fields=id,title 只返回指定字段
+	fields, err := parseFields(c)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppError(http.StatusBadRequest, "fields 参数错误", err.Error()))
+		return
+	}
+
+	// ?ids=1,2,3 按 id 批量查询，忽略分页与过滤参数
+	if _, ok := c.GetQuery("ids"); ok {
+		a.fetchByIDs(c, fields)
+		return
+	}
+
+	// ?title= 返回该标题的全部文章（标题不唯一），忽略分页与过滤参数
+	if title, ok := c.GetQuery("title"); ok {
+		a.findByTitle(c, title, fields)
+		return
+	}
+
+	num, err := a.pageSize(c)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(http.StatusBadRequest, "num 参数错误", err))
+		return
+	}
+
+	// 默认只列出已发布的文章，可通过 ?status= 查看其他状态
+	status := domain.ArticleStatus(c.DefaultQuery("status", string(domain.StatusPublished)))
+	if !status.Valid() {
+		middleware.HandleError(c, middleware.NewAppError(http.StatusBadRequest, "status 参数错误", string(status)))
+		return
+	}
+	filter := domain.ArticleFilter{Status: status}
+
+	// 可选的作者过滤 ?author_id=，必须为正整数
+	if value, ok := c.GetQuery("author_id"); ok {
+		filter.AuthorID, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || filter.AuthorID <= 0 {
+			middleware.HandleError(c, middleware.NewAppError(http.StatusBadRequest, "author_id 参数错误", "author_id 必须为正整数"))
+			return
+		}
+	}
+
+	// 可选的创建时间范围 ?from=&to=（RFC3339，闭区间）
+	if filter.CreatedFrom, err = parseTimeQuery(c, "from"); err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(http.StatusBadRequest, "from 参数错误", err))
+		return
+	}
+	if filter.CreatedTo, err = parseTimeQuery(c, "to"); err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(http.StatusBadRequest, "to 参数错误", err))
+		return
+	}
+	if !filter.CreatedFrom.IsZero() && !filter.CreatedTo.IsZero() && filter.CreatedFrom.After(filter.CreatedTo) {
+		middleware.HandleError(c, middleware.NewAppError(http.StatusBadRequest, "时间范围错误", "from 不能晚于 to"))
+		return
+	}
+
+	// 可选的标签过滤 ?tags=go,web&match=all|any，all 要求包含全部标签，any（默认）包含任一即可
+	if filter.Tags, filter.TagMatch, err = parseTagsQuery(c); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// ?direction=prev 配合 X-Prev-Cursor 读取上一页，不带 cursor 时读取最后一页
+	direction := domain.PageDirection(c.DefaultQuery("direction", string(domain.PageNext)))
+	if !direction.Valid() {
+		middleware.HandleError(c, middleware.NewAppError(http.StatusBadRequest, "direction 参数错误", "direction 只能为 next 或 prev"))
+		return
 	}
 
 	cursor := c.Query("cursor")
 	ctx := c.Request.Context()
 
-	listAr, nextCursor, err := a.Service.Fetch(ctx, cursor, int64(num))
+	listAr, page, err := a.Service.Fetch(ctx, cursor, direction, int64(num), filter)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "获取文章列表失败", err))
+		return
+	}
+
+	// 仅在显式请求时统计总数，避免每次分页都多一次 COUNT 查询
+	if c.Query("with_total") == "true" {
+		total, err := a.Service.Count(ctx, filter)
+		if err != nil {
+			middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "获取文章总数失败", err))
+			return
+		}
+		c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	}
+
+	// 最后一页也返回空的 X-Cursor（c.Header 传空值会删除该头），便于客户端判断已无下一页；
+	// 第一页同样返回空的 X-Prev-Cursor
+	c.Writer.Header().Set("X-Cursor", page.Next)
+	c.Writer.Header().Set("X-Prev-Cursor", page.Prev)
+	// 部分代理会丢弃非标准头，上一页与下一页地址同时通过标准的 Link 头（RFC 5988）返回
+	var links []string
+	if page.Next != "" {
+		links = append(links, nextLink(c, page.Next))
+	}
+	if page.Prev != "" {
+		links = append(links, pageLink(c, page.Prev, domain.PagePrev))
+	}
+	if len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+	respondJSON(c, http.StatusOK, withFields(a.excerptResponses(listAr), fields))
+}
+
+// nextLink is the Link header value pointing at the next page
+func nextLink(c *gin.Context, cursor string) string {
+	return pageLink(c, cursor, domain.PageNext)
+}
+
+// pageLink is the Link header value pointing at the page read from cursor in direction: the request URL with
+// the cursor replaced, every other query param is kept so the page uses the same filters and page size.
+// ?direction= is only kept for the previous page, next is the default.
+func pageLink(c *gin.Context, cursor string, direction domain.PageDirection) string {
+	query := c.Request.URL.Query()
+	query.Set("cursor", cursor)
+	query.Del("direction")
+	rel := "next"
+	if direction == domain.PagePrev {
+		query.Set("direction", string(domain.PagePrev))
+		rel = "prev"
+	}
+	link := url.URL{Path: c.Request.URL.Path, RawQuery: query.Encode()}
+	return "<" + link.String() + `>; rel="` + rel + `"`
+}
+
+// parseTagsQuery reads ?tags= as a comma separated list, ignoring blanks and duplicates, and ?match=
+func parseTagsQuery(c *gin.Context) ([]string, domain.TagMatch, error) {
+	match := domain.TagMatch(c.DefaultQuery("match", string(domain.TagMatchAny)))
+	if !match.Valid() {
+		return nil, "", middleware.NewAppError(http.StatusBadRequest, "match 参数错误", "match 只能为 all 或 any")
+	}
+	value, ok := c.GetQuery("tags")
+	if !ok {
+		return nil, "", nil
+	}
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) == 0 {
+		return nil, "", middleware.NewAppError(http.StatusBadRequest, "tags 参数错误", "tags 不能为空")
+	}
+	return tags, match, nil
+}
+
+// FetchRecent returns the latest published articles, newest first, for clients that do not page with cursors
+func (a *ArticleHandler) FetchRecent(c *gin.Context) {
+	fields, err := parseFields(c)
 	if err != nil {
-		middleware.HandleError(c, middleware.NewAppErrorWithErr(getStatusCode(err), "获取文章列表失败", err))
+		middleware.HandleError(c, middleware.NewAppError(http.StatusBadRequest, "fields 参数错误", err.Error()))
 		return
 	}
 
-	c.Header("X-Cursor", nextCursor)
-	c.JSON(http.StatusOK, listAr)
+	// ?limit= 默认 defaultRecentLimit，超过 maxPageSize 时按 maxPageSize 返回
+	limit := defaultRecentLimit
+	if value, ok := c.GetQuery("limit"); ok {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			middleware.HandleError(c, middleware.NewAppError(http.StatusBadRequest, "limit 参数错误", "limit 必须为正整数"))
+			return
+		}
+	}
+	limit = min(limit, a.maxPageSize)
+
+	listAr, err := a.Service.FetchRecent(c.Request.Context(), int64(limit))
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "获取文章列表失败", err))
+		return
+	}
+	respondJSON(c, http.StatusOK, withFields(a.excerptResponses(listAr), fields))
+}
+
+// ArticleChange is an entry of the changes feed, Deleted tells a syncing client to drop its copy of Article
+type ArticleChange struct {
+	Deleted bool            `json:"deleted"`
+	Article ArticleResponse `json:"article"`
+}
+
+// FetchChanges returns the articles updated after ?since= (RFC3339) in updated_at order, soft-deleted ones
+// included, so clients can sync incrementally. Pages continue with X-Cursor and Link like FetchArticle.
+func (a *ArticleHandler) FetchChanges(c *gin.Context) {
+	since, err := time.Parse(time.RFC3339, c.Query("since"))
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppError(http.StatusBadRequest, "since 参数错误", "since 必须为 RFC3339 格式的时间"))
+		return
+	}
+	num, err := a.pageSize(c)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(http.StatusBadRequest, "num 参数错误", err))
+		return
+	}
+
+	listAr, nextCursor, err := a.Service.FetchChanges(c.Request.Context(), since, c.Query("cursor"), int64(num))
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "获取文章列表失败", err))
+		return
+	}
+
+	changes := make([]ArticleChange, 0, len(listAr))
+	for _, ar := range listAr {
+		changes = append(changes, ArticleChange{Deleted: ar.DeletedAt != nil, Article: a.articleResponse(ar)})
+	}
+	c.Writer.Header().Set("X-Cursor", nextCursor)
+	if nextCursor != "" {
+		c.Header("Link", nextLink(c, nextCursor))
+	}
+	respondJSON(c, http.StatusOK, changes)
+}
+
+// ArticlesByIDResponse represent the response body of a ?ids= lookup, ids without an article are listed in Missing
+type ArticlesByIDResponse struct {
+	Data    []ArticleResponse `json:"data"`
+	Missing []int64           `json:"missing"`
+}
+
+// fetchByIDs looks up the comma separated ?ids= and reports the ones that do not exist instead of dropping them
+func (a *ArticleHandler) fetchByIDs(c *gin.Context, fields []string) {
+	ids, err := parseIDList(c.Query("ids"))
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(http.StatusBadRequest, "ids 参数错误", err))
+		return
+	}
+	if len(ids) > a.maxPageSize {
+		middleware.HandleError(c, middleware.NewAppError(http.StatusBadRequest, "ids 参数错误",
+			"最多查询 "+strconv.Itoa(a.maxPageSize)+" 个 id"))
+		return
+	}
+
+	listAr, missing, err := a.Service.GetByIDs(c.Request.Context(), ids)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "获取文章列表失败", err))
+		return
+	}
+	if missing == nil {
+		missing = []int64{}
+	}
+	respondJSON(c, http.StatusOK, ArticlesByIDResponse{Data: withFields(a.excerptResponses(listAr), fields), Missing: missing})
+}
+
+// findByTitle lists every article with the exact title, titles are not unique so there may be several
+func (a *ArticleHandler) findByTitle(c *gin.Context, title string, fields []string) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		middleware.HandleError(c, middleware.NewAppError(http.StatusBadRequest, "title 参数错误", "title 不能为空"))
+		return
+	}
+
+	listAr, err := a.Service.FindByTitle(c.Request.Context(), title)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "获取文章列表失败", err))
+		return
+	}
+	respondJSON(c, http.StatusOK, withFields(a.excerptResponses(listAr), fields))
+}
+
+// parseIDList parses a comma separated list of article ids, it must hold at least one id
+func parseIDList(value string) ([]int64, error) {
+	parts := strings.Split(value, ",")
+	ids := make([]int64, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseTimeQuery reads an optional RFC3339 query param, a missing param yields the zero time
+func parseTimeQuery(c *gin.Context, key string) (time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// pageSize reads the num query param, clamped to [1, maxPageSize]. A missing num uses the default page size.
+func (a *ArticleHandler) pageSize(c *gin.Context) (int, error) {
+	numS, ok := c.GetQuery("num")
+	if !ok || numS == "" {
+		return a.defaultPageSize, nil
+	}
+
+	num, err := strconv.Atoi(numS)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case num < 1:
+		return 1, nil
+	case num > a.maxPageSize:
+		return a.maxPageSize, nil
+	}
+	return num, nil
+}
+
+// Export will stream every article as newline-delimited JSON
+func (a *ArticleHandler) Export(c *gin.Context) {
+	a.streamArticles(c, "application/x-ndjson", nil, func(w io.Writer, ar domain.Article) error {
+		return json.NewEncoder(w).Encode(a.articleResponse(ar))
+	})
+}
+
+// streamArticles streams every article through encode without buffering them in memory.
+// writeHeader, if set, is written once before the first article, even when there is none.
+func (a *ArticleHandler) streamArticles(c *gin.Context, contentType string, writeHeader func(w io.Writer) error, encode func(w io.Writer, ar domain.Article) error) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	articles, errs := a.Service.FetchAll(ctx)
+
+	// wait for the first article so a failing query can still be reported as a proper error
+	first, ok := <-articles
+	if !ok {
+		if err := <-errs; err != nil {
+			middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "导出文章失败", err))
+			return
+		}
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+	if writeHeader != nil {
+		if err := writeHeader(c.Writer); err != nil {
+			a.logger.Error("Failed to write export header ", err)
+			return
+		}
+	}
+	if !ok {
+		return
+	}
+
+	next := first
+	c.Stream(func(w io.Writer) bool {
+		if err := encode(w, next); err != nil {
+			a.logger.Error("Failed to write exported article ", err)
+			return false
+		}
+		next, ok = <-articles
+		return ok
+	})
+
+	// stop the producer if the stream ended early, then wait for it to finish
+	cancel()
+	for range articles {
+		// discard what was produced before the cancellation
+	}
+	// the status is already sent, an error in the middle of the stream can only be logged
+	if err := <-errs; err != nil && !errors.Is(err, context.Canceled) {
+		a.logger.Error("Export stopped before the end ", err)
+	}
 }
 
 // GetByID will get article by given id
@@ -86,16 +666,107 @@ func (a *ArticleHandler) GetByID(c *gin.Context) {
 		return
 	}
 
+	fields, err := parseFields(c)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppError(http.StatusBadRequest, "fields 参数错误", err.Error()))
+		return
+	}
+
 	id := int64(idP)
 	ctx := c.Request.Context()
+	// ?fresh=true 或 Cache-Control: no-cache 时绕过服务端缓存直接读库，并用读到的值刷新缓存
+	fresh, err := wantsFresh(c)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppError(http.StatusBadRequest, "fresh 参数错误", "fresh 必须为 true 或 false"))
+		return
+	}
+	if fresh {
+		ctx = requestctx.WithBypassCache(ctx)
+	}
 
 	art, err := a.Service.GetByID(ctx, id)
 	if err != nil {
-		middleware.HandleError(c, middleware.NewAppErrorWithErr(getStatusCode(err), "获取文章失败", err))
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "获取文章失败", err))
+		return
+	}
+
+	res := a.articleResponse(art)
+	res.fields = fields
+	a.respondCacheable(c, EndpointGetByID, res, art.UpdatedAt)
+}
+
+// wantsFresh reports whether the client asked to bypass the server side cache, with ?fresh=true
+// or a Cache-Control: no-cache request header
+func wantsFresh(c *gin.Context) (bool, error) {
+	if value, ok := c.GetQuery("fresh"); ok {
+		return strconv.ParseBool(value)
+	}
+	for _, directive := range strings.Split(c.GetHeader("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-cache") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// UpdateStatusRequest represent the request body of UpdateStatus
+type UpdateStatusRequest struct {
+	Status domain.ArticleStatus `json:"status" validate:"required"`
+}
+
+// UpdateStatus will move the article to the given lifecycle status
+func (a *ArticleHandler) UpdateStatus(c *gin.Context) {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	var req UpdateStatusRequest
+	if err := bindStrictJSON(c, &req); err != nil {
+		middleware.HandleError(c, bindError(err))
+		return
+	}
+	if err := a.validator.Struct(req); err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(http.StatusBadRequest, "参数验证失败", err))
 		return
 	}
 
-	c.JSON(http.StatusOK, art)
+	ctx := c.Request.Context()
+	art, err := a.Service.UpdateStatus(ctx, int64(idP), req.Status)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "更新文章状态失败", err))
+		return
+	}
+
+	respondJSON(c, http.StatusOK, a.articleResponse(art))
+}
+
+// GetBySlug will get article by given slug
+func (a *ArticleHandler) GetBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+	ctx := c.Request.Context()
+
+	art, err := a.Service.GetBySlug(ctx, slug)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "获取文章失败", err))
+		return
+	}
+
+	a.respondCacheable(c, EndpointGetBySlug, a.articleResponse(art), time.Time{})
+}
+
+// newValidator reports field errors by their json name so they match the request body
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name, _, _ := strings.Cut(fld.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
 }
 
 func (a *ArticleHandler) isRequestValid(m *domain.Article) (bool, error) {
@@ -103,20 +774,89 @@ func (a *ArticleHandler) isRequestValid(m *domain.Article) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	if err := a.checkMetadata(m); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// MetadataTooLargeError reports metadata whose JSON encoding is bigger than the configured limit
+type MetadataTooLargeError struct {
+	Size int
+	Max  int
+}
+
+func (e *MetadataTooLargeError) Error() string {
+	return fmt.Sprintf("metadata is %d bytes, the limit is %d", e.Size, e.Max)
+}
+
+// checkMetadata enforces the size cap of the metadata, that it is a JSON object is already
+// guaranteed by decoding it into a map
+func (a *ArticleHandler) checkMetadata(m *domain.Article) error {
+	if len(m.Metadata) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(m.Metadata)
+	if err != nil {
+		return err
+	}
+	if len(data) > a.maxMetadata {
+		return &MetadataTooLargeError{Size: len(data), Max: a.maxMetadata}
+	}
+	return nil
+}
+
+// bindWithSchema validates the raw body against the JSON Schema before decoding it into m
+func (a *ArticleHandler) bindWithSchema(c *gin.Context, m *domain.Article) (bool, error) {
+	body, err := c.GetRawData()
+	if err != nil {
+		return false, err
+	}
+	if err := validateSchema(a.schema, body); err != nil {
+		return false, err
+	}
+	if err := checkUnknownFields(body, reflect.TypeOf(m), ""); err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(body, m); err != nil {
+		return false, err
+	}
+	if err := a.checkMetadata(m); err != nil {
+		return false, err
+	}
 	return true, nil
 }
 
 // Store will store the article by given request body
 func (a *ArticleHandler) Store(c *gin.Context) {
-	var article domain.Article
-	if err := c.ShouldBindJSON(&article); err != nil {
-		middleware.HandleError(c, middleware.NewAppErrorWithErr(http.StatusBadRequest, "请求参数错误", err))
+	if err := a.acceptTimeFormat(c); err != nil {
+		middleware.HandleError(c, bindError(err))
 		return
 	}
 
+	var article domain.Article
 	var ok bool
 	var err error
-	if ok, err = a.isRequestValid(&article); !ok {
+	if a.schema != nil {
+		ok, err = a.bindWithSchema(c, &article)
+		// 未知字段（多为拼写错误）即使在 validate_only 下也直接返回 400
+		var unknown *UnknownFieldError
+		if errors.As(err, &unknown) {
+			middleware.HandleError(c, bindError(err))
+			return
+		}
+	} else {
+		if err := bindStrictJSON(c, &article); err != nil {
+			middleware.HandleError(c, bindError(err))
+			return
+		}
+		ok, err = a.isRequestValid(&article)
+	}
+	if c.Query("validate_only") == "true" {
+		respondJSON(c, http.StatusOK, toValidationResult(err))
+		return
+	}
+	if !ok {
 		middleware.HandleError(c, middleware.NewAppErrorWithErr(http.StatusBadRequest, "参数验证失败", err))
 		return
 	}
@@ -124,11 +864,225 @@ func (a *ArticleHandler) Store(c *gin.Context) {
 	ctx := c.Request.Context()
 	err = a.Service.Store(ctx, &article)
 	if err != nil {
-		middleware.HandleError(c, middleware.NewAppErrorWithErr(getStatusCode(err), "创建文章失败", err))
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "创建文章失败", err))
 		return
 	}
 
-	c.JSON(http.StatusCreated, article)
+	// 201 响应通过 Location 指向新建的文章
+	c.Header("Location", a.articleLocation(article.ID))
+	respondJSON(c, http.StatusCreated, a.articleResponse(article))
+}
+
+// Upsert will create the article at the given id (201), or replace it when it already exists (200)
+func (a *ArticleHandler) Upsert(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	if err := a.acceptTimeFormat(c); err != nil {
+		middleware.HandleError(c, bindError(err))
+		return
+	}
+
+	var article domain.Article
+	var ok bool
+	if a.schema != nil {
+		ok, err = a.bindWithSchema(c, &article)
+	} else {
+		if err := bindStrictJSON(c, &article); err != nil {
+			middleware.HandleError(c, bindError(err))
+			return
+		}
+		ok, err = a.isRequestValid(&article)
+	}
+	if !ok {
+		var unknown *UnknownFieldError
+		if errors.As(err, &unknown) {
+			middleware.HandleError(c, bindError(err))
+			return
+		}
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(http.StatusBadRequest, "参数验证失败", err))
+		return
+	}
+	// 请求体中的 id 可以省略，但不能与路径中的 id 不一致
+	if article.ID != 0 && article.ID != id {
+		middleware.HandleError(c, middleware.NewAppError(http.StatusBadRequest, "请求参数错误", "id 与路径不一致"))
+		return
+	}
+	article.ID = id
+
+	created, err := a.Service.Upsert(c.Request.Context(), &article)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "保存文章失败", err))
+		return
+	}
+
+	if created {
+		c.Header("Location", a.articleLocation(article.ID))
+		respondJSON(c, http.StatusCreated, a.articleResponse(article))
+		return
+	}
+	respondJSON(c, http.StatusOK, a.articleResponse(article))
+}
+
+// articleLocation is the URL path of the article id under the configured prefix
+func (a *ArticleHandler) articleLocation(id int64) string {
+	return path.Join(a.prefix, "articles", strconv.FormatInt(id, 10))
+}
+
+// toValidationResult converts the validator error into the field-error list of a ValidationResult
+func toValidationResult(err error) ValidationResult {
+	if err == nil {
+		return ValidationResult{Valid: true}
+	}
+
+	var schemaErr *SchemaError
+	if errors.As(err, &schemaErr) {
+		return ValidationResult{Errors: schemaErr.Errors}
+	}
+	var tooLarge *MetadataTooLargeError
+	if errors.As(err, &tooLarge) {
+		return ValidationResult{Errors: []FieldError{{Field: "metadata", Rule: "max"}}}
+	}
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return ValidationResult{Errors: []FieldError{{Rule: err.Error()}}}
+	}
+	res := ValidationResult{Errors: make([]FieldError, 0, len(validationErrs))}
+	for _, fe := range validationErrs {
+		res.Errors = append(res.Errors, FieldError{Field: fe.Field(), Rule: fe.Tag()})
+	}
+	return res
+}
+
+// Clone will store a draft copy of the given article
+func (a *ArticleHandler) Clone(c *gin.Context) {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	art, err := a.Service.Clone(c.Request.Context(), int64(idP))
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "复制文章失败", err))
+		return
+	}
+
+	c.Header("Location", a.articleLocation(art.ID))
+	respondJSON(c, http.StatusCreated, a.articleResponse(art))
+}
+
+// View will count one more view of the given article
+func (a *ArticleHandler) View(c *gin.Context) {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	if err := a.Service.IncrementViewCount(c.Request.Context(), int64(idP)); err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "记录浏览失败", err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// NeighborsResponse represent the response body of GetNeighbors, a missing neighbor is null
+type NeighborsResponse struct {
+	Prev *ArticleResponse `json:"prev"`
+	Next *ArticleResponse `json:"next"`
+}
+
+// GetNeighbors will return the published articles right before and after the given one
+func (a *ArticleHandler) GetNeighbors(c *gin.Context) {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	prev, next, err := a.Service.GetNeighbors(c.Request.Context(), int64(idP))
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "获取相邻文章失败", err))
+		return
+	}
+
+	var res NeighborsResponse
+	if prev != nil {
+		r := a.articleResponse(*prev)
+		res.Prev = &r
+	}
+	if next != nil {
+		r := a.articleResponse(*next)
+		res.Next = &r
+	}
+	respondJSON(c, http.StatusOK, res)
+}
+
+// AuthorResponse represent an author listed by ListAuthors, ArticleCount is only set with ?with_counts=true
+type AuthorResponse struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	ArticleCount *int64 `json:"article_count,omitempty"`
+}
+
+// ListAuthors will return every author ordered by id, ?with_counts=true adds how many articles each wrote
+func (a *ArticleHandler) ListAuthors(c *gin.Context) {
+	withCounts := false
+	if value, ok := c.GetQuery("with_counts"); ok {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			middleware.HandleError(c, middleware.NewAppError(http.StatusBadRequest, "with_counts 参数错误", "with_counts 必须为 true 或 false"))
+			return
+		}
+		withCounts = parsed
+	}
+
+	// 作者与文章数由同一条聚合查询返回，不需要文章数时直接丢弃
+	stats, err := a.Service.ListAuthorsWithCounts(c.Request.Context())
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "获取作者列表失败", err))
+		return
+	}
+
+	res := make([]AuthorResponse, 0, len(stats))
+	for _, stat := range stats {
+		author := AuthorResponse{ID: stat.Author.ID, Name: stat.Author.Name}
+		if withCounts {
+			count := stat.ArticleCount
+			author.ArticleCount = &count
+		}
+		res = append(res, author)
+	}
+	respondJSON(c, http.StatusOK, res)
+}
+
+// AuthorArticleCount represent the response body of CountByAuthor
+type AuthorArticleCount struct {
+	AuthorID int64 `json:"author_id"`
+	Count    int64 `json:"count"`
+}
+
+// CountByAuthor will return how many articles the given author wrote
+func (a *ArticleHandler) CountByAuthor(c *gin.Context) {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	authorID := int64(idP)
+	total, err := a.Service.CountByAuthor(c.Request.Context(), authorID)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "获取作者文章数失败", err))
+		return
+	}
+
+	respondJSON(c, http.StatusOK, AuthorArticleCount{AuthorID: authorID, Count: total})
 }
 
 // Delete will delete article by given param
@@ -143,28 +1097,65 @@ func (a *ArticleHandler) Delete(c *gin.Context) {
 	id := int64(idP)
 	ctx := c.Request.Context()
 
+	// If-Unmodified-Since：文章在客户端上次读取后被修改过则拒绝删除
+	var since time.Time
+	if header := c.GetHeader("If-Unmodified-Since"); header != "" {
+		since, err = http.ParseTime(header)
+		if err != nil {
+			middleware.HandleError(c, middleware.NewAppErrorWithErr(http.StatusBadRequest, "If-Unmodified-Since 格式错误", err))
+			return
+		}
+	}
+	// ?return=representation 时在响应中返回被删除的文章（例如用于撤销）
+	representation := c.Query("return") == "representation"
+
+	var art domain.Article
+	if !since.IsZero() || representation {
+		art, err = a.Service.GetByID(ctx, id)
+		if err != nil {
+			middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "删除文章失败", err))
+			return
+		}
+		// HTTP 日期只精确到秒
+		if !since.IsZero() && art.UpdatedAt.Truncate(time.Second).After(since) {
+			middleware.HandleError(c, middleware.ErrPreconditionFailed)
+			return
+		}
+	}
+
 	err = a.Service.Delete(ctx, id)
 	if err != nil {
-		middleware.HandleError(c, middleware.NewAppErrorWithErr(getStatusCode(err), "删除文章失败", err))
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "删除文章失败", err))
 		return
 	}
 
+	if representation {
+		respondJSON(c, http.StatusOK, a.articleResponse(art))
+		return
+	}
 	c.Status(http.StatusNoContent)
 }
 
-func getStatusCode(err error) int {
+func (a *ArticleHandler) getStatusCode(err error) int {
 	if err == nil {
 		return http.StatusOK
 	}
 
-	log.Error("Error occurred while processing request", err)
-	switch err {
-	case domain.ErrInternalServerError:
+	a.logger.Error("Error occurred while processing request ", err)
+	return statusCode(err)
+}
+
+// statusCode maps the domain errors to HTTP status codes, including ones wrapped with context
+func statusCode(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInternalServerError):
 		return http.StatusInternalServerError
-	case domain.ErrNotFound:
+	case errors.Is(err, domain.ErrNotFound):
 		return http.StatusNotFound
-	case domain.ErrConflict:
+	case errors.Is(err, domain.ErrConflict):
 		return http.StatusConflict
+	case errors.Is(err, domain.ErrBadParamInput):
+		return http.StatusBadRequest
 	default:
 		return http.StatusInternalServerError
 	}
@@ -1,95 +1,1237 @@
 package handler_test
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/bxcodec/go-clean-arch/domain"
 	"github.com/bxcodec/go-clean-arch/internal/handler"
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
 	"github.com/bxcodec/go-clean-arch/internal/handler/mocks"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/requestctx"
 	"github.com/gin-gonic/gin"
 	faker "github.com/go-faker/faker/v4"
+	"github.com/go-faker/faker/v4/pkg/options"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
+const defaultNum = 10
+
+var published = domain.ArticleFilter{Status: domain.StatusPublished}
+
 func setupRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
-	return gin.New()
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware())
+	return r
 }
 
 func TestFetch(t *testing.T) {
 	var mockArticle domain.Article
-	err := faker.FakeData(&mockArticle)
+	err := faker.FakeData(&mockArticle, options.WithFieldsToIgnore("Metadata"))
 	assert.NoError(t, err)
 
 	mockUCase := new(mocks.ArticleService)
-	mockListArticle := make([]domain.Article, 0)
-	mockListArticle = append(mockListArticle, mockArticle)
-	num := 1
-	cursor := "2"
-	mockUCase.On("Fetch", mock.Anything, cursor, int64(num)).Return(mockListArticle, "10", nil)
+	mockListArticle := make([]domain.Article, 0)
+	mockListArticle = append(mockListArticle, mockArticle)
+	num := 1
+	cursor := "2"
+	mockUCase.On("Fetch", mock.Anything, cursor, domain.PageNext, int64(num), published).Return(mockListArticle, domain.PageCursors{Next: "10"}, nil)
+
+	r := setupRouter()
+	handler.NewArticleHandler(r, mockUCase)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/articles?num=1&cursor="+cursor, nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	responseCursor := w.Header().Get("X-Cursor")
+	assert.Equal(t, "10", responseCursor)
+	assert.Equal(t, `</api/v1/articles?cursor=10&num=1>; rel="next"`, w.Header().Get("Link"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestFetchNextLink(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	filter := domain.ArticleFilter{Status: domain.StatusDraft, AuthorID: 7}
+	// cursors are base64 and may hold characters that have to be escaped in the URL
+	mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, int64(2), filter).Return([]domain.Article{{ID: 1}, {ID: 2}}, domain.PageCursors{Next: "MjAyNC0wMS0wMVQwMDowMDowMFosMg=="}, nil).Once()
+
+	r := setupRouter()
+	handler.NewArticleHandler(r, mockUCase)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles?num=2&status=draft&author_id=7", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "MjAyNC0wMS0wMVQwMDowMDowMFosMg==", w.Header().Get("X-Cursor"))
+	link := w.Header().Get("Link")
+	require.True(t, strings.HasPrefix(link, "<") && strings.HasSuffix(link, `>; rel="next"`), link)
+
+	// following the link requests the next page with the same filters
+	next, err := url.Parse(strings.TrimSuffix(strings.TrimPrefix(link, "<"), `>; rel="next"`))
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/articles", next.Path)
+	assert.Equal(t, "MjAyNC0wMS0wMVQwMDowMDowMFosMg==", next.Query().Get("cursor"))
+	assert.Equal(t, "2", next.Query().Get("num"))
+	assert.Equal(t, "draft", next.Query().Get("status"))
+	assert.Equal(t, "7", next.Query().Get("author_id"))
+	mockUCase.AssertExpectations(t)
+}
+
+func TestFetchPrevious(t *testing.T) {
+	t.Run("both-cursors", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "5", domain.PagePrev, int64(2), published).
+			Return([]domain.Article{{ID: 3}, {ID: 4}}, domain.PageCursors{Next: "4", Prev: "3"}, nil).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles?num=2&cursor=5&direction=prev", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "4", w.Header().Get("X-Cursor"))
+		assert.Equal(t, "3", w.Header().Get("X-Prev-Cursor"))
+		assert.Equal(t, `</api/v1/articles?cursor=4&num=2>; rel="next", </api/v1/articles?cursor=3&direction=prev&num=2>; rel="prev"`,
+			w.Header().Get("Link"))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("first-page", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, int64(2), published).
+			Return([]domain.Article{{ID: 1}, {ID: 2}}, domain.PageCursors{Next: "2"}, nil).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles?num=2", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get("X-Cursor"))
+		// the header is present but empty, there is no previous page
+		assert.Equal(t, []string{""}, w.Header().Values("X-Prev-Cursor"))
+		assert.Equal(t, `</api/v1/articles?cursor=2&num=2>; rel="next"`, w.Header().Get("Link"))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("invalid-direction", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles?direction=back", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFetchError(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	num := 1
+	cursor := "2"
+	mockUCase.On("Fetch", mock.Anything, cursor, domain.PageNext, int64(num), published).Return(nil, domain.PageCursors{}, domain.ErrInternalServerError)
+
+	r := setupRouter()
+	handler.NewArticleHandler(r, mockUCase)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/articles?num=1&cursor="+cursor, nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	responseCursor := w.Header().Get("X-Cursor")
+	assert.Equal(t, "", responseCursor)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestFetchEmpty(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, int64(10), published).Return(nil, domain.PageCursors{}, nil)
+
+	r := setupRouter()
+	handler.NewArticleHandler(r, mockUCase)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/articles", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "[]", w.Body.String())
+	_, ok := w.Header()["X-Cursor"]
+	assert.True(t, ok)
+	assert.Equal(t, "", w.Header().Get("X-Cursor"))
+	// the last page has no next link
+	assert.Empty(t, w.Header().Get("Link"))
+	mockUCase.AssertExpectations(t)
+}
+
+func TestFetchChanges(t *testing.T) {
+	since := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("valid-since", func(t *testing.T) {
+		deletedAt := since.Add(time.Hour)
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("FetchChanges", mock.Anything, mock.MatchedBy(since.Equal), "", int64(2)).Return([]domain.Article{
+			{ID: 3, Title: "Updated", Content: "Content", UpdatedAt: since.Add(time.Minute)},
+			{ID: 1, Title: "Deleted", Content: "Content", UpdatedAt: deletedAt, DeletedAt: &deletedAt},
+		}, "next", nil).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/changes?since=2024-05-01T10:00:00Z&num=2", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var res []handler.ArticleChange
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		if assert.Len(t, res, 2) {
+			assert.False(t, res[0].Deleted)
+			assert.Equal(t, int64(3), res[0].Article.ID)
+			assert.Equal(t, "Content", res[0].Article.Content)
+			assert.True(t, res[1].Deleted)
+			assert.Equal(t, int64(1), res[1].Article.ID)
+		}
+		assert.Equal(t, "next", w.Header().Get("X-Cursor"))
+		assert.Equal(t, `</api/v1/articles/changes?cursor=next&num=2&since=2024-05-01T10%3A00%3A00Z>; rel="next"`, w.Header().Get("Link"))
+		mockUCase.AssertExpectations(t)
+	})
+
+	for _, query := range []string{"", "?since=", "?since=2024-05-01", "?since=yesterday"} {
+		t.Run("invalid-since"+query, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/changes"+query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "since 参数错误")
+			mockUCase.AssertNotCalled(t, "FetchChanges", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("invalid-cursor", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("FetchChanges", mock.Anything, mock.Anything, "forged", int64(10)).
+			Return(nil, "", domain.ErrBadParamInput).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/changes?since=2024-05-01T10:00:00Z&cursor=forged", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestFetchWithTotal(t *testing.T) {
+	t.Run("requested", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, int64(defaultNum), published).Return([]domain.Article{}, domain.PageCursors{}, nil)
+		mockUCase.On("Count", mock.Anything, published).Return(int64(42), nil)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles?with_total=true", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "42", w.Header().Get("X-Total-Count"))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("not-requested", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, int64(defaultNum), published).Return([]domain.Article{}, domain.PageCursors{}, nil)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		_, ok := w.Header()["X-Total-Count"]
+		assert.False(t, ok)
+		mockUCase.AssertNotCalled(t, "Count", mock.Anything, mock.Anything)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("count-error", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, int64(defaultNum), published).Return([]domain.Article{}, domain.PageCursors{}, nil)
+		mockUCase.On("Count", mock.Anything, published).Return(int64(0), domain.ErrInternalServerError)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles?with_total=true", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestFetchStatusParam(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected domain.ArticleStatus
+	}{
+		{name: "default-published", query: "", expected: domain.StatusPublished},
+		{name: "draft", query: "?status=draft", expected: domain.StatusDraft},
+		{name: "archived", query: "?status=archived", expected: domain.StatusArchived},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, int64(defaultNum), domain.ArticleFilter{Status: tt.expected}).
+				Return([]domain.Article{}, domain.PageCursors{}, nil).Once()
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/articles"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			mockUCase.AssertExpectations(t)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles?status=deleted", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFetchAuthorParam(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		filter := domain.ArticleFilter{Status: domain.StatusPublished, AuthorID: 7}
+		mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, int64(defaultNum), filter).Return([]domain.Article{{ID: 1, Author: domain.Author{ID: 7}}}, domain.PageCursors{}, nil).Once()
+		mockUCase.On("Count", mock.Anything, filter).Return(int64(1), nil).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles?author_id=7&with_total=true", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "1", w.Header().Get("X-Total-Count"))
+		mockUCase.AssertExpectations(t)
+	})
+
+	for name, value := range map[string]string{"zero": "0", "negative": "-3", "not-a-number": "abc", "empty": ""} {
+		t.Run(name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/articles?author_id="+value, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestFetchTagsParam(t *testing.T) {
+	valid := []struct {
+		name  string
+		query string
+		tags  []string
+		match domain.TagMatch
+	}{
+		{name: "all", query: "?tags=go,web&match=all", tags: []string{"go", "web"}, match: domain.TagMatchAll},
+		{name: "any", query: "?tags=go,web&match=any", tags: []string{"go", "web"}, match: domain.TagMatchAny},
+		{name: "default-any", query: "?tags=go", tags: []string{"go"}, match: domain.TagMatchAny},
+		{name: "blanks-and-duplicates", query: "?tags=go,%20web%20,,go&match=all", tags: []string{"go", "web"}, match: domain.TagMatchAll},
+	}
+	for _, tt := range valid {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			filter := domain.ArticleFilter{Status: domain.StatusPublished, Tags: tt.tags, TagMatch: tt.match}
+			mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, int64(defaultNum), filter).Return([]domain.Article{}, domain.PageCursors{}, nil).Once()
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles"+tt.query, nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			mockUCase.AssertExpectations(t)
+		})
+	}
+
+	invalid := []struct {
+		name    string
+		query   string
+		message string
+	}{
+		{name: "unknown-match", query: "?tags=go&match=some", message: "match 参数错误"},
+		{name: "match-case", query: "?tags=go&match=ALL", message: "match 参数错误"},
+		{name: "empty-tags", query: "?tags=,,&match=all", message: "tags 参数错误"},
+		{name: "repeated-tags", query: "?tags=go&tags=web", message: "查询参数重复"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles"+tt.query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tt.message)
+			mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestFetchTimeRange(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)
+
+	t.Run("valid", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		filter := domain.ArticleFilter{Status: domain.StatusPublished, CreatedFrom: from, CreatedTo: to}
+		mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, int64(defaultNum), filter).Return([]domain.Article{}, domain.PageCursors{}, nil).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles?from=2024-01-01T00:00:00Z&to=2024-01-31T23:59:59Z", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	invalid := []struct {
+		name  string
+		query string
+	}{
+		{name: "from-after-to", query: "?from=2024-02-01T00:00:00Z&to=2024-01-01T00:00:00Z"},
+		{name: "malformed-from", query: "?from=2024-01-01"},
+		{name: "malformed-to", query: "?to=yesterday"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/articles"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		err      error
+		expected int
+	}{
+		{name: "success", body: `{"status":"published"}`, expected: http.StatusOK},
+		{name: "invalid-transition", body: `{"status":"archived"}`, err: domain.ErrConflict, expected: http.StatusConflict},
+		{name: "unknown-status", body: `{"status":"deleted"}`, err: domain.ErrBadParamInput, expected: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req handler.UpdateStatusRequest
+			assert.NoError(t, json.Unmarshal([]byte(tt.body), &req))
+
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On("UpdateStatus", mock.Anything, int64(1), req.Status).
+				Return(domain.Article{ID: 1, Status: req.Status}, tt.err).Once()
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase)
+
+			httpReq := httptest.NewRequest(http.MethodPut, "/api/v1/articles/1/status", strings.NewReader(tt.body))
+			httpReq.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, httpReq)
+
+			assert.Equal(t, tt.expected, w.Code)
+			mockUCase.AssertExpectations(t)
+		})
+	}
+
+	t.Run("missing-status", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		httpReq := httptest.NewRequest(http.MethodPut, "/api/v1/articles/1/status", strings.NewReader(`{}`))
+		httpReq.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUCase.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFetchNumParam(t *testing.T) {
+	tests := []struct {
+		name     string
+		num      string
+		expected int64
+	}{
+		{name: "absent", num: "", expected: defaultNum},
+		{name: "zero", num: "0", expected: 1},
+		{name: "negative", num: "-5", expected: 1},
+		{name: "over-max", num: "1000000", expected: 50},
+		{name: "in-range", num: "20", expected: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, tt.expected, published).Return([]domain.Article{}, domain.PageCursors{}, nil).Once()
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase, handler.WithMaxPageSize(50))
+
+			target := "/api/v1/articles"
+			if tt.num != "" {
+				target += "?num=" + tt.num
+			}
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			mockUCase.AssertExpectations(t)
+		})
+	}
+
+	t.Run("configured-default", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, int64(25), published).Return([]domain.Article{}, domain.PageCursors{}, nil).Once()
+		mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, int64(50), published).Return([]domain.Article{}, domain.PageCursors{}, nil).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase, handler.WithMaxPageSize(50), handler.WithDefaultPageSize(25))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles?num=500", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("default-over-max", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, int64(50), published).Return([]domain.Article{}, domain.PageCursors{}, nil).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase, handler.WithMaxPageSize(50), handler.WithDefaultPageSize(80))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("non-numeric", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase, handler.WithMaxPageSize(50))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles?num=ten", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFetchDuplicatedParams(t *testing.T) {
+	for _, query := range []string{"?num=1&num=9999", "?cursor=a&cursor=b"} {
+		t.Run(query, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/articles"+query, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestFetchInvalidCursor(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	cursor := "tampered"
+	mockUCase.On("Fetch", mock.Anything, cursor, domain.PageNext, int64(defaultNum), published).Return(nil, domain.PageCursors{}, domain.ErrBadParamInput)
+
+	r := setupRouter()
+	handler.NewArticleHandler(r, mockUCase)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/articles?cursor="+cursor, nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestExport(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	articles := make(chan domain.Article, 3)
+	errs := make(chan error)
+	for i := 1; i <= 3; i++ {
+		articles <- domain.Article{ID: int64(i), Title: "Title", Content: "Content"}
+	}
+	close(articles)
+	close(errs)
+	mockUCase.On("FetchAll", mock.Anything).Return((<-chan domain.Article)(articles), (<-chan error)(errs))
+
+	r := setupRouter()
+	handler.NewArticleHandler(r, mockUCase)
+
+	// c.Stream needs a real connection, a ResponseRecorder cannot notify a closed client
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/v1/articles/export")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	count := 0
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var ar domain.Article
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &ar))
+		count++
+		assert.Equal(t, int64(count), ar.ID)
+	}
+	assert.NoError(t, scanner.Err())
+	assert.Equal(t, 3, count)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestExportResponseOptions(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	articles := make(chan domain.Article, 1)
+	errs := make(chan error)
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	articles <- domain.Article{ID: 1, Title: "Title", Content: "one two", CreatedAt: created, UpdatedAt: created}
+	close(articles)
+	close(errs)
+	mockUCase.On("FetchAll", mock.Anything).Return((<-chan domain.Article)(articles), (<-chan error)(errs))
+
+	// every line is encoded like the response of GET /articles/:id
+	r := setupRouter()
+	handler.NewArticleHandler(r, mockUCase, handler.WithStringIDs(), handler.WithTimeFormat(handler.TimeFormatUnix))
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/v1/articles/export")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var res map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+	assert.Equal(t, "1", res["id"])
+	assert.Equal(t, float64(created.Unix()), res["created_at"])
+	assert.Equal(t, float64(2), res["word_count"])
+	mockUCase.AssertExpectations(t)
+}
+
+func TestExportCSV(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	articles := make(chan domain.Article, 2)
+	errs := make(chan error)
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	articles <- domain.Article{ID: 1, Title: "Hello, World", Slug: "hello-world", Status: domain.StatusPublished,
+		Content: "Line one\nLine \"two\"", Author: domain.Author{ID: 7}, CreatedAt: created, UpdatedAt: created}
+	articles <- domain.Article{ID: 2, Title: "Plain", Content: "Content"}
+	close(articles)
+	close(errs)
+	mockUCase.On("FetchAll", mock.Anything).Return((<-chan domain.Article)(articles), (<-chan error)(errs))
+
+	r := setupRouter()
+	handler.NewArticleHandler(r, mockUCase)
+
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/v1/articles/export.csv")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="articles.csv"`, resp.Header.Get("Content-Disposition"))
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, []string{"id", "title", "slug", "status", "content", "author_id", "created_at", "updated_at"}, records[0])
+	assert.Equal(t, []string{"1", "Hello, World", "hello-world", "published", "Line one\nLine \"two\"", "7",
+		"2024-01-02T03:04:05Z", "2024-01-02T03:04:05Z"}, records[1])
+	assert.Equal(t, "2", records[2][0])
+	mockUCase.AssertExpectations(t)
+}
+
+func TestFetchByIDs(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("GetByIDs", mock.Anything, []int64{1, 2, 999}).
+		Return([]domain.Article{{ID: 1, Title: "one"}, {ID: 2, Title: "two"}}, []int64{999}, nil)
+
+	r := setupRouter()
+	handler.NewArticleHandler(r, mockUCase)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/articles?ids=1,2,999", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var res handler.ArticlesByIDResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	if assert.Len(t, res.Data, 2) {
+		assert.Equal(t, int64(1), res.Data[0].ID)
+		assert.Equal(t, int64(2), res.Data[1].ID)
+	}
+	assert.Equal(t, []int64{999}, res.Missing)
+	mockUCase.AssertExpectations(t)
+	mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFetchByTitle(t *testing.T) {
+	t.Run("several-matches", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("FindByTitle", mock.Anything, "Same title").
+			Return([]domain.Article{{ID: 1, Title: "Same title"}, {ID: 4, Title: "Same title"}}, nil).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles?title=Same+title&num=1", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var res []handler.ArticleResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		if assert.Len(t, res, 2) {
+			assert.Equal(t, int64(1), res[0].ID)
+			assert.Equal(t, int64(4), res[1].ID)
+		}
+		mockUCase.AssertExpectations(t)
+		mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("no-match", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("FindByTitle", mock.Anything, "missing").Return([]domain.Article{}, nil).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles?title=missing", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, "[]", w.Body.String())
+	})
+
+	t.Run("empty-title", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles?title=+", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUCase.AssertNotCalled(t, "FindByTitle", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFetchByIDsNoneMissing(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("GetByIDs", mock.Anything, []int64{1}).Return([]domain.Article{{ID: 1}}, nil, nil)
+
+	r := setupRouter()
+	handler.NewArticleHandler(r, mockUCase)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/articles?ids=1", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"missing":[]`)
+}
+
+func TestFetchByIDsInvalid(t *testing.T) {
+	for name, query := range map[string]string{
+		"not-a-number": "ids=1,abc",
+		"empty":        "ids=",
+		"too-many":     "ids=" + strings.TrimSuffix(strings.Repeat("1,", 101), ","),
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/articles?"+query, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockUCase.AssertNotCalled(t, "GetByIDs", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestFetchRecent(t *testing.T) {
+	list := []domain.Article{{ID: 2, Title: "newer"}, {ID: 1, Title: "older"}}
+	tests := []struct {
+		name  string
+		query string
+		limit int64
+	}{
+		{name: "default-limit", query: "", limit: 5},
+		{name: "custom-limit", query: "?limit=2", limit: 2},
+		{name: "clamped-to-max", query: "?limit=1000", limit: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On("FetchRecent", mock.Anything, tt.limit).Return(list, nil).Once()
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/recent"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var res []handler.ArticleResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			if assert.Len(t, res, 2) {
+				assert.Equal(t, int64(2), res[0].ID)
+				assert.Equal(t, int64(1), res[1].ID)
+			}
+			mockUCase.AssertExpectations(t)
+		})
+	}
+
+	for name, query := range map[string]string{
+		"not-a-number": "?limit=abc",
+		"zero":         "?limit=0",
+		"negative":     "?limit=-1",
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/recent"+query, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockUCase.AssertNotCalled(t, "FetchRecent", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetByID(t *testing.T) {
+	var mockArticle domain.Article
+	err := faker.FakeData(&mockArticle, options.WithFieldsToIgnore("Metadata"))
+	assert.NoError(t, err)
+
+	mockUCase := new(mocks.ArticleService)
+	num := int(mockArticle.ID)
+	mockUCase.On("GetByID", mock.Anything, int64(num)).Return(mockArticle, nil)
+
+	r := setupRouter()
+	handler.NewArticleHandler(r, mockUCase)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/"+strconv.Itoa(num), nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestHeadByID(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	maxAge := handler.WithCacheMaxAge(map[string]time.Duration{handler.EndpointGetByID: time.Minute})
+
+	t.Run("found", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(1)).
+			Return(domain.Article{ID: 1, Title: "Title", Content: "Content", UpdatedAt: updatedAt}, nil).Twice()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase, maxAge)
+
+		get := httptest.NewRecorder()
+		r.ServeHTTP(get, httptest.NewRequest(http.MethodGet, "/api/v1/articles/1", nil))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/api/v1/articles/1", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.NotEmpty(t, w.Header().Get("ETag"))
+		assert.Equal(t, get.Header().Get("ETag"), w.Header().Get("ETag"))
+		assert.Equal(t, get.Header().Get("Cache-Control"), w.Header().Get("Cache-Control"))
+		assert.Equal(t, get.Header().Get("Content-Type"), w.Header().Get("Content-Type"))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{}, domain.ErrNotFound).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/api/v1/articles/7", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Empty(t, w.Body.String())
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestGetByIDIgnoresIncludeDeleted(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{}, domain.ErrNotFound).Once()
 
 	r := setupRouter()
 	handler.NewArticleHandler(r, mockUCase)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/articles?num=1&cursor="+cursor, nil)
+	// soft-deleted articles are only visible through the admin endpoint
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/7?include_deleted=true", nil)
 	w := httptest.NewRecorder()
 
 	r.ServeHTTP(w, req)
 
-	responseCursor := w.Header().Get("X-Cursor")
-	assert.Equal(t, "10", responseCursor)
-	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusNotFound, w.Code)
 	mockUCase.AssertExpectations(t)
+	mockUCase.AssertNotCalled(t, "GetByIDIncludingDeleted", mock.Anything, mock.Anything)
 }
 
-func TestFetchError(t *testing.T) {
-	mockUCase := new(mocks.ArticleService)
-	num := 1
-	cursor := "2"
-	mockUCase.On("Fetch", mock.Anything, cursor, int64(num)).Return(nil, "", domain.ErrInternalServerError)
+func TestGetByIDFresh(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		header string
+		fresh  bool
+		code   int
+	}{
+		{name: "default", fresh: false, code: http.StatusOK},
+		{name: "fresh-query", query: "?fresh=true", fresh: true, code: http.StatusOK},
+		{name: "fresh-false", query: "?fresh=false", header: "no-cache", fresh: false, code: http.StatusOK},
+		{name: "no-cache-header", header: "max-age=0, No-Cache", fresh: true, code: http.StatusOK},
+		{name: "invalid", query: "?fresh=maybe", code: http.StatusBadRequest},
+	}
 
-	r := setupRouter()
-	handler.NewArticleHandler(r, mockUCase)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On("GetByID", mock.MatchedBy(func(ctx context.Context) bool {
+				return requestctx.BypassCacheFromContext(ctx) == tt.fresh
+			}), int64(7)).Return(domain.Article{ID: 7, Title: "Title", Content: "Content"}, nil).Maybe()
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/articles?num=1&cursor="+cursor, nil)
-	w := httptest.NewRecorder()
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase)
 
-	r.ServeHTTP(w, req)
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/7"+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set("Cache-Control", tt.header)
+			}
+			w := httptest.NewRecorder()
 
-	responseCursor := w.Header().Get("X-Cursor")
-	assert.Equal(t, "", responseCursor)
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	mockUCase.AssertExpectations(t)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+			if tt.code == http.StatusOK {
+				mockUCase.AssertExpectations(t)
+			} else {
+				mockUCase.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+			}
+		})
+	}
 }
 
-func TestGetByID(t *testing.T) {
-	var mockArticle domain.Article
-	err := faker.FakeData(&mockArticle)
-	assert.NoError(t, err)
+func TestGetBySlug(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetBySlug", mock.Anything, "hello-world").Return(domain.Article{ID: 1, Slug: "hello-world"}, nil).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/slug/hello-world", nil)
+		w := httptest.NewRecorder()
 
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var res domain.Article
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, "hello-world", res.Slug)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetBySlug", mock.Anything, "missing").Return(domain.Article{}, domain.ErrNotFound).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/slug/missing", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestGetNeighbors(t *testing.T) {
 	mockUCase := new(mocks.ArticleService)
-	num := int(mockArticle.ID)
-	mockUCase.On("GetByID", mock.Anything, int64(num)).Return(mockArticle, nil)
+	mockUCase.On("GetNeighbors", mock.Anything, int64(1)).Return(nil, &domain.Article{ID: 2, Title: "Second"}, nil).Once()
 
 	r := setupRouter()
 	handler.NewArticleHandler(r, mockUCase)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/"+strconv.Itoa(num), nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/1/neighbors", nil)
 	w := httptest.NewRecorder()
 
 	r.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, http.StatusOK, w.Code)
+	var res map[string]*handler.ArticleResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Contains(t, res, "prev")
+	assert.Nil(t, res["prev"])
+	require.NotNil(t, res["next"])
+	assert.Equal(t, int64(2), res["next"].ID)
 	mockUCase.AssertExpectations(t)
 }
 
+func TestClone(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Clone", mock.Anything, int64(7)).
+			Return(domain.Article{ID: 8, Title: "Hello (Copy)", Content: "Content", Status: domain.StatusDraft}, nil)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/articles/7/clone", nil))
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var res handler.ArticleResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, int64(8), res.ID)
+		assert.Equal(t, "Hello (Copy)", res.Title)
+		assert.Equal(t, domain.StatusDraft, res.Status)
+		assert.Equal(t, "/api/v1/articles/8", w.Header().Get("Location"))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("missing-source", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Clone", mock.Anything, int64(7)).Return(domain.Article{}, domain.ErrNotFound)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/articles/7/clone", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestView(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("IncrementViewCount", mock.Anything, int64(7)).Return(nil).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/articles/7/view", nil))
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Body.String())
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("IncrementViewCount", mock.Anything, int64(7)).Return(domain.ErrNotFound).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/articles/7/view", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("invalid-id", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/articles/invalid/view", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUCase.AssertNotCalled(t, "IncrementViewCount", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCustomPrefix(t *testing.T) {
+	for _, prefix := range []string{"/", "/service/api/v1"} {
+		t.Run(prefix, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1}, nil).Once()
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase, handler.WithPrefix(prefix))
+
+			req := httptest.NewRequest(http.MethodGet, strings.TrimSuffix(prefix, "/")+"/articles/1", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			mockUCase.AssertExpectations(t)
+
+			req = httptest.NewRequest(http.MethodGet, "/api/v1/articles/1", nil)
+			w = httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+		})
+	}
+}
+
 func TestGetByIDInvalidID(t *testing.T) {
 	mockUCase := new(mocks.ArticleService)
 
@@ -119,7 +1261,9 @@ func TestStore(t *testing.T) {
 	j, err := json.Marshal(tempMockArticle)
 	assert.NoError(t, err)
 
-	mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil)
+	mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Article).ID = 42
+	}).Return(nil)
 
 	r := setupRouter()
 	handler.NewArticleHandler(r, mockUCase)
@@ -131,9 +1275,170 @@ func TestStore(t *testing.T) {
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/api/v1/articles/42", w.Header().Get("Location"))
+	var created domain.Article
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "/api/v1/articles/"+strconv.FormatInt(created.ID, 10), w.Header().Get("Location"))
+	mockUCase.AssertExpectations(t)
+}
+
+func TestStoreMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata string
+		opts     []handler.Option
+		status   int
+	}{
+		{name: "object", metadata: `{"cover":"cover.png","seo":{"description":"d"}}`, status: http.StatusCreated},
+		{name: "absent", status: http.StatusCreated},
+		{name: "not-an-object", metadata: `["cover.png"]`, status: http.StatusBadRequest},
+		{name: "string", metadata: `"cover.png"`, status: http.StatusBadRequest},
+		{name: "too-large", metadata: `{"cover":"` + strings.Repeat("a", 4096) + `"}`, status: http.StatusBadRequest},
+		{name: "custom-limit", metadata: `{"cover":"cover.png"}`, opts: []handler.Option{handler.WithMaxMetadataBytes(10)}, status: http.StatusBadRequest},
+		{name: "json-schema", metadata: `{"cover":"cover.png"}`, opts: []handler.Option{handler.WithJSONSchema()}, status: http.StatusCreated},
+		{name: "json-schema-not-an-object", metadata: `"cover.png"`, opts: []handler.Option{handler.WithJSONSchema()}, status: http.StatusBadRequest},
+		{name: "json-schema-too-large", metadata: `{"cover":"cover.png"}`, opts: []handler.Option{handler.WithJSONSchema(), handler.WithMaxMetadataBytes(10)}, status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"title":"Title","content":"Content"}`
+			if tt.metadata != "" {
+				body = `{"title":"Title","content":"Content","metadata":` + tt.metadata + `}`
+			}
+			mockUCase := new(mocks.ArticleService)
+			if tt.status == http.StatusCreated {
+				mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Run(func(args mock.Arguments) {
+					args.Get(1).(*domain.Article).ID = 42
+				}).Return(nil)
+			}
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase, tt.opts...)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/articles", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+			if tt.status == http.StatusCreated {
+				var res map[string]any
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				if tt.metadata == "" {
+					assert.NotContains(t, res, "metadata")
+				} else {
+					metadata, err := json.Marshal(res["metadata"])
+					require.NoError(t, err)
+					assert.JSONEq(t, tt.metadata, string(metadata))
+				}
+			}
+			mockUCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetByIDMetadata(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{
+		ID: 1, Title: "Title", Content: "Content", Metadata: map[string]any{"cover": "cover.png"},
+	}, nil)
+
+	r := setupRouter()
+	handler.NewArticleHandler(r, mockUCase)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/1?fields=id,metadata", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":1,"metadata":{"cover":"cover.png"}}`, w.Body.String())
 	mockUCase.AssertExpectations(t)
 }
 
+func TestStoreLocationWithPrefix(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Article).ID = 7
+	}).Return(nil)
+
+	// a gateway stripping the prefix configures "/", which must not produce "//articles"
+	r := setupRouter()
+	handler.NewArticleHandler(r, mockUCase, handler.WithPrefix("/"))
+
+	req := httptest.NewRequest(http.MethodPost, "/articles", bytes.NewBufferString(`{"title":"Title","content":"Content"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/articles/7", w.Header().Get("Location"))
+}
+
+func TestUpsert(t *testing.T) {
+	tests := []struct {
+		name     string
+		created  bool
+		code     int
+		location string
+	}{
+		{name: "create", created: true, code: http.StatusCreated, location: "/api/v1/articles/12"},
+		{name: "replace", created: false, code: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On("Upsert", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool {
+				return ar.ID == 12 && ar.Title == "Title"
+			})).Return(tt.created, nil).Once()
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/articles/12", bytes.NewBufferString(`{"title":"Title","content":"Content"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.location, w.Header().Get("Location"))
+			var res domain.Article
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			assert.Equal(t, int64(12), res.ID)
+			mockUCase.AssertExpectations(t)
+		})
+	}
+
+	t.Run("id-mismatch", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/articles/12", bytes.NewBufferString(`{"id":13,"title":"Title","content":"Content"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUCase.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Upsert", mock.Anything, mock.Anything).Return(false, domain.ErrConflict).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/articles/12", bytes.NewBufferString(`{"title":"Title","content":"Content"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
 func TestStoreInvalidJSON(t *testing.T) {
 	mockUCase := new(mocks.ArticleService)
 
@@ -149,9 +1454,68 @@ func TestStoreInvalidJSON(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestStoreUnsupportedContentType(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+
+	r := setupRouter()
+	handler.NewArticleHandler(r, mockUCase)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/articles", bytes.NewBufferString("title=Title&content=Content"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	mockUCase.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+}
+
+func TestStoreValidateOnly(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		j, err := json.Marshal(domain.Article{Title: "Title", Content: "Content"})
+		assert.NoError(t, err)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/articles?validate_only=true", bytes.NewBuffer(j))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"valid":true}`, w.Body.String())
+		mockUCase.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		j, err := json.Marshal(domain.Article{Content: "Content"})
+		assert.NoError(t, err)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/articles?validate_only=true", bytes.NewBuffer(j))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var res handler.ValidationResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.False(t, res.Valid)
+		assert.Equal(t, []handler.FieldError{{Field: "title", Rule: "required"}}, res.Errors)
+		mockUCase.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
 func TestDelete(t *testing.T) {
 	var mockArticle domain.Article
-	err := faker.FakeData(&mockArticle)
+	err := faker.FakeData(&mockArticle, options.WithFieldsToIgnore("Metadata"))
 	assert.NoError(t, err)
 
 	mockUCase := new(mocks.ArticleService)
@@ -170,6 +1534,181 @@ func TestDelete(t *testing.T) {
 	mockUCase.AssertExpectations(t)
 }
 
+func TestDeleteWrappedErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "not-found", err: fmt.Errorf("delete article 3: %w", domain.ErrNotFound), code: http.StatusNotFound},
+		{name: "conflict", err: fmt.Errorf("delete article 3: %w", domain.ErrConflict), code: http.StatusConflict},
+		{name: "bad-param", err: fmt.Errorf("delete article 3: %w", domain.ErrBadParamInput), code: http.StatusBadRequest},
+		{name: "unknown", err: errors.New("connection reset"), code: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On("Delete", mock.Anything, int64(3)).Return(tt.err).Once()
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/articles/3", nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+			mockUCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteReturnRepresentation(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("GetByID", mock.Anything, int64(3)).Return(domain.Article{ID: 3, Title: "Deleted"}, nil).Once()
+	mockUCase.On("Delete", mock.Anything, int64(3)).Return(nil).Once()
+
+	r := setupRouter()
+	handler.NewArticleHandler(r, mockUCase)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/articles/3?return=representation", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var res handler.ArticleResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, int64(3), res.ID)
+	assert.Equal(t, "Deleted", res.Title)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestDeleteDefaultNoBody(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("Delete", mock.Anything, int64(3)).Return(nil).Once()
+
+	r := setupRouter()
+	handler.NewArticleHandler(r, mockUCase)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/articles/3", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+	// the article is only loaded when the representation is requested
+	mockUCase.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestCountByAuthor(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("CountByAuthor", mock.Anything, int64(7)).Return(int64(3), nil).Once()
+
+	r := setupRouter()
+	handler.NewArticleHandler(r, mockUCase)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/authors/7/articles/count", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"author_id":7,"count":3}`, w.Body.String())
+	mockUCase.AssertExpectations(t)
+}
+
+func TestListAuthors(t *testing.T) {
+	stats := []domain.AuthorStat{
+		{Author: domain.Author{ID: 1, Name: "Iman"}, ArticleCount: 3},
+		{Author: domain.Author{ID: 2, Name: "Without Articles"}, ArticleCount: 0},
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		code     int
+		expected string
+	}{
+		{
+			name:     "with-counts",
+			query:    "?with_counts=true",
+			code:     http.StatusOK,
+			expected: `[{"id":1,"name":"Iman","article_count":3},{"id":2,"name":"Without Articles","article_count":0}]`,
+		},
+		{
+			name:     "without-counts",
+			code:     http.StatusOK,
+			expected: `[{"id":1,"name":"Iman"},{"id":2,"name":"Without Articles"}]`,
+		},
+		{name: "invalid", query: "?with_counts=maybe", code: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On("ListAuthorsWithCounts", mock.Anything).Return(stats, nil).Maybe()
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/authors"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+			if tt.expected != "" {
+				assert.JSONEq(t, tt.expected, w.Body.String())
+			} else {
+				mockUCase.AssertNotCalled(t, "ListAuthorsWithCounts", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestDeleteIfUnmodifiedSince(t *testing.T) {
+	updated := time.Date(2024, 1, 2, 3, 4, 5, 500, time.UTC)
+
+	tests := []struct {
+		name   string
+		header string
+		code   int
+	}{
+		{name: "unchanged", header: updated.Format(http.TimeFormat), code: http.StatusNoContent},
+		{name: "later", header: updated.Add(time.Hour).Format(http.TimeFormat), code: http.StatusNoContent},
+		{name: "modified-since", header: updated.Add(-time.Second).Format(http.TimeFormat), code: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1, UpdatedAt: updated}, nil).Once()
+			if tt.code == http.StatusNoContent {
+				mockUCase.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+			}
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/articles/1", nil)
+			req.Header.Set("If-Unmodified-Since", tt.header)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+			mockUCase.AssertExpectations(t)
+			if tt.code == http.StatusPreconditionFailed {
+				mockUCase.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
 func TestDeleteInvalidID(t *testing.T) {
 	mockUCase := new(mocks.ArticleService)
 
@@ -183,3 +1722,176 @@ func TestDeleteInvalidID(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func TestStoreUnknownFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []handler.Option
+		body    string
+		details string
+	}{
+		{name: "typo", body: `{"titel":"Title","content":"Content"}`, details: `unknown field "titel"`},
+		{name: "extra", body: `{"title":"Title","content":"Content","subtitle":"Sub"}`, details: `unknown field "subtitle"`},
+		{name: "nested", body: `{"title":"Title","content":"Content","author":{"nmae":"x"}}`, details: `unknown field "author.nmae"`},
+		{
+			name:    "json-schema",
+			opts:    []handler.Option{handler.WithJSONSchema()},
+			body:    `{"title":"Title","content":"Content","titel":"Title"}`,
+			details: `unknown field "titel"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase, tt.opts...)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/articles", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			var res middleware.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			assert.Equal(t, tt.details, res.Details)
+			mockUCase.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("known-fields-any-case", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		body := `{"Title":"Title","content":"Content","author":{"id":1},"created_at":"2024-01-01T00:00:00Z"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/articles", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		stored := mockUCase.Calls[0].Arguments.Get(1).(*domain.Article)
+		assert.Equal(t, "Title", stored.Title)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestUpdateStatusUnknownFields(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+
+	r := setupRouter()
+	handler.NewArticleHandler(r, mockUCase)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/articles/1/status", strings.NewReader(`{"status":"published","reason":"x"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `unknown field \"reason\"`)
+	mockUCase.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStoreJSONSchema(t *testing.T) {
+	t.Run("conforming", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase, handler.WithJSONSchema())
+
+		body := `{"title":"Title","content":"Content","author":{"id":1}}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/articles", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		stored := mockUCase.Calls[0].Arguments.Get(1).(*domain.Article)
+		assert.Equal(t, "Title", stored.Title)
+		assert.Equal(t, int64(1), stored.Author.ID)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("non-conforming", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase, handler.WithJSONSchema())
+
+		body := `{"title":"","author":{"id":"one"}}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/articles?validate_only=true", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var res handler.ValidationResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.False(t, res.Valid)
+		assert.ElementsMatch(t, []handler.FieldError{
+			{Field: "/content", Rule: "required"},
+			{Field: "/title", Rule: "minLength"},
+			{Field: "/author/id", Rule: "type"},
+		}, res.Errors)
+		mockUCase.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("non-conforming-rejected", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase, handler.WithJSONSchema())
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/articles", strings.NewReader(`{"content":"Content"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUCase.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFeatureFlags(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusServiceUnavailable} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On("FetchRecent", mock.Anything, int64(5)).Return([]domain.Article{{ID: 1}}, nil).Once()
+
+			flags := middleware.NewFeatureFlags(map[string]bool{handler.FeatureRecent: true, handler.FeatureClone: false}, status)
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase, handler.WithFeatureFlags(flags))
+
+			// the enabled feature works
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/recent", nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			// the disabled one answers with the configured status without reaching the service
+			w = httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/articles/7/clone", nil))
+			assert.Equal(t, status, w.Code)
+			mockUCase.AssertNotCalled(t, "Clone", mock.Anything, mock.Anything)
+
+			// switching it on takes effect without registering the routes again
+			mockUCase.On("Clone", mock.Anything, int64(7)).Return(domain.Article{ID: 8}, nil).Once()
+			flags.Set(handler.FeatureClone, true)
+			w = httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/articles/7/clone", nil))
+			assert.Equal(t, http.StatusCreated, w.Code)
+			mockUCase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+)
+
+// UnknownFieldError is returned when a request body holds a field the target does not declare,
+// Field is the dotted path of the field as sent by the client
+type UnknownFieldError struct {
+	Field string
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("unknown field %q", e.Field)
+}
+
+// bindStrictJSON decodes the request body into obj like ShouldBindJSON, but rejects the fields obj does not
+// declare: a typo such as "titel" would otherwise be dropped silently. The json tags are checked instead of
+// using json.Decoder.DisallowUnknownFields, which custom UnmarshalJSON methods such as domain.Article's bypass.
+func bindStrictJSON(c *gin.Context, obj interface{}) error {
+	body, err := c.GetRawData()
+	if err != nil {
+		return err
+	}
+	if err := checkUnknownFields(body, reflect.TypeOf(obj), ""); err != nil {
+		return err
+	}
+	return json.Unmarshal(body, obj)
+}
+
+// bindError turns a binding error into a 400, an unknown field is named in Details so the client can spot it
+func bindError(err error) *middleware.AppError {
+	var unknown *UnknownFieldError
+	if errors.As(err, &unknown) {
+		return middleware.NewAppError(http.StatusBadRequest, "请求参数错误", unknown.Error())
+	}
+	return middleware.NewAppErrorWithErr(http.StatusBadRequest, "请求参数错误", err)
+}
+
+// checkUnknownFields compares the keys of the JSON object data with the json fields of t, recursing into
+// nested objects. Values that are not objects are left to json.Unmarshal, which reports type mismatches.
+func checkUnknownFields(data []byte, t reflect.Type, prefix string) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil
+	}
+
+	fields := map[string]reflect.Type{}
+	collectJSONFields(t, fields)
+	for key, value := range object {
+		// encoding/json matches keys case-insensitively
+		fieldType, ok := fields[strings.ToLower(key)]
+		if !ok {
+			return &UnknownFieldError{Field: prefix + key}
+		}
+		if err := checkUnknownFields(value, fieldType, prefix+key+"."); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectJSONFields maps the lower-cased json name of every field of t to its type, promoting the fields
+// of embedded structs the way encoding/json does
+func collectJSONFields(t reflect.Type, fields map[string]reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				collectJSONFields(embedded, fields)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = f.Type
+	}
+}
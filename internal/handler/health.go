@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/internal/pkg/health"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/logger"
+)
+
+// NewHealthHandler exposes GET /readyz, which runs the checks of checker and answers 503 when a required
+// one fails so the load balancer stops routing to this instance. The report is written as is, without the
+// envelope, because probes and operators read it in both the ready and the unready case.
+func NewHealthHandler(r *gin.Engine, checker *health.Checker, l *logger.Logger) {
+	r.GET("/readyz", func(c *gin.Context) {
+		report := checker.Run(c.Request.Context())
+		for name, err := range report.Errors {
+			l.Warnf("readiness check %s failed: %v", name, err)
+		}
+
+		status := http.StatusOK
+		if report.Status == health.StatusFail {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, report)
+	})
+}
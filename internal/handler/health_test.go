@@ -0,0 +1,84 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/health"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/logger"
+)
+
+func TestReadyz(t *testing.T) {
+	up := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name   string
+		checks []health.Check
+		code   int
+		status string
+		result map[string]string
+	}{
+		{
+			name:   "ready",
+			checks: []health.Check{{Name: "mysql", Func: up}, {Name: "redis", Func: up}},
+			code:   http.StatusOK,
+			status: "ok",
+			result: map[string]string{"mysql": "ok", "redis": "ok"},
+		},
+		{
+			name:   "required-down",
+			checks: []health.Check{{Name: "mysql", Func: up}, {Name: "redis", Func: down}},
+			code:   http.StatusServiceUnavailable,
+			status: "fail",
+			result: map[string]string{"mysql": "ok", "redis": "fail"},
+		},
+		{
+			name:   "optional-down",
+			checks: []health.Check{{Name: "mysql", Func: up}, {Name: "redis", Func: down, Optional: true}},
+			code:   http.StatusOK,
+			status: "degraded",
+			result: map[string]string{"mysql": "ok", "redis": "fail"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := health.New(time.Second)
+			for _, check := range tt.checks {
+				checker.Register(check)
+			}
+			var logged []string
+			l := logger.New(logger.DebugLevel, func(_ logger.Level, msg string) { logged = append(logged, msg) })
+
+			r := setupRouter()
+			handler.NewHealthHandler(r, checker, l)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			require.Equal(t, tt.code, w.Code)
+			var res struct {
+				Status string            `json:"status"`
+				Checks map[string]string `json:"checks"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			assert.Equal(t, tt.status, res.Status)
+			assert.Equal(t, tt.result, res.Checks)
+			// the cause of a failure is logged, never sent to the client
+			assert.NotContains(t, w.Body.String(), "connection refused")
+			if tt.status != "ok" {
+				assert.Equal(t, []string{"readiness check redis failed: connection refused"}, logged)
+			}
+		})
+	}
+}
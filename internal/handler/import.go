@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+)
+
+// importColumns are the CSV columns Import requires, in any order. An optional author_id column sets
+// the author of each row, rows leaving it empty are stored without an author.
+var importColumns = []string{"title", "content"}
+
+// ImportFailure describes a CSV row that was skipped
+type ImportFailure struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// ImportResult represent the response body of Import
+type ImportResult struct {
+	Imported int             `json:"imported"`
+	Failed   []ImportFailure `json:"failed"`
+}
+
+// Import will store the articles of an uploaded CSV file (multipart field "file") in one batch.
+// Rows that cannot be parsed or fail validation are reported and skipped, the rest are stored atomically.
+// The file is parsed while the request body is read rather than buffered first, the route's BodyLimit
+// rejects uploads over importMaxBytes.
+func (a *ArticleHandler) Import(c *gin.Context) {
+	f, err := importFile(c.Request)
+	if err != nil {
+		if middleware.IsBodyTooLarge(err) {
+			middleware.HandleError(c, middleware.ErrRequestEntityTooLarge)
+			return
+		}
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(http.StatusBadRequest, "缺少上传文件 file", err))
+		return
+	}
+	defer f.Close()
+
+	articles, failed, err := a.parseImportCSV(f)
+	if err != nil {
+		if middleware.IsBodyTooLarge(err) {
+			middleware.HandleError(c, middleware.ErrRequestEntityTooLarge)
+			return
+		}
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(http.StatusBadRequest, "CSV 格式错误", err))
+		return
+	}
+
+	if len(articles) > 0 {
+		if err := a.Service.StoreBatch(c.Request.Context(), articles); err != nil {
+			middleware.HandleError(c, middleware.NewAppErrorWithErr(a.getStatusCode(err), "导入文章失败", err))
+			return
+		}
+	}
+
+	respondJSON(c, http.StatusOK, ImportResult{Imported: len(articles), Failed: failed})
+}
+
+// importFile returns the "file" part of a multipart request, positioned on the request body so it is read
+// as it arrives. Other parts before it are skipped.
+func importFile(r *http.Request) (*multipart.Part, error) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("no file part")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == "file" && part.FileName() != "" {
+			return part, nil
+		}
+		part.Close()
+	}
+}
+
+// parseImportCSV reads the header and turns every valid row into an article
+func (a *ArticleHandler) parseImportCSV(r io.Reader) ([]*domain.Article, []ImportFailure, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("read header: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range importColumns {
+		if _, ok := index[name]; !ok {
+			return nil, nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	articles := []*domain.Article{}
+	failed := []ImportFailure{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, err
+			}
+			failed = append(failed, ImportFailure{Line: parseErr.StartLine, Error: err.Error()})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) != len(header) {
+			failed = append(failed, ImportFailure{Line: line, Error: fmt.Sprintf("expected %d fields, got %d", len(header), len(record))})
+			continue
+		}
+
+		ar := &domain.Article{
+			Title:   record[index["title"]],
+			Content: record[index["content"]],
+		}
+		if i, ok := index["author_id"]; ok {
+			if ar.Author.ID, err = parseImportAuthorID(record[i]); err != nil {
+				failed = append(failed, ImportFailure{Line: line, Error: err.Error()})
+				continue
+			}
+		}
+		if ok, err := a.isRequestValid(ar); !ok {
+			failed = append(failed, ImportFailure{Line: line, Error: err.Error()})
+			continue
+		}
+		articles = append(articles, ar)
+	}
+	return articles, failed, nil
+}
+
+// parseImportAuthorID reads the author_id field of a row, an empty field means no author
+func parseImportAuthorID(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid author_id %q", value)
+	}
+	return id, nil
+}
@@ -0,0 +1,213 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/article"
+	articleMocks "github.com/bxcodec/go-clean-arch/article/mocks"
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/handler"
+	"github.com/bxcodec/go-clean-arch/internal/handler/mocks"
+)
+
+func newImportRequest(t *testing.T, csv string) *http.Request {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", "articles.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(csv))
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/articles/import", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestImport(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("StoreBatch", mock.Anything, mock.MatchedBy(func(list []*domain.Article) bool {
+			return len(list) == 2 && list[0].Title == "First" && list[1].Content == "Second, with a comma"
+		})).Return(nil).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, newImportRequest(t, "title,content\nFirst,Body one\nSecond,\"Second, with a comma\"\n"))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var res handler.ImportResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, 2, res.Imported)
+		assert.Empty(t, res.Failed)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("author-id", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("StoreBatch", mock.Anything, mock.MatchedBy(func(list []*domain.Article) bool {
+			return len(list) == 2 && list[0].Author.ID == 7 && list[1].Author.ID == 0
+		})).Return(nil).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		csv := "title,content,author_id\nFirst,Body,7\nSecond,Body,\nThird,Body,seven\nFourth,Body,-1\n"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, newImportRequest(t, csv))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var res handler.ImportResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, 2, res.Imported)
+		require.Len(t, res.Failed, 2)
+		assert.Equal(t, 4, res.Failed[0].Line)
+		assert.Equal(t, `invalid author_id "seven"`, res.Failed[0].Error)
+		assert.Equal(t, 5, res.Failed[1].Line)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("malformed-row", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("StoreBatch", mock.Anything, mock.MatchedBy(func(list []*domain.Article) bool {
+			return len(list) == 1 && list[0].Title == "Good"
+		})).Return(nil).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		csv := "title,content\nGood,Body\nonly-one-field\n,Missing title\n"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, newImportRequest(t, csv))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var res handler.ImportResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, 1, res.Imported)
+		require.Len(t, res.Failed, 2)
+		assert.Equal(t, 3, res.Failed[0].Line)
+		assert.Equal(t, 4, res.Failed[1].Line)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("missing-column", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, newImportRequest(t, "title\nFirst\n"))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUCase.AssertNotCalled(t, "StoreBatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("streaming", func(t *testing.T) {
+		// a chunked upload has no Content-Length, it is parsed while it is read
+		rows := strings.Repeat("Title,Body\n", 100)
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("StoreBatch", mock.Anything, mock.MatchedBy(func(list []*domain.Article) bool {
+			return len(list) == 100
+		})).Return(nil).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase, handler.WithImportMaxBytes(4096))
+
+		req := newImportRequest(t, "title,content\n"+rows)
+		req.ContentLength = -1
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var res handler.ImportResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, 100, res.Imported)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("too-large", func(t *testing.T) {
+		rows := strings.Repeat("Title,Body\n", 1000)
+		for _, chunked := range []bool{false, true} {
+			mockUCase := new(mocks.ArticleService)
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase, handler.WithImportMaxBytes(4096))
+
+			req := newImportRequest(t, "title,content\n"+rows)
+			if chunked {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code, "chunked: %v", chunked)
+			mockUCase.AssertNotCalled(t, "StoreBatch", mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("StoreBatch", mock.Anything, mock.Anything).Return(domain.ErrConflict).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, newImportRequest(t, "title,content\nFirst,Body\n"))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestImportThenList(t *testing.T) {
+	// the real service between the handler and the repositories, so the listed articles go through the
+	// author lookup the imported rows without an author used to fail
+	articleRepo := new(articleMocks.ArticleRepository)
+	authorRepo := new(articleMocks.AuthorRepository)
+	articleRepo.On("GetByTitle", mock.Anything, mock.Anything).Return(domain.Article{}, domain.ErrNotFound)
+	articleRepo.On("GetBySlug", mock.Anything, mock.Anything).Return(domain.Article{}, domain.ErrNotFound)
+	var stored []domain.Article
+	articleRepo.On("StoreBatch", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		for i, ar := range args.Get(1).([]*domain.Article) {
+			ar.ID = int64(i + 1)
+			stored = append(stored, *ar)
+		}
+	}).Return(nil).Once()
+	authorRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Author{ID: 7, Name: "Iman Tumorang"}, nil)
+
+	r := setupRouter()
+	handler.NewArticleHandler(r, article.NewService(articleRepo, authorRepo))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newImportRequest(t, "title,content,author_id\nFirst,Body one,7\nSecond,Body two,\n"))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, stored, 2)
+
+	articleRepo.On("Fetch", mock.Anything, mock.Anything, domain.PageNext, mock.Anything, mock.Anything).
+		Return(stored, domain.PageCursors{}, nil).Once()
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var res []handler.ArticleResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	require.Len(t, res, 2)
+	assert.Equal(t, "Iman Tumorang", res[0].Author.Name)
+	assert.Equal(t, domain.Author{}, res[1].Author)
+	authorRepo.AssertNotCalled(t, "GetByID", mock.Anything, int64(0))
+	articleRepo.AssertExpectations(t)
+}
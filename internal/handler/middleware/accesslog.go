@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/internal/pkg/logger"
+)
+
+// AccessLogConfig 访问日志配置
+type AccessLogConfig struct {
+	// LogRequestBody 为 true 时，非 2xx 响应的访问日志附带请求体，便于排查错误请求
+	LogRequestBody bool
+	// MaxBodySize 记录的请求体最大字节数，超出部分截断
+	MaxBodySize int
+	// RedactFields 需要脱敏的 JSON 字段名（不区分大小写）
+	RedactFields []string
+	// SampleRate 2xx 响应被记录的比例，取值 0 到 1；非 2xx 响应始终记录
+	SampleRate float64
+}
+
+// DefaultAccessLogConfig 默认访问日志配置，不记录请求体，记录全部请求
+var DefaultAccessLogConfig = AccessLogConfig{
+	MaxBodySize:  1024,
+	RedactFields: []string{"password", "token", "secret"},
+	SampleRate:   1,
+}
+
+// AccessLog 访问日志中间件，替代 gin.Logger() 使所有日志通过同一个 logger 输出
+func AccessLog(l *logger.Logger) gin.HandlerFunc {
+	return AccessLogWithConfig(l, DefaultAccessLogConfig)
+}
+
+// AccessLogWithConfig 按配置输出访问日志。记录请求体时只预读 MaxBodySize 字节，
+// 并将其拼回请求体，handler 仍可完整读取
+func AccessLogWithConfig(l *logger.Logger, cfg AccessLogConfig) gin.HandlerFunc {
+	redact := redactPattern(cfg.RedactFields)
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+		if raw := c.Request.URL.RawQuery; raw != "" {
+			path = path + "?" + raw
+		}
+
+		var body []byte
+		truncated := false
+		if cfg.LogRequestBody && c.Request.Body != nil {
+			body, truncated = peekBody(c, cfg.MaxBodySize)
+		}
+
+		c.Next()
+
+		status := c.Writer.Status()
+		// 只对 2xx 采样，错误请求始终记录以便排查
+		if status >= 200 && status < 300 && !sampled(cfg.SampleRate) {
+			return
+		}
+		if cfg.LogRequestBody && (status < 200 || status >= 300) {
+			logged := string(body)
+			if redact != nil {
+				logged = redact.ReplaceAllString(logged, `"$1":"***"`)
+			}
+			if truncated {
+				logged += "...(truncated)"
+			}
+			l.Infof("Access - Method: %s, URI: %s, Status: %d, Latency: %s, IP: %s, Body: %s",
+				c.Request.Method, path, status, time.Since(start), c.ClientIP(), logged)
+			return
+		}
+		l.Infof("Access - Method: %s, URI: %s, Status: %d, Latency: %s, IP: %s",
+			c.Request.Method, path, status, time.Since(start), c.ClientIP())
+	}
+}
+
+// sampled 以 rate 的概率返回 true，rate 为 0 时从不记录，为 1 时总是记录
+func sampled(rate float64) bool {
+	return rate >= 1 || rand.Float64() < rate
+}
+
+// peekBody 读取请求体的前 max 字节，并把读出的部分拼回请求体
+func peekBody(c *gin.Context, max int) ([]byte, bool) {
+	original := c.Request.Body
+	prefix, err := io.ReadAll(io.LimitReader(original, int64(max)+1))
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(prefix), original), original}
+	if err != nil {
+		return nil, false
+	}
+	if len(prefix) > max {
+		return prefix[:max], true
+	}
+	return prefix, false
+}
+
+// redactPattern 匹配 "字段": 值，值为字符串、数字、布尔或 null
+func redactPattern(fields []string) *regexp.Regexp {
+	if len(fields) == 0 {
+		return nil
+	}
+	quoted := make([]string, 0, len(fields))
+	for _, f := range fields {
+		quoted = append(quoted, regexp.QuoteMeta(f))
+	}
+	return regexp.MustCompile(`(?i)"(` + strings.Join(quoted, "|") + `)"\s*:\s*("(?:[^"\\]|\\.)*"?|[^,}\]\s]+)`)
+}
@@ -0,0 +1,131 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/logger"
+)
+
+func TestAccessLogRequestBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	l, entries := newCaptureLogger(logger.InfoLevel)
+
+	cfg := middleware.DefaultAccessLogConfig
+	cfg.LogRequestBody = true
+	cfg.MaxBodySize = 64
+	r := gin.New()
+	r.Use(middleware.AccessLogWithConfig(l, cfg))
+	var received string
+	r.POST("/articles", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		require.NoError(t, err)
+		received = string(body)
+		if strings.Contains(received, "bad") {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	t.Run("logged-on-400", func(t *testing.T) {
+		*entries = nil
+		body := `{"title":"bad","password":"hunter2","token": "abc"}`
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(body)))
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, body, received, "the handler still reads the whole body")
+		require.Len(t, *entries, 1)
+		assert.Contains(t, (*entries)[0].msg, `Body: {"title":"bad","password":"***","token":"***"}`)
+		assert.NotContains(t, (*entries)[0].msg, "hunter2")
+	})
+
+	t.Run("not-logged-on-200", func(t *testing.T) {
+		*entries = nil
+		body := `{"title":"good"}`
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(body)))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, body, received)
+		require.Len(t, *entries, 1)
+		assert.NotContains(t, (*entries)[0].msg, "Body:")
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		*entries = nil
+		body := `{"title":"bad","content":"` + strings.Repeat("x", 200) + `"}`
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(body)))
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, body, received)
+		require.Len(t, *entries, 1)
+		assert.Contains(t, (*entries)[0].msg, body[:64]+"...(truncated)")
+	})
+}
+
+func TestAccessLogRequestBodyDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	l, entries := newCaptureLogger(logger.InfoLevel)
+
+	r := gin.New()
+	r.Use(middleware.AccessLog(l))
+	r.POST("/articles", func(c *gin.Context) {
+		c.Status(http.StatusBadRequest)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(`{"title":"bad"}`)))
+
+	require.Len(t, *entries, 1)
+	assert.NotContains(t, (*entries)[0].msg, "Body:")
+}
+
+func TestAccessLogSampling(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(rate float64) (*gin.Engine, *[]capturedEntry) {
+		l, entries := newCaptureLogger(logger.InfoLevel)
+		cfg := middleware.DefaultAccessLogConfig
+		cfg.SampleRate = rate
+		r := gin.New()
+		r.Use(middleware.AccessLogWithConfig(l, cfg))
+		r.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+		r.GET("/bad", func(c *gin.Context) { c.Status(http.StatusBadRequest) })
+		r.GET("/fail", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+		return r, entries
+	}
+	serve := func(r *gin.Engine, path string, times int) {
+		for i := 0; i < times; i++ {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}
+	}
+
+	t.Run("rate-zero-keeps-errors", func(t *testing.T) {
+		r, entries := newRouter(0)
+		serve(r, "/ok", 20)
+		assert.Empty(t, *entries)
+
+		serve(r, "/bad", 1)
+		serve(r, "/fail", 1)
+		require.Len(t, *entries, 2)
+		assert.Contains(t, (*entries)[0].msg, "Status: 400")
+		assert.Contains(t, (*entries)[1].msg, "Status: 500")
+	})
+
+	t.Run("rate-one-logs-everything", func(t *testing.T) {
+		r, entries := newRouter(1)
+		serve(r, "/ok", 20)
+		assert.Len(t, *entries, 20)
+	})
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// APIVersionHeader 客户端请求的 API 版本，响应中回写实际使用的版本
+	APIVersionHeader = "X-API-Version"
+	apiVersionKey    = "middleware.api_version"
+)
+
+// APIVersionConfig API 版本配置
+type APIVersionConfig struct {
+	// Supported 支持的版本，按从旧到新排列
+	Supported []string
+	// Default 未携带 X-API-Version 时使用的版本，为空时使用 Supported 中最新的版本
+	Default string
+}
+
+// RequireAPIVersion 校验 X-API-Version 是否在 supported 中（按从旧到新排列），未携带时使用最新版本
+func RequireAPIVersion(supported ...string) gin.HandlerFunc {
+	return RequireAPIVersionWithConfig(APIVersionConfig{Supported: supported})
+}
+
+// RequireAPIVersionWithConfig 校验 X-API-Version，不支持的版本返回 400 并在 details 中列出支持的版本
+func RequireAPIVersionWithConfig(cfg APIVersionConfig) gin.HandlerFunc {
+	defaultVersion := cfg.Default
+	if defaultVersion == "" && len(cfg.Supported) > 0 {
+		defaultVersion = cfg.Supported[len(cfg.Supported)-1]
+	}
+	accepted := "支持的版本: " + strings.Join(cfg.Supported, ", ")
+
+	return func(c *gin.Context) {
+		version := strings.TrimSpace(c.GetHeader(APIVersionHeader))
+		if version == "" {
+			version = defaultVersion
+		} else if !slices.Contains(cfg.Supported, version) {
+			HandleError(c, NewAppError(http.StatusBadRequest, "不支持的 API 版本", accepted))
+			c.Abort()
+			return
+		}
+		c.Set(apiVersionKey, version)
+		c.Header(APIVersionHeader, version)
+		c.Next()
+	}
+}
+
+// GetAPIVersion 返回当前请求使用的 API 版本，未注册 RequireAPIVersion 中间件时返回空字符串
+func GetAPIVersion(c *gin.Context) string {
+	return c.GetString(apiVersionKey)
+}
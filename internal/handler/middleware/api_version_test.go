@@ -0,0 +1,69 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+)
+
+func TestRequireAPIVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(versioning gin.HandlerFunc) *gin.Engine {
+		r := gin.New()
+		r.Use(middleware.ErrorMiddleware())
+		r.Use(versioning)
+		r.GET("/articles", func(c *gin.Context) {
+			c.String(http.StatusOK, middleware.GetAPIVersion(c))
+		})
+		return r
+	}
+
+	tests := []struct {
+		name       string
+		versioning gin.HandlerFunc
+		header     string
+		status     int
+		version    string
+	}{
+		{name: "supported", versioning: middleware.RequireAPIVersion("1", "2"), header: "1", status: http.StatusOK, version: "1"},
+		{name: "absent-uses-latest", versioning: middleware.RequireAPIVersion("1", "2"), status: http.StatusOK, version: "2"},
+		{
+			name:       "absent-uses-configured-default",
+			versioning: middleware.RequireAPIVersionWithConfig(middleware.APIVersionConfig{Supported: []string{"1", "2"}, Default: "1"}),
+			status:     http.StatusOK,
+			version:    "1",
+		},
+		{name: "unsupported", versioning: middleware.RequireAPIVersion("1", "2"), header: "3", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/articles", nil)
+			if tt.header != "" {
+				req.Header.Set(middleware.APIVersionHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			newRouter(tt.versioning).ServeHTTP(w, req)
+
+			require.Equal(t, tt.status, w.Code)
+			if tt.status != http.StatusOK {
+				var res middleware.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				assert.Equal(t, "支持的版本: 1, 2", res.Details)
+				assert.Empty(t, w.Header().Get(middleware.APIVersionHeader))
+				return
+			}
+			assert.Equal(t, tt.version, w.Body.String())
+			assert.Equal(t, tt.version, w.Header().Get(middleware.APIVersionHeader))
+		})
+	}
+}
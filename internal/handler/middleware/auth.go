@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/bxcodec/go-clean-arch/internal/pkg/requestctx"
+)
+
+// BearerAuth 要求请求携带 "Authorization: Bearer <token>"，令牌不匹配时返回 401
+func BearerAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		got, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.Header("WWW-Authenticate", "Bearer")
+			HandleError(c, ErrUnauthorized)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// JWTActor 校验 "Authorization: Bearer <jwt>"（HS256），并将 sub 声明作为操作者写入请求 context，
+// 供审计日志等下层使用（见 requestctx.ActorFromContext）。
+// 文章接口不要求登录，因此缺少令牌或令牌无效时不拒绝请求，只是按匿名处理；/admin 的 Bearer 令牌同理不受影响
+func JWTActor(secret []byte) gin.HandlerFunc {
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	keyFunc := func(*jwt.Token) (interface{}, error) { return secret, nil }
+
+	return func(c *gin.Context) {
+		raw, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if ok && len(secret) > 0 {
+			var claims jwt.RegisteredClaims
+			if _, err := parser.ParseWithClaims(raw, &claims, keyFunc); err == nil && claims.Subject != "" {
+				c.Request = c.Request.WithContext(requestctx.WithActor(c.Request.Context(), claims.Subject))
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,109 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/requestctx"
+)
+
+func TestBearerAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		code   int
+	}{
+		{name: "valid", token: "s3cret", header: "Bearer s3cret", code: http.StatusOK},
+		{name: "wrong-token", token: "s3cret", header: "Bearer other", code: http.StatusUnauthorized},
+		{name: "missing-header", token: "s3cret", header: "", code: http.StatusUnauthorized},
+		{name: "wrong-scheme", token: "s3cret", header: "Basic s3cret", code: http.StatusUnauthorized},
+		{name: "empty-token-rejects-all", token: "", header: "Bearer ", code: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(middleware.ErrorMiddleware())
+			r.GET("/admin", middleware.BearerAuth(tt.token), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+			if tt.code == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestJWTActor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := []byte("s3cret")
+
+	sign := func(method jwt.SigningMethod, key interface{}, claims jwt.Claims) string {
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		require.NoError(t, err)
+		return "Bearer " + token
+	}
+	valid := jwt.RegisteredClaims{Subject: "alice", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))}
+
+	tests := []struct {
+		name   string
+		header string
+		actor  string
+	}{
+		{name: "valid", header: sign(jwt.SigningMethodHS256, secret, valid), actor: "alice"},
+		{name: "missing-header", header: ""},
+		{name: "not-a-jwt", header: "Bearer admin-token"},
+		{name: "wrong-secret", header: sign(jwt.SigningMethodHS256, []byte("other"), valid)},
+		{name: "wrong-method", header: sign(jwt.SigningMethodHS512, secret, valid)},
+		{name: "none-method", header: sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, valid)},
+		{
+			name:   "expired",
+			header: sign(jwt.SigningMethodHS256, secret, jwt.RegisteredClaims{Subject: "alice", ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))}),
+		},
+		{name: "no-subject", header: sign(jwt.SigningMethodHS256, secret, jwt.RegisteredClaims{})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			var actor string
+			r.GET("/articles", middleware.JWTActor(secret), func(c *gin.Context) {
+				actor = requestctx.ActorFromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/articles", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			// an invalid token never rejects the request, the caller is anonymous
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.actor, actor)
+		})
+	}
+}
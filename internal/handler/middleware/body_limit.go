@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimit 限制请求体大小，limit <= 0 时不限制。
+// Content-Length 超过 limit 的请求直接返回 413，不读取请求体；未声明长度（分块传输）的请求体读取超过 limit 时报错，
+// 处理函数通过 IsBodyTooLarge 判断后返回 ErrRequestEntityTooLarge
+func BodyLimit(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > limit {
+			HandleError(c, ErrRequestEntityTooLarge)
+			c.Abort()
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+// IsBodyTooLarge 判断读取请求体的错误是否由 BodyLimit 的大小限制导致
+func IsBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
@@ -0,0 +1,64 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+)
+
+func TestBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		limit    int64
+		body     string
+		chunked  bool
+		expected int
+	}{
+		{name: "within-limit", limit: 8, body: "12345678", expected: http.StatusOK},
+		{name: "content-length-over-limit", limit: 8, body: "123456789", expected: http.StatusRequestEntityTooLarge},
+		{name: "chunked-within-limit", limit: 8, body: "1234", chunked: true, expected: http.StatusOK},
+		{name: "chunked-over-limit", limit: 8, body: "123456789", chunked: true, expected: http.StatusRequestEntityTooLarge},
+		{name: "unlimited", limit: 0, body: "123456789", expected: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			read := false
+			r := gin.New()
+			r.Use(middleware.ErrorMiddleware())
+			r.POST("/test", middleware.BodyLimit(tt.limit), func(c *gin.Context) {
+				read = true
+				if _, err := io.ReadAll(c.Request.Body); err != nil {
+					if middleware.IsBodyTooLarge(err) {
+						middleware.HandleError(c, middleware.ErrRequestEntityTooLarge)
+						return
+					}
+					middleware.HandleError(c, err)
+					return
+				}
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+			// a declared Content-Length over the limit is rejected before the handler reads the body
+			assert.Equal(t, tt.name != "content-length-over-limit", read)
+		})
+	}
+}
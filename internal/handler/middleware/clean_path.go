@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CleanPathConfig 路径规范化配置
+type CleanPathConfig struct {
+	// KeepTrailingSlash 为 true 时保留末尾的 /，此时仍由 gin 将其重定向到不带 / 的路由
+	KeepTrailingSlash bool
+	// Redirect 为 true 时重定向到规范路径，否则在内部按规范路径重新路由
+	Redirect bool
+}
+
+// CleanPath 规范化未匹配任何路由的请求路径：合并重复的 /、解析 . 与 ..、去掉末尾的 /，
+// 并在内部按规范路径重新路由，例如 /api//v1/articles 与 /api/v1/articles/ 均由 /api/v1/articles 处理
+func CleanPath(r *gin.Engine) gin.HandlerFunc {
+	return CleanPathWithConfig(r, CleanPathConfig{})
+}
+
+// CleanPathWithConfig 需在其他中间件之前注册，否则重新路由时这些中间件会执行两次。
+// gin 只对未匹配的路由执行全局中间件与 NoRoute，因此已匹配的请求不受影响；
+// 去掉末尾 / 时会关闭 r.RedirectTrailingSlash，改由本中间件处理。
+// 重定向时 GET、HEAD 返回 301，其他方法返回 308 以保留方法与请求体
+func CleanPathWithConfig(r *gin.Engine, cfg CleanPathConfig) gin.HandlerFunc {
+	if !cfg.KeepTrailingSlash {
+		r.RedirectTrailingSlash = false
+	}
+
+	return func(c *gin.Context) {
+		if c.FullPath() != "" {
+			c.Next()
+			return
+		}
+		original := c.Request.URL.Path
+		cleaned := cleanPath(original, cfg.KeepTrailingSlash)
+		if cleaned == original {
+			c.Next()
+			return
+		}
+
+		if cfg.Redirect {
+			location := cleaned
+			if c.Request.URL.RawQuery != "" {
+				location += "?" + c.Request.URL.RawQuery
+			}
+			status := http.StatusPermanentRedirect
+			if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+				status = http.StatusMovedPermanently
+			}
+			c.Redirect(status, location)
+			c.Abort()
+			return
+		}
+
+		c.Request.URL.Path = cleaned
+		c.Request.URL.RawPath = ""
+		r.HandleContext(c)
+		// HandleContext 恢复了本中间件的下标，不终止的话会继续执行新路由处理链中的后续函数
+		c.Abort()
+	}
+}
+
+// cleanPath 返回 p 的规范形式，p 本身规范时原样返回
+func cleanPath(p string, keepTrailingSlash bool) string {
+	cleaned := path.Clean("/" + p)
+	if keepTrailingSlash && strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
@@ -0,0 +1,103 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+)
+
+func setupCleanPathRouter(cfg middleware.CleanPathConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(middleware.CleanPathWithConfig(r, cfg))
+	r.Use(middleware.ErrorMiddleware())
+	r.NoRoute(middleware.NotFound())
+	r.GET("/api/v1/articles", func(c *gin.Context) {
+		c.String(http.StatusOK, "list "+c.Query("num"))
+	})
+	r.POST("/api/v1/articles", func(c *gin.Context) {
+		c.String(http.StatusCreated, "created")
+	})
+	r.GET("/api/v1/articles/:id", func(c *gin.Context) {
+		c.String(http.StatusOK, "article "+c.Param("id"))
+	})
+	return r
+}
+
+func TestCleanPathRewrite(t *testing.T) {
+	r := setupCleanPathRouter(middleware.CleanPathConfig{})
+
+	tests := []struct {
+		method string
+		path   string
+		code   int
+		body   string
+	}{
+		{method: http.MethodGet, path: "/api/v1/articles", code: http.StatusOK, body: "list "},
+		{method: http.MethodGet, path: "/api//v1/articles", code: http.StatusOK, body: "list "},
+		{method: http.MethodGet, path: "/api/v1/articles/", code: http.StatusOK, body: "list "},
+		{method: http.MethodGet, path: "/api///v1//articles//?num=5", code: http.StatusOK, body: "list 5"},
+		{method: http.MethodGet, path: "/api/v1/./articles/7", code: http.StatusOK, body: "article 7"},
+		{method: http.MethodPost, path: "/api//v1/articles/", code: http.StatusCreated, body: "created"},
+		{method: http.MethodGet, path: "/api//v1/missing", code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Empty(t, w.Header().Get("Location"))
+			if tt.body != "" {
+				assert.Equal(t, tt.body, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCleanPathRedirect(t *testing.T) {
+	r := setupCleanPathRouter(middleware.CleanPathConfig{Redirect: true})
+
+	tests := []struct {
+		method   string
+		path     string
+		code     int
+		location string
+	}{
+		{method: http.MethodGet, path: "/api//v1/articles", code: http.StatusMovedPermanently, location: "/api/v1/articles"},
+		{method: http.MethodGet, path: "/api/v1/articles/?num=5", code: http.StatusMovedPermanently, location: "/api/v1/articles?num=5"},
+		{method: http.MethodPost, path: "/api//v1/articles", code: http.StatusPermanentRedirect, location: "/api/v1/articles"},
+		{method: http.MethodGet, path: "/api/v1/articles", code: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.location, w.Header().Get("Location"))
+		})
+	}
+}
+
+func TestCleanPathKeepTrailingSlash(t *testing.T) {
+	r := setupCleanPathRouter(middleware.CleanPathConfig{KeepTrailingSlash: true})
+
+	// duplicate slashes are still collapsed, the trailing slash is left to gin's redirect
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api//v1/articles", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/", nil))
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/api/v1/articles", w.Header().Get("Location"))
+}
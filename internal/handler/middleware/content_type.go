@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireJSON 要求写请求（POST/PUT/PATCH）的 Content-Type 为 application/json，否则返回 415
+func RequireJSON() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			c.Next()
+			return
+		}
+
+		mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		if err != nil || mediaType != gin.MIMEJSON {
+			HandleError(c, ErrUnsupportedMediaType)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
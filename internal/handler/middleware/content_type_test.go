@@ -0,0 +1,52 @@
+package middleware_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+)
+
+func TestRequireJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		expected    int
+	}{
+		{name: "json", method: http.MethodPost, contentType: "application/json", expected: http.StatusOK},
+		{name: "json-with-charset", method: http.MethodPut, contentType: "application/json; charset=utf-8", expected: http.StatusOK},
+		{name: "form", method: http.MethodPost, contentType: "application/x-www-form-urlencoded", expected: http.StatusUnsupportedMediaType},
+		{name: "text", method: http.MethodPatch, contentType: "text/plain", expected: http.StatusUnsupportedMediaType},
+		{name: "missing", method: http.MethodPost, contentType: "", expected: http.StatusUnsupportedMediaType},
+		{name: "read-is-not-checked", method: http.MethodGet, contentType: "text/plain", expected: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(middleware.ErrorMiddleware())
+			r.Use(middleware.RequireJSON())
+			r.Handle(tt.method, "/test", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, "/test", bytes.NewBufferString(`{}`))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
@@ -1,16 +1,63 @@
 package middleware
 
-import "github.com/gin-gonic/gin"
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig 跨域配置
+type CORSConfig struct {
+	AllowOrigin  string
+	AllowMethods []string
+	AllowHeaders []string
+	// ExposeHeaders 允许浏览器脚本读取的响应头，为空时不发送 Access-Control-Expose-Headers
+	ExposeHeaders []string
+	// MaxAge 预检请求结果的缓存时间，为 0 时不发送 Access-Control-Max-Age
+	MaxAge time.Duration
+}
+
+// DefaultCORSConfig 默认跨域配置
+var DefaultCORSConfig = CORSConfig{
+	AllowOrigin:  "*",
+	AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+	AllowHeaders: []string{"Content-Type", "Authorization", "If-Modified-Since", "If-None-Match", "If-Unmodified-Since", "Cache-Control"},
+	ExposeHeaders: []string{
+		"X-Cursor", "X-Prev-Cursor", "X-Total-Count", "X-Request-ID", "Link", "ETag", "Last-Modified", "Location",
+	},
+}
 
 // CORS will handle the CORS middleware
 func CORS() gin.HandlerFunc {
+	return CORSWithConfig(DefaultCORSConfig)
+}
+
+// CORSWithConfig will handle the CORS middleware with the given config
+func CORSWithConfig(cfg CORSConfig) gin.HandlerFunc {
+	allowMethods := strings.Join(cfg.AllowMethods, ", ")
+	allowHeaders := strings.Join(cfg.AllowHeaders, ", ")
+	exposeHeaders := strings.Join(cfg.ExposeHeaders, ", ")
+	maxAge := ""
+	if cfg.MaxAge > 0 {
+		maxAge = strconv.Itoa(int(cfg.MaxAge.Seconds()))
+	}
+
 	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Origin", cfg.AllowOrigin)
+		c.Header("Access-Control-Allow-Methods", allowMethods)
+		c.Header("Access-Control-Allow-Headers", allowHeaders)
+		if exposeHeaders != "" {
+			c.Header("Access-Control-Expose-Headers", exposeHeaders)
+		}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		if c.Request.Method == http.MethodOptions {
+			if maxAge != "" {
+				c.Header("Access-Control-Max-Age", maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
 
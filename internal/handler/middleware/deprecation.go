@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeprecatedParam 已弃用的查询参数
+type DeprecatedParam struct {
+	// Name 查询参数名
+	Name string
+	// Replacement 替代的参数，写入 Warning 的提示文本，为空时不提示
+	Replacement string
+	// Sunset 停止支持的时间，零值时不发送 Sunset 头
+	Sunset time.Time
+}
+
+// DeprecatedParams 请求携带已弃用的查询参数时，添加 Deprecation: true 与每个参数一条 Warning（299），
+// 并以其中最早的停止支持时间作为 Sunset；请求照常处理，未携带时不添加任何头
+func DeprecatedParams(params ...DeprecatedParam) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := c.Request.URL.Query()
+		var sunset time.Time
+		deprecated := false
+		for _, param := range params {
+			if !query.Has(param.Name) {
+				continue
+			}
+			deprecated = true
+			c.Writer.Header().Add("Warning", deprecationWarning(param))
+			if !param.Sunset.IsZero() && (sunset.IsZero() || param.Sunset.Before(sunset)) {
+				sunset = param.Sunset
+			}
+		}
+		if deprecated {
+			c.Header("Deprecation", "true")
+			if !sunset.IsZero() {
+				c.Header("Sunset", sunset.UTC().Format(http.TimeFormat))
+			}
+		}
+		c.Next()
+	}
+}
+
+// deprecationWarning 按 RFC 7234 的格式（warn-code warn-agent "warn-text"）生成 Warning 头
+func deprecationWarning(param DeprecatedParam) string {
+	text := "Deprecated query parameter " + param.Name
+	if param.Replacement != "" {
+		text += ", use " + param.Replacement + " instead"
+	}
+	return "299 - " + strconv.Quote(text)
+}
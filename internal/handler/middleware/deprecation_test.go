@@ -0,0 +1,80 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+)
+
+func TestDeprecatedParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(middleware.DeprecatedParams(
+		middleware.DeprecatedParam{Name: "page", Replacement: "cursor", Sunset: time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC)},
+		middleware.DeprecatedParam{Name: "offset", Sunset: time.Date(2027, 3, 31, 0, 0, 0, 0, time.UTC)},
+		middleware.DeprecatedParam{Name: "per_page", Replacement: "num"},
+	))
+	r.GET("/articles", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name     string
+		query    string
+		warnings []string
+		sunset   string
+	}{
+		{name: "none", query: "num=10&cursor=abc"},
+		{
+			name:     "with-replacement",
+			query:    "page=2",
+			warnings: []string{`299 - "Deprecated query parameter page, use cursor instead"`},
+			sunset:   "Wed, 30 Jun 2027 00:00:00 GMT",
+		},
+		{
+			name:     "empty-value",
+			query:    "offset=",
+			warnings: []string{`299 - "Deprecated query parameter offset"`},
+			sunset:   "Wed, 31 Mar 2027 00:00:00 GMT",
+		},
+		{
+			name:     "without-sunset",
+			query:    "per_page=20",
+			warnings: []string{`299 - "Deprecated query parameter per_page, use num instead"`},
+		},
+		{
+			name:  "several-use-earliest-sunset",
+			query: "page=2&offset=20",
+			warnings: []string{
+				`299 - "Deprecated query parameter page, use cursor instead"`,
+				`299 - "Deprecated query parameter offset"`,
+			},
+			sunset: "Wed, 31 Mar 2027 00:00:00 GMT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/articles?"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.warnings, w.Header().Values("Warning"))
+			assert.Equal(t, tt.sunset, w.Header().Get("Sunset"))
+			if tt.warnings == nil {
+				assert.Empty(t, w.Header().Get("Deprecation"))
+				return
+			}
+			assert.Equal(t, "true", w.Header().Get("Deprecation"))
+		})
+	}
+}
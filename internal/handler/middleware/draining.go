@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// drainingRetryAfter 关闭期间拒绝请求时建议客户端等待的秒数，重试通常会落到其他实例
+const drainingRetryAfter = "1"
+
+// RejectWhileDraining 在服务关闭期间直接拒绝新到达的请求：返回 503 并设置 Connection: close 与 Retry-After，
+// 避免请求被处理到一半时连接被强制关闭。draining 通常为 server.Server.Draining
+func RejectWhileDraining(draining func() bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !draining() {
+			c.Next()
+			return
+		}
+		c.Header("Connection", "close")
+		c.Header("Retry-After", drainingRetryAfter)
+		HandleError(c, ErrServiceUnavailable)
+		c.Abort()
+	}
+}
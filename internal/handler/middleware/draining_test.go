@@ -0,0 +1,42 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+)
+
+func TestRejectWhileDraining(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var draining atomic.Bool
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware())
+	r.Use(middleware.RejectWhileDraining(draining.Load))
+	r.GET("/articles", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Connection"))
+
+	draining.Store(true)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "close", w.Header().Get("Connection"))
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	draining.Store(false)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
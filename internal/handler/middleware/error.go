@@ -2,10 +2,13 @@ package middleware
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"runtime/debug"
 
 	"github.com/gin-gonic/gin"
-	log "github.com/lingdongomg/g-lib/logger"
+
+	"github.com/bxcodec/go-clean-arch/internal/pkg/logger"
 )
 
 // ErrorResponse 统一错误响应结构
@@ -32,12 +35,18 @@ func (e *AppError) Error() string {
 
 // 预定义错误类型
 var (
-	ErrBadRequest          = &AppError{Code: http.StatusBadRequest, Message: "请求参数错误"}
-	ErrUnauthorized        = &AppError{Code: http.StatusUnauthorized, Message: "未授权访问"}
-	ErrForbidden           = &AppError{Code: http.StatusForbidden, Message: "禁止访问"}
-	ErrNotFound            = &AppError{Code: http.StatusNotFound, Message: "资源不存在"}
-	ErrConflict            = &AppError{Code: http.StatusConflict, Message: "资源冲突"}
-	ErrInternalServerError = &AppError{Code: http.StatusInternalServerError, Message: "服务器内部错误"}
+	ErrBadRequest            = &AppError{Code: http.StatusBadRequest, Message: "请求参数错误"}
+	ErrUnauthorized          = &AppError{Code: http.StatusUnauthorized, Message: "未授权访问"}
+	ErrForbidden             = &AppError{Code: http.StatusForbidden, Message: "禁止访问"}
+	ErrNotFound              = &AppError{Code: http.StatusNotFound, Message: "资源不存在"}
+	ErrConflict              = &AppError{Code: http.StatusConflict, Message: "资源冲突"}
+	ErrUnsupportedMediaType  = &AppError{Code: http.StatusUnsupportedMediaType, Message: "不支持的 Content-Type，请使用 application/json"}
+	ErrPreconditionFailed    = &AppError{Code: http.StatusPreconditionFailed, Message: "资源已被修改，前置条件不满足"}
+	ErrRequestEntityTooLarge = &AppError{Code: http.StatusRequestEntityTooLarge, Message: "请求体过大"}
+	ErrTooManyRequests       = &AppError{Code: http.StatusTooManyRequests, Message: "请求过于频繁，请稍后再试"}
+	ErrInternalServerError   = &AppError{Code: http.StatusInternalServerError, Message: "服务器内部错误"}
+	ErrServiceUnavailable    = &AppError{Code: http.StatusServiceUnavailable, Message: "服务繁忙，请稍后再试"}
+	ErrGatewayTimeout        = &AppError{Code: http.StatusGatewayTimeout, Message: "请求处理超时"}
 )
 
 // NewAppError 创建应用错误
@@ -60,24 +69,43 @@ func NewAppErrorWithErr(code int, message string, err error) *AppError {
 
 // ErrorHandler 统一错误处理中间件（用于panic恢复）
 func ErrorHandler() gin.HandlerFunc {
-	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
-		if err, ok := recovered.(error); ok {
-			handleError(c, err)
-		} else {
-			handleError(c, ErrInternalServerError)
+	return ErrorHandlerWithLogger(logger.Default())
+}
+
+// ErrorHandlerWithLogger 使用注入的 logger 的 panic 恢复中间件
+// panic 一律返回 500，并以 ERROR 级别记录请求 ID 与堆栈；debug 模式下响应 Details 中附带请求 ID 便于排查
+func ErrorHandlerWithLogger(l *logger.Logger) gin.HandlerFunc {
+	// 堆栈由注入的 logger 输出，不再写入 gin 默认的 stderr
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, recovered interface{}) {
+		requestID := GetRequestID(c)
+		l.Errorf("Panic recovered - Method: %s, URI: %s, IP: %s, RequestID: %s, Panic: %v\n%s",
+			c.Request.Method, c.Request.RequestURI, c.ClientIP(), requestID, recovered, debug.Stack())
+
+		resp := ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: Localize(c, ErrInternalServerError.Message),
 		}
+		if gin.IsDebugging() {
+			resp.Details = fmt.Sprintf("request_id: %s, panic: %v", requestID, recovered)
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, resp)
 	})
 }
 
 // ErrorMiddleware 错误处理中间件（用于手动错误处理）
 func ErrorMiddleware() gin.HandlerFunc {
+	return ErrorMiddlewareWithLogger(logger.Default())
+}
+
+// ErrorMiddlewareWithLogger 使用注入的 logger 的错误处理中间件
+func ErrorMiddlewareWithLogger(l *logger.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
 
 		// 检查是否有错误
 		if len(c.Errors) > 0 {
 			err := c.Errors.Last().Err
-			handleError(c, err)
+			handleError(c, l, err)
 			c.Abort()
 		}
 	}
@@ -88,22 +116,22 @@ func HandleError(c *gin.Context, err error) {
 	c.Error(err)
 }
 
-func handleError(c *gin.Context, err error) {
+func handleError(c *gin.Context, l *logger.Logger, err error) {
 	// 检查是否是自定义应用错误
 	var appErr *AppError
 	if errors.As(err, &appErr) {
 		if appErr.Code >= 500 {
 			// 服务器错误，使用 ERROR 级别
-			log.Errorf("Server error - Method: %s, URI: %s, UserAgent: %s, IP: %s, Error: %v",
+			l.Errorf("Server error - Method: %s, URI: %s, UserAgent: %s, IP: %s, Error: %v",
 				c.Request.Method, c.Request.RequestURI, c.Request.UserAgent(), c.ClientIP(), err)
 		} else {
 			// 客户端错误，使用 WARN 级别
-			log.Warnf("Client error - Method: %s, URI: %s, UserAgent: %s, IP: %s, Error: %v",
+			l.Warnf("Client error - Method: %s, URI: %s, UserAgent: %s, IP: %s, Error: %v",
 				c.Request.Method, c.Request.RequestURI, c.Request.UserAgent(), c.ClientIP(), err)
 		}
 		c.JSON(appErr.Code, ErrorResponse{
 			Code:    appErr.Code,
-			Message: appErr.Message,
+			Message: Localize(c, appErr.Message),
 			Details: appErr.Details,
 		})
 		return
@@ -114,23 +142,23 @@ func handleError(c *gin.Context, err error) {
 		code := http.StatusBadRequest
 		message := "请求参数错误"
 
-		log.Warnf("Binding error - Method: %s, URI: %s, UserAgent: %s, IP: %s, Error: %v",
+		l.Warnf("Binding error - Method: %s, URI: %s, UserAgent: %s, IP: %s, Error: %v",
 			c.Request.Method, c.Request.RequestURI, c.Request.UserAgent(), c.ClientIP(), err)
 
 		c.JSON(code, ErrorResponse{
 			Code:    code,
-			Message: message,
+			Message: Localize(c, message),
 			Details: bindErr.Error(),
 		})
 		return
 	}
 
 	// 未知错误，返回 500
-	log.Errorf("Unknown error - Method: %s, URI: %s, UserAgent: %s, IP: %s, Error: %v",
+	l.Errorf("Unknown error - Method: %s, URI: %s, UserAgent: %s, IP: %s, Error: %v",
 		c.Request.Method, c.Request.RequestURI, c.Request.UserAgent(), c.ClientIP(), err)
 	c.JSON(http.StatusInternalServerError, ErrorResponse{
 		Code:    http.StatusInternalServerError,
-		Message: "服务器内部错误",
+		Message: Localize(c, "服务器内部错误"),
 	})
 }
 
@@ -0,0 +1,156 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/logger"
+)
+
+type capturedEntry struct {
+	level logger.Level
+	msg   string
+}
+
+func newCaptureLogger(level logger.Level) (*logger.Logger, *[]capturedEntry) {
+	entries := &[]capturedEntry{}
+	l := logger.New(level, func(level logger.Level, msg string) {
+		*entries = append(*entries, capturedEntry{level: level, msg: msg})
+	})
+	return l, entries
+}
+
+func TestErrorMiddlewareWithLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	l, entries := newCaptureLogger(logger.InfoLevel)
+
+	r := gin.New()
+	r.Use(middleware.ErrorMiddlewareWithLogger(l))
+	r.GET("/server", func(c *gin.Context) {
+		middleware.HandleError(c, middleware.NewAppErrorWithErr(http.StatusInternalServerError, "失败", errors.New("boom")))
+	})
+	r.GET("/client", func(c *gin.Context) {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/server", nil))
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/client", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	require.Len(t, *entries, 2)
+	assert.Equal(t, logger.ErrorLevel, (*entries)[0].level)
+	assert.Contains(t, (*entries)[0].msg, "boom")
+	assert.Equal(t, logger.WarnLevel, (*entries)[1].level)
+}
+
+func TestErrorMiddlewareWithLoggerRespectsLevel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	l, entries := newCaptureLogger(logger.ErrorLevel)
+
+	r := gin.New()
+	r.Use(middleware.ErrorMiddlewareWithLogger(l))
+	r.GET("/client", func(c *gin.Context) {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/client", nil))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, *entries)
+}
+
+func TestErrorHandlerWithLoggerPanic(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "error", value: errors.New("boom")},
+		{name: "string", value: "boom"},
+		{name: "int", value: 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.DebugMode)
+			defer gin.SetMode(gin.TestMode)
+			l, entries := newCaptureLogger(logger.InfoLevel)
+
+			r := gin.New()
+			r.Use(middleware.RequestID())
+			r.Use(middleware.ErrorHandlerWithLogger(l))
+			r.GET("/panic", func(c *gin.Context) {
+				panic(tt.value)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+			req.Header.Set(middleware.RequestIDHeader, "req-123")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusInternalServerError, w.Code)
+			assert.Equal(t, "req-123", w.Header().Get(middleware.RequestIDHeader))
+			var resp middleware.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, http.StatusInternalServerError, resp.Code)
+			assert.Contains(t, resp.Details, "req-123")
+
+			require.Len(t, *entries, 1)
+			assert.Equal(t, logger.ErrorLevel, (*entries)[0].level)
+			assert.Contains(t, (*entries)[0].msg, "RequestID: req-123")
+			assert.Contains(t, (*entries)[0].msg, fmt.Sprint(tt.value))
+			assert.Contains(t, (*entries)[0].msg, "goroutine")
+		})
+	}
+}
+
+func TestErrorHandlerWithLoggerPanicReleaseMode(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	defer gin.SetMode(gin.TestMode)
+	l, _ := newCaptureLogger(logger.InfoLevel)
+
+	r := gin.New()
+	r.Use(middleware.RequestID())
+	r.Use(middleware.ErrorHandlerWithLogger(l))
+	r.GET("/panic", func(c *gin.Context) {
+		panic("secret detail")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotEmpty(t, w.Header().Get(middleware.RequestIDHeader))
+	assert.NotContains(t, w.Body.String(), "secret detail")
+}
+
+func TestAccessLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	l, entries := newCaptureLogger(logger.InfoLevel)
+
+	r := gin.New()
+	r.Use(middleware.AccessLog(l))
+	r.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test?num=1", nil))
+
+	require.Len(t, *entries, 1)
+	assert.Contains(t, (*entries)[0].msg, "URI: /test?num=1")
+	assert.Contains(t, (*entries)[0].msg, "Status: 200")
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"maps"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureFlags 功能开关，可在运行时修改（见 PUT /admin/features/:name），无需重新部署。
+// 未配置的功能默认开启，nil 的 *FeatureFlags 视为全部开启
+type FeatureFlags struct {
+	mu      sync.RWMutex
+	enabled map[string]bool
+	status  int
+}
+
+// NewFeatureFlags 按配置创建功能开关，disabledStatus 为关闭功能的响应状态码：
+// 404 时接口表现为不存在，503 时提示功能暂不可用；其他值按 404 处理
+func NewFeatureFlags(flags map[string]bool, disabledStatus int) *FeatureFlags {
+	if disabledStatus != http.StatusServiceUnavailable {
+		disabledStatus = http.StatusNotFound
+	}
+	enabled := make(map[string]bool, len(flags))
+	maps.Copy(enabled, flags)
+	return &FeatureFlags{enabled: enabled, status: disabledStatus}
+}
+
+// Enabled 返回功能是否开启
+func (f *FeatureFlags) Enabled(name string) bool {
+	if f == nil {
+		return true
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	enabled, ok := f.enabled[name]
+	return !ok || enabled
+}
+
+// Set 开启或关闭功能，立即对后续请求生效
+func (f *FeatureFlags) Set(name string, enabled bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.enabled[name] = enabled
+}
+
+// All 返回已配置的功能开关的副本
+func (f *FeatureFlags) All() map[string]bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return maps.Clone(f.enabled)
+}
+
+// RequireFeature 在功能关闭时中止请求，按配置返回 404 或 503
+func RequireFeature(flags *FeatureFlags, name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if flags.Enabled(name) {
+			c.Next()
+			return
+		}
+		if flags.status == http.StatusServiceUnavailable {
+			HandleError(c, NewAppError(http.StatusServiceUnavailable, "功能暂不可用", name))
+		} else {
+			HandleError(c, ErrNotFound)
+		}
+		c.Abort()
+	}
+}
@@ -0,0 +1,49 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+)
+
+func TestRequireFeature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		flags  *middleware.FeatureFlags
+		code   int
+		enable bool
+	}{
+		{name: "enabled", flags: middleware.NewFeatureFlags(map[string]bool{"export": true}, http.StatusNotFound), code: http.StatusOK},
+		{name: "unlisted-is-enabled", flags: middleware.NewFeatureFlags(nil, http.StatusNotFound), code: http.StatusOK},
+		{name: "nil-flags", flags: nil, code: http.StatusOK},
+		{name: "disabled-404", flags: middleware.NewFeatureFlags(map[string]bool{"export": false}, http.StatusNotFound), code: http.StatusNotFound},
+		{name: "disabled-503", flags: middleware.NewFeatureFlags(map[string]bool{"export": false}, http.StatusServiceUnavailable), code: http.StatusServiceUnavailable},
+		{name: "other-status-is-404", flags: middleware.NewFeatureFlags(map[string]bool{"export": false}, http.StatusTeapot), code: http.StatusNotFound},
+		{name: "enabled-at-runtime", flags: middleware.NewFeatureFlags(map[string]bool{"export": false}, http.StatusNotFound), code: http.StatusOK, enable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(middleware.ErrorMiddleware())
+			r.GET("/export", middleware.RequireFeature(tt.flags, "export"), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+			if tt.enable {
+				tt.flags.Set("export", true)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export", nil))
+
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTPSConfig HTTPS 强制配置
+type HTTPSConfig struct {
+	// Reject 为 true 时对非 HTTPS 请求返回 403，否则重定向到对应的 https 地址
+	Reject bool
+	// SkipPaths 不要求 HTTPS 的路径，例如编排系统直接访问实例的健康检查
+	SkipPaths []string
+}
+
+// DefaultHTTPSConfig 默认重定向，并放行健康检查
+var DefaultHTTPSConfig = HTTPSConfig{SkipPaths: []string{"/health", "/readyz"}}
+
+// ErrHTTPSRequired 拒绝非 HTTPS 请求时返回的错误
+var ErrHTTPSRequired = &AppError{Code: http.StatusForbidden, Message: "请使用 HTTPS 访问"}
+
+// RequireHTTPS 将非 HTTPS 请求重定向到 https 地址
+func RequireHTTPS() gin.HandlerFunc {
+	return RequireHTTPSWithConfig(DefaultHTTPSConfig)
+}
+
+// RequireHTTPSWithConfig 仅在 release 模式下生效，以便本地调试仍可使用 http。
+// 请求经 TLS 终结代理转发时按 X-Forwarded-Proto 判断协议（多级代理时取第一个值，即客户端使用的协议），
+// 因此只应部署在会覆盖该请求头的代理之后。GET、HEAD 返回 301，其他方法返回 308 以保留方法与请求体
+func RequireHTTPSWithConfig(cfg HTTPSConfig) gin.HandlerFunc {
+	if gin.Mode() != gin.ReleaseMode {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		if isHTTPS(c.Request) || slices.Contains(cfg.SkipPaths, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+		if cfg.Reject {
+			HandleError(c, ErrHTTPSRequired)
+			c.Abort()
+			return
+		}
+
+		status := http.StatusPermanentRedirect
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			status = http.StatusMovedPermanently
+		}
+		c.Redirect(status, "https://"+c.Request.Host+c.Request.URL.RequestURI())
+		c.Abort()
+	}
+}
+
+func isHTTPS(r *http.Request) bool {
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		first, _, _ := strings.Cut(proto, ",")
+		return strings.EqualFold(strings.TrimSpace(first), "https")
+	}
+	return r.TLS != nil
+}
@@ -0,0 +1,96 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+)
+
+func setupHTTPSRouter(t *testing.T, mode string, cfg middleware.HTTPSConfig) *gin.Engine {
+	t.Helper()
+	gin.SetMode(mode)
+	t.Cleanup(func() { gin.SetMode(gin.TestMode) })
+
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware())
+	r.Use(middleware.RequireHTTPSWithConfig(cfg))
+	r.Any("/articles", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.GET("/health", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestRequireHTTPSRedirect(t *testing.T) {
+	r := setupHTTPSRouter(t, gin.ReleaseMode, middleware.DefaultHTTPSConfig)
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		proto    string
+		code     int
+		location string
+	}{
+		{name: "http-get", method: http.MethodGet, target: "/articles?num=10", proto: "http", code: http.StatusMovedPermanently, location: "https://example.com/articles?num=10"},
+		{name: "no-header", method: http.MethodGet, target: "/articles", code: http.StatusMovedPermanently, location: "https://example.com/articles"},
+		{name: "http-post-keeps-method", method: http.MethodPost, target: "/articles", proto: "http", code: http.StatusPermanentRedirect, location: "https://example.com/articles"},
+		{name: "client-proto-first", method: http.MethodGet, target: "/articles", proto: "http, https", code: http.StatusMovedPermanently, location: "https://example.com/articles"},
+		{name: "https", method: http.MethodGet, target: "/articles", proto: "https", code: http.StatusOK},
+		{name: "https-uppercase", method: http.MethodPost, target: "/articles", proto: "HTTPS", code: http.StatusOK},
+		{name: "skipped-path", method: http.MethodGet, target: "/health", proto: "http", code: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "http://example.com"+tt.target, strings.NewReader(`{}`))
+			if tt.proto != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.proto)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.location, w.Header().Get("Location"))
+		})
+	}
+}
+
+func TestRequireHTTPSReject(t *testing.T) {
+	r := setupHTTPSRouter(t, gin.ReleaseMode, middleware.HTTPSConfig{Reject: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/articles", nil)
+	req.Header.Set("X-Forwarded-Proto", "http")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, w.Header().Get("Location"))
+	assert.Contains(t, w.Body.String(), "请使用 HTTPS 访问")
+
+	req = httptest.NewRequest(http.MethodGet, "/articles", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequireHTTPSDebugMode(t *testing.T) {
+	r := setupHTTPSRouter(t, gin.DebugMode, middleware.HTTPSConfig{Reject: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/articles", nil)
+	req.Header.Set("X-Forwarded-Proto", "http")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// maxInFlightRetryAfter 拒绝请求时建议客户端等待的秒数
+const maxInFlightRetryAfter = "1"
+
+// MaxInFlight 限制同时处理中的请求数，保护数据库连接池：已有 n 个请求在处理时直接返回 503 并设置 Retry-After。
+// n <= 0 表示不限制
+func MaxInFlight(n int) gin.HandlerFunc {
+	if n <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	sem := make(chan struct{}, n)
+	return func(c *gin.Context) {
+		select {
+		case sem <- struct{}{}:
+			defer func() { <-sem }()
+			c.Next()
+		default:
+			c.Header("Retry-After", maxInFlightRetryAfter)
+			HandleError(c, ErrServiceUnavailable)
+			c.Abort()
+		}
+	}
+}
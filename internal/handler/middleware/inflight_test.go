@@ -0,0 +1,63 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+)
+
+func TestMaxInFlight(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const limit, total = 3, 8
+
+	started := make(chan struct{}, total)
+	release := make(chan struct{})
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware())
+	r.Use(middleware.MaxInFlight(limit))
+	r.GET("/slow", func(c *gin.Context) {
+		started <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+
+	codes := make(chan *httptest.ResponseRecorder, total)
+	var wg sync.WaitGroup
+	for i := 0; i < total; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+			codes <- w
+		}()
+	}
+
+	// wait until the limit is reached, every other request must be rejected without blocking
+	for i := 0; i < limit; i++ {
+		<-started
+	}
+	for i := 0; i < total-limit; i++ {
+		w := <-codes
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	}
+	close(release)
+	wg.Wait()
+	close(codes)
+
+	for w := range codes {
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	// the slots are released once the requests complete
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
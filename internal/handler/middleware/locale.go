@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/internal/pkg/requestctx"
+)
+
+// DefaultLocale 默认语言，错误消息原文即为中文
+const DefaultLocale = "zh"
+
+const localeKey = "middleware.locale"
+
+// messageCatalog 按语言存放错误消息的翻译，以中文原文为键；缺少的条目回退为中文原文
+var messageCatalog = map[string]map[string]string{
+	"en": {
+		"请求参数错误": "Invalid request parameters",
+		"未授权访问":  "Unauthorized",
+		"禁止访问":   "Forbidden",
+		"资源不存在":  "Resource not found",
+		"资源冲突":   "Resource conflict",
+		"请求体过大":  "Request body too large",
+		"不支持的 Content-Type，请使用 application/json": "Unsupported Content-Type, use application/json",
+		"资源已被修改，前置条件不满足":                         "The resource has been modified, precondition failed",
+		"请求过于频繁，请稍后再试":                           "Too many requests, please retry later",
+		"服务器内部错误":                                "Internal server error",
+		"服务繁忙，请稍后再试":                             "Service busy, please retry later",
+		"请求处理超时":                                 "Request timed out",
+		"不支持的请求方法":                               "Method not allowed",
+		"参数验证失败":                                 "Validation failed",
+		"查询参数重复":                                 "Duplicated query parameter",
+		"请使用 HTTPS 访问":                           "HTTPS required",
+		"功能暂不可用":                                 "Feature temporarily unavailable",
+		"功能不存在":                                  "Unknown feature",
+		"不支持的 API 版本":                            "Unsupported API version",
+		"num 参数错误":                               "Invalid num parameter",
+		"status 参数错误":                            "Invalid status parameter",
+		"from 参数错误":                              "Invalid from parameter",
+		"to 参数错误":                                "Invalid to parameter",
+		"fields 参数错误":                            "Invalid fields parameter",
+		"author_id 参数错误":                         "Invalid author_id parameter",
+		"tags 参数错误":                              "Invalid tags parameter",
+		"match 参数错误":                             "Invalid match parameter",
+		"direction 参数错误":                         "Invalid direction parameter",
+		"limit 参数错误":                             "Invalid limit parameter",
+		"title 参数错误":                             "Invalid title parameter",
+		"ids 参数错误":                               "Invalid ids parameter",
+		"level 参数错误":                             "Invalid level parameter",
+		"since 参数错误":                             "Invalid since parameter",
+		"fresh 参数错误":                             "Invalid fresh parameter",
+		"with_counts 参数错误":                       "Invalid with_counts parameter",
+		"时间范围错误":                                 "Invalid time range",
+		"If-Unmodified-Since 格式错误":               "Invalid If-Unmodified-Since header",
+		"CSV 格式错误":                               "Invalid CSV",
+		"缺少上传文件 file":                            "Missing upload file",
+		"读取上传文件失败":                               "Failed to read the uploaded file",
+		"获取文章失败":                                 "Failed to get the article",
+		"获取文章列表失败":                               "Failed to list articles",
+		"获取文章总数失败":                               "Failed to count articles",
+		"获取文章统计失败":                               "Failed to get article statistics",
+		"获取相邻文章失败":                               "Failed to get neighboring articles",
+		"获取作者文章数失败":                              "Failed to count the author's articles",
+		"获取作者列表失败":                               "Failed to list authors",
+		"复制文章失败":                                 "Failed to clone the article",
+		"记录浏览失败":                                 "Failed to record the view",
+		"创建文章失败":                                 "Failed to create the article",
+		"保存文章失败":                                 "Failed to save the article",
+		"更新文章状态失败":                               "Failed to update the article status",
+		"删除文章失败":                                 "Failed to delete the article",
+		"导出文章失败":                                 "Failed to export articles",
+		"导入文章失败":                                 "Failed to import articles",
+	},
+}
+
+// Locale 解析 Accept-Language，按 q 值选出首个支持的语言（仅比较主语言标签，如 en-US 视为 en），
+// 并通过 Content-Language 告知客户端；没有可用语言时使用 DefaultLocale。
+// 协商结果同时写入请求的 context，service 层通过 requestctx.LocaleFromContext 读取
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := negotiateLocale(c.GetHeader("Accept-Language"))
+		c.Set(localeKey, locale)
+		c.Request = c.Request.WithContext(requestctx.WithLocale(c.Request.Context(), locale))
+		c.Header("Content-Language", locale)
+		c.Next()
+	}
+}
+
+// GetLocale 返回当前请求协商出的语言，未经过 Locale 中间件时为 DefaultLocale
+func GetLocale(c *gin.Context) string {
+	if locale := c.GetString(localeKey); locale != "" {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// Localize 将中文错误消息翻译为当前请求的语言，目录中没有的消息原样返回
+func Localize(c *gin.Context, message string) string {
+	if translated, ok := messageCatalog[GetLocale(c)][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// negotiateLocale 忽略格式错误、q=0 以及不支持的条目，q 值相同时保持原有顺序
+func negotiateLocale(header string) string {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		primary, _, _ := strings.Cut(tag, "-")
+		if primary == "" {
+			continue
+		}
+
+		q := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || parsed < 0 || parsed > 1 {
+				continue
+			}
+			q = parsed
+		}
+		if q == 0 || !supportedLocale(primary) {
+			continue
+		}
+		candidates = append(candidates, candidate{tag: primary, q: q})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	if len(candidates) == 0 {
+		return DefaultLocale
+	}
+	return candidates[0].tag
+}
+
+func supportedLocale(tag string) bool {
+	if tag == DefaultLocale {
+		return true
+	}
+	_, ok := messageCatalog[tag]
+	return ok
+}
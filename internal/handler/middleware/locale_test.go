@@ -0,0 +1,131 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/requestctx"
+)
+
+func TestLocale(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(middleware.Locale())
+	r.Use(middleware.ErrorHandler())
+	r.Use(middleware.ErrorMiddleware())
+	r.GET("/missing", func(c *gin.Context) {
+		middleware.HandleError(c, middleware.ErrNotFound)
+	})
+	r.GET("/untranslated", func(c *gin.Context) {
+		middleware.HandleError(c, middleware.NewAppError(http.StatusBadRequest, "没有翻译的消息", ""))
+	})
+	r.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptLanguage string
+		locale         string
+		message        string
+	}{
+		{name: "no-header", path: "/missing", locale: "zh", message: "资源不存在"},
+		{name: "en", path: "/missing", acceptLanguage: "en", locale: "en", message: "Resource not found"},
+		{name: "region-and-case", path: "/missing", acceptLanguage: "EN-us", locale: "en", message: "Resource not found"},
+		{name: "quality-order", path: "/missing", acceptLanguage: "zh-CN;q=0.5, en;q=0.8", locale: "en", message: "Resource not found"},
+		{name: "zero-quality", path: "/missing", acceptLanguage: "en;q=0, zh;q=0.1", locale: "zh", message: "资源不存在"},
+		{name: "unknown-falls-back", path: "/missing", acceptLanguage: "fr-FR, de;q=0.9", locale: "zh", message: "资源不存在"},
+		{name: "skips-unknown", path: "/missing", acceptLanguage: "fr, en;q=0.5", locale: "en", message: "Resource not found"},
+		{name: "malformed", path: "/missing", acceptLanguage: "en;q=abc, ;q=1", locale: "zh", message: "资源不存在"},
+		{name: "untranslated-message", path: "/untranslated", acceptLanguage: "en", locale: "en", message: "没有翻译的消息"},
+		{name: "panic", path: "/panic", acceptLanguage: "en", locale: "en", message: "Internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.locale, w.Header().Get("Content-Language"))
+			var res middleware.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			assert.Equal(t, tt.message, res.Message)
+		})
+	}
+}
+
+func TestLocalizeWithoutMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	assert.Equal(t, middleware.DefaultLocale, middleware.GetLocale(c))
+	assert.Equal(t, "资源不存在", middleware.Localize(c, "资源不存在"))
+}
+
+// greeter stands for a service, it only sees the context handed down by the handler
+type greeter struct{}
+
+func (greeter) Greet(ctx context.Context) string {
+	actor := requestctx.ActorFromContext(ctx)
+	if actor == "" {
+		actor = "anonymous"
+	}
+	return requestctx.LocaleFromContext(ctx) + ":" + actor
+}
+
+func TestRequestContextValues(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := []byte("s3cret")
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{Subject: "alice"}).SignedString(secret)
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(middleware.Locale())
+	r.Use(middleware.JWTActor(secret))
+	r.GET("/greeting", func(c *gin.Context) {
+		c.String(http.StatusOK, greeter{}.Greet(c.Request.Context()))
+	})
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		authorization  string
+		greeting       string
+	}{
+		{name: "defaults", greeting: "zh:anonymous"},
+		{name: "negotiated-locale", acceptLanguage: "en-US", greeting: "en:anonymous"},
+		{name: "actor-and-locale", acceptLanguage: "en", authorization: "Bearer " + token, greeting: "en:alice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/greeting", nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.greeting, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrMethodNotAllowed 路径存在但不支持该请求方法
+var ErrMethodNotAllowed = &AppError{Code: http.StatusMethodNotAllowed, Message: "不支持的请求方法"}
+
+// EnableMethodNotAllowed 让 gin 对路径存在但方法不支持的请求返回 405（默认返回 404），
+// 并通过 Allow 响应头列出该路径支持的方法
+func EnableMethodNotAllowed(r *gin.Engine) {
+	r.HandleMethodNotAllowed = true
+	r.NoMethod(MethodNotAllowed(r))
+}
+
+// MethodNotAllowed 405 处理函数，响应体为统一的 ErrorResponse
+func MethodNotAllowed(r *gin.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if allow := allowedMethods(r.Routes(), c.Request.URL.Path); len(allow) > 0 {
+			c.Header("Allow", strings.Join(allow, ", "))
+		}
+		HandleError(c, ErrMethodNotAllowed)
+		c.Abort()
+	}
+}
+
+// allowedMethods 返回注册了与 path 匹配的路由的方法
+func allowedMethods(routes gin.RoutesInfo, path string) []string {
+	seen := map[string]bool{}
+	var methods []string
+	for _, route := range routes {
+		if seen[route.Method] || !matchRoute(route.Path, path) {
+			continue
+		}
+		seen[route.Method] = true
+		methods = append(methods, route.Method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// matchRoute 按 gin 的路由语法匹配路径：":name" 匹配一段，"*name" 匹配剩余部分
+func matchRoute(pattern, path string) bool {
+	patternSegs := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegs := strings.Split(strings.Trim(path, "/"), "/")
+	for i, seg := range patternSegs {
+		if strings.HasPrefix(seg, "*") {
+			return true
+		}
+		if i >= len(pathSegs) {
+			return false
+		}
+		if !strings.HasPrefix(seg, ":") && seg != pathSegs[i] {
+			return false
+		}
+	}
+	return len(patternSegs) == len(pathSegs)
+}
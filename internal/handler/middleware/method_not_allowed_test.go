@@ -0,0 +1,59 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+)
+
+func TestMethodNotAllowed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware())
+	middleware.EnableMethodNotAllowed(r)
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/api/v1/articles", ok)
+	r.POST("/api/v1/articles", ok)
+	r.GET("/api/v1/articles/:id", ok)
+	r.DELETE("/api/v1/articles/:id", ok)
+	r.PUT("/api/v1/articles/:id/status", ok)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		code   int
+		allow  string
+	}{
+		{name: "patch-item", method: http.MethodPatch, path: "/api/v1/articles/1", code: http.StatusMethodNotAllowed, allow: "DELETE, GET"},
+		{name: "delete-collection", method: http.MethodDelete, path: "/api/v1/articles", code: http.StatusMethodNotAllowed, allow: "GET, POST"},
+		{name: "get-status", method: http.MethodGet, path: "/api/v1/articles/1/status", code: http.StatusMethodNotAllowed, allow: "PUT"},
+		{name: "allowed", method: http.MethodGet, path: "/api/v1/articles/1", code: http.StatusOK},
+		{name: "unknown-path", method: http.MethodGet, path: "/api/v1/unknown", code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.allow, w.Header().Get("Allow"))
+			if tt.code == http.StatusMethodNotAllowed {
+				var body middleware.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, http.StatusMethodNotAllowed, body.Code)
+			}
+		})
+	}
+}
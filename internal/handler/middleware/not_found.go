@@ -0,0 +1,12 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// NotFound 未匹配任何路由时的处理函数，通过 r.NoRoute 注册，
+// 以统一的 ErrorResponse 代替 gin 默认的纯文本 "404 page not found"
+func NotFound() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleError(c, ErrNotFound)
+		c.Abort()
+	}
+}
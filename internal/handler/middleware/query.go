@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UniqueQueryParams 拒绝重复出现的查询参数（如 ?num=1&num=9999），返回 400。
+// gin 的 c.Query 只取第一个值，而网关或缓存可能取最后一个，两者不一致时容易被利用，因此直接拒绝
+func UniqueQueryParams(keys ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := c.Request.URL.Query()
+		for _, key := range keys {
+			if len(query[key]) > 1 {
+				HandleError(c, NewAppError(http.StatusBadRequest, "查询参数重复", key))
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
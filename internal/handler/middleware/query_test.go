@@ -0,0 +1,45 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+)
+
+func TestUniqueQueryParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware())
+	r.GET("/articles", middleware.UniqueQueryParams("num", "cursor"), func(c *gin.Context) {
+		c.String(http.StatusOK, c.Query("num"))
+	})
+
+	tests := []struct {
+		name  string
+		query string
+		code  int
+	}{
+		{name: "single-values", query: "?num=1&cursor=abc", code: http.StatusOK},
+		{name: "duplicated-num", query: "?num=1&num=9999", code: http.StatusBadRequest},
+		{name: "duplicated-cursor", query: "?cursor=a&cursor=b", code: http.StatusBadRequest},
+		{name: "duplicated-empty", query: "?num=&num=5", code: http.StatusBadRequest},
+		{name: "unguarded-key", query: "?tag=a&tag=b", code: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/articles"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
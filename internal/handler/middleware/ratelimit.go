@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit 固定窗口限流：每个 window 内最多放行 limit 个请求，超出返回 429 并设置 Retry-After。
+// 计数对所有调用方共享，适用于运维接口等低频端点
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	var (
+		mu          sync.Mutex
+		windowStart time.Time
+		count       int
+	)
+	return func(c *gin.Context) {
+		mu.Lock()
+		t := time.Now()
+		if t.Sub(windowStart) >= window {
+			windowStart = t
+			count = 0
+		}
+		count++
+		allowed := count <= limit
+		retryAfter := windowStart.Add(window).Sub(t)
+		mu.Unlock()
+
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			HandleError(c, ErrTooManyRequests)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServerTimingHeader 响应耗时响应头
+const ServerTimingHeader = "Server-Timing"
+
+// ServerTiming 在响应中添加 Server-Timing: total;dur=<毫秒> 响应头。
+// 响应头必须在写入响应体之前设置，因此通过包装 ResponseWriter 在首次写入时计算耗时。
+func ServerTiming() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w := &timingWriter{ResponseWriter: c.Writer, start: time.Now()}
+		c.Writer = w
+		c.Next()
+		// 没有响应体时，gin 在所有中间件返回后才发送响应头
+		w.stamp()
+	}
+}
+
+// timingWriter 在响应头发出前写入 Server-Timing
+type timingWriter struct {
+	gin.ResponseWriter
+	start time.Time
+}
+
+func (w *timingWriter) stamp() {
+	if w.Written() {
+		return
+	}
+	ms := float64(time.Since(w.start).Microseconds()) / 1000
+	w.Header().Set(ServerTimingHeader, "total;dur="+strconv.FormatFloat(ms, 'f', 3, 64))
+}
+
+func (w *timingWriter) WriteHeaderNow() {
+	w.stamp()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *timingWriter) Write(data []byte) (int, error) {
+	w.stamp()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timingWriter) WriteString(s string) (int, error) {
+	w.stamp()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *timingWriter) Flush() {
+	w.stamp()
+	w.ResponseWriter.Flush()
+}
@@ -0,0 +1,65 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+)
+
+func parseServerTiming(t *testing.T, header string) float64 {
+	t.Helper()
+	dur, ok := strings.CutPrefix(header, "total;dur=")
+	require.True(t, ok, "unexpected Server-Timing %q", header)
+	ms, err := strconv.ParseFloat(dur, 64)
+	require.NoError(t, err)
+	return ms
+}
+
+func TestServerTiming(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+	}{
+		{name: "json", handler: func(c *gin.Context) {
+			time.Sleep(5 * time.Millisecond)
+			c.JSON(http.StatusOK, gin.H{"ok": true})
+		}},
+		{name: "status-only", handler: func(c *gin.Context) {
+			time.Sleep(5 * time.Millisecond)
+			c.Status(http.StatusNoContent)
+		}},
+		{name: "error", handler: func(c *gin.Context) {
+			time.Sleep(5 * time.Millisecond)
+			middleware.HandleError(c, middleware.ErrNotFound)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(middleware.ServerTiming())
+			r.Use(middleware.ErrorMiddleware())
+			r.GET("/test", tt.handler)
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			ms := parseServerTiming(t, w.Header().Get(middleware.ServerTimingHeader))
+			assert.Greater(t, ms, 0.0)
+			assert.GreaterOrEqual(t, ms, 5.0)
+		})
+	}
+}
@@ -2,18 +2,63 @@ package middleware
 
 import (
 	"context"
+	"crypto/subtle"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	// RequestTimeoutHeader 内部调用方请求的超时时间（秒）
+	RequestTimeoutHeader = "X-Request-Timeout"
+	// InternalSecretHeader 内部调用方携带的共享密钥
+	InternalSecretHeader = "X-Internal-Secret"
+)
+
+// TimeoutConfig 请求超时配置
+type TimeoutConfig struct {
+	// Default 普通请求的超时时间
+	Default time.Duration
+	// Max 内部调用方通过 X-Request-Timeout 可申请的最大超时时间
+	Max time.Duration
+	// Secret 内部调用方共享密钥，为空时不接受 X-Request-Timeout
+	Secret string
+}
+
 // SetRequestContextWithTimeout will set the request context with timeout for every incoming HTTP Request
 func SetRequestContextWithTimeout(d time.Duration) gin.HandlerFunc {
+	return SetRequestContextWithTimeoutConfig(TimeoutConfig{Default: d})
+}
+
+// SetRequestContextWithTimeoutConfig will set the request context with timeout for every incoming HTTP Request,
+// trusted internal callers may override the timeout up to cfg.Max
+func SetRequestContextWithTimeoutConfig(cfg TimeoutConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout(c, cfg))
 		defer cancel()
 
 		c.Request = c.Request.WithContext(ctx)
 		c.Next()
 	}
 }
+
+// requestTimeout 仅在共享密钥匹配时采用 X-Request-Timeout，并截断到 cfg.Max
+func requestTimeout(c *gin.Context, cfg TimeoutConfig) time.Duration {
+	if cfg.Secret == "" || cfg.Max <= 0 {
+		return cfg.Default
+	}
+	secret := c.GetHeader(InternalSecretHeader)
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(cfg.Secret)) != 1 {
+		return cfg.Default
+	}
+	seconds, err := strconv.Atoi(c.GetHeader(RequestTimeoutHeader))
+	if err != nil || seconds <= 0 {
+		return cfg.Default
+	}
+	d := time.Duration(seconds) * time.Second
+	if d > cfg.Max {
+		return cfg.Max
+	}
+	return d
+}
@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimeoutWithResponse 与 SetRequestContextWithTimeoutConfig 一样设置请求超时，但不依赖处理函数响应取消：
+// 处理函数在单独的 goroutine 中执行，响应先写入缓冲，超时后立即返回 504 JSON 并中止，之后的写入全部丢弃。
+// 处理函数调用 Flush（流式导出）后响应已发出，超时不再改写响应。
+// 本中间件仍会等处理函数返回后才结束，避免 gin.Context 被回收复用时还在使用
+func TimeoutWithResponse(cfg TimeoutConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout(c, cfg))
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		// 处理函数运行期间不再读取 c，超时响应体提前生成
+		body, _ := json.Marshal(ErrorResponse{
+			Code:    ErrGatewayTimeout.Code,
+			Message: Localize(c, ErrGatewayTimeout.Message),
+		})
+
+		w := newTimeoutWriter(c.Writer)
+		c.Writer = w
+		done := make(chan struct{})
+		var recovered interface{}
+		go func() {
+			defer close(done)
+			defer func() { recovered = recover() }()
+			c.Next()
+		}()
+
+		timedOut := false
+		select {
+		case <-done:
+		case <-ctx.Done():
+			// 客户端断开同样会取消 ctx，只有超时才返回 504
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				timedOut = w.timeout(body)
+			}
+			<-done
+		}
+
+		if recovered != nil {
+			c.Writer = w.ResponseWriter
+			panic(recovered)
+		}
+		if timedOut {
+			// 保留包装，外层中间件（如 ErrorMiddleware）的写入同样丢弃，访问日志记录 504
+			c.Abort()
+			return
+		}
+		w.commit()
+		c.Writer = w.ResponseWriter
+	}
+}
+
+// timeoutWriter 缓冲处理函数的响应头与响应体，保证客户端只收到处理结果与超时响应中的一个
+type timeoutWriter struct {
+	gin.ResponseWriter
+
+	mu          sync.Mutex
+	header      http.Header
+	buf         bytes.Buffer
+	status      int
+	wroteHeader bool
+	committed   bool
+	timedOut    bool
+}
+
+func newTimeoutWriter(w gin.ResponseWriter) *timeoutWriter {
+	return &timeoutWriter{ResponseWriter: w, header: w.Header().Clone(), status: http.StatusOK}
+}
+
+// timeout 在响应尚未发出时写入 504，并设置 Content-Length 后立即 Flush，客户端无需等待处理函数返回
+func (w *timeoutWriter) timeout(body []byte) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.committed {
+		return false
+	}
+	w.timedOut = true
+	h := w.ResponseWriter.Header()
+	h.Set("Content-Type", "application/json; charset=utf-8")
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	w.ResponseWriter.WriteHeader(http.StatusGatewayTimeout)
+	_, _ = w.ResponseWriter.Write(body)
+	w.ResponseWriter.Flush()
+	return true
+}
+
+// commit 将缓冲的响应交给下层 ResponseWriter，调用方需持有锁或确保处理函数已返回
+func (w *timeoutWriter) commit() {
+	if w.committed || w.timedOut {
+		return
+	}
+	w.committed = true
+	dst := w.ResponseWriter.Header()
+	for k := range dst {
+		delete(dst, k)
+	}
+	for k, v := range w.header {
+		dst[k] = v
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.wroteHeader {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+	if w.buf.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.buf.Bytes())
+	}
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.committed {
+		return w.ResponseWriter.Header()
+	}
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	switch {
+	case w.timedOut:
+	case w.committed:
+		w.ResponseWriter.WriteHeader(code)
+	case code > 0 && !w.wroteHeader:
+		w.status = code
+	}
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	switch {
+	case w.timedOut:
+	case w.committed:
+		w.ResponseWriter.WriteHeaderNow()
+	default:
+		w.wroteHeader = true
+	}
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	switch {
+	case w.timedOut:
+		return 0, http.ErrHandlerTimeout
+	case w.committed:
+		return w.ResponseWriter.Write(data)
+	default:
+		w.wroteHeader = true
+		return w.buf.Write(data)
+	}
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush 发出已缓冲的响应，此后的写入直接交给下层 ResponseWriter
+func (w *timeoutWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return
+	}
+	w.commit()
+	w.ResponseWriter.Flush()
+}
+
+func (w *timeoutWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut || w.committed {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *timeoutWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut || w.committed {
+		return w.ResponseWriter.Size()
+	}
+	if !w.wroteHeader {
+		return -1
+	}
+	return w.buf.Len()
+}
+
+func (w *timeoutWriter) Written() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut || w.committed {
+		return w.ResponseWriter.Written()
+	}
+	return w.wroteHeader
+}
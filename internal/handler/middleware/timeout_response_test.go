@@ -0,0 +1,89 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+)
+
+func TestTimeoutWithResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	release := make(chan struct{})
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware())
+	r.Use(middleware.TimeoutWithResponse(middleware.TimeoutConfig{Default: 50 * time.Millisecond}))
+	r.GET("/slow", func(c *gin.Context) {
+		// ignores the cancellation, then responds and reports an error once released
+		<-release
+		c.JSON(http.StatusOK, gin.H{"late": true})
+		middleware.HandleError(c, middleware.ErrConflict)
+	})
+	r.GET("/fast", func(c *gin.Context) {
+		c.Header("X-Handler", "fast")
+		c.JSON(http.StatusCreated, gin.H{"ok": true})
+	})
+	r.GET("/error", func(c *gin.Context) {
+		middleware.HandleError(c, middleware.ErrNotFound)
+	})
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+	defer close(release)
+
+	t.Run("timeout", func(t *testing.T) {
+		// the response arrives while the handler is still blocked
+		resp, err := srv.Client().Get(srv.URL + "/slow")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+		assert.Equal(t, "application/json; charset=utf-8", resp.Header.Get("Content-Type"))
+		var body middleware.ErrorResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, middleware.ErrorResponse{Code: http.StatusGatewayTimeout, Message: "请求处理超时"}, body)
+		rest, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Empty(t, rest)
+	})
+
+	t.Run("in-time", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fast", nil))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "fast", w.Header().Get("X-Handler"))
+		assert.JSONEq(t, `{"ok":true}`, w.Body.String())
+	})
+
+	t.Run("error-after-handler", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/error", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var body middleware.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, http.StatusNotFound, body.Code)
+	})
+
+	t.Run("panic-reaches-recovery", func(t *testing.T) {
+		rr := gin.New()
+		rr.Use(middleware.ErrorHandler())
+		rr.Use(middleware.TimeoutWithResponse(middleware.TimeoutConfig{Default: time.Second}))
+		rr.GET("/panic", func(c *gin.Context) {
+			panic("boom")
+		})
+
+		w := httptest.NewRecorder()
+		rr.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
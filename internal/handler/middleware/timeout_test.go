@@ -0,0 +1,86 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+)
+
+func TestRequestTimeoutOverride(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := middleware.TimeoutConfig{
+		Default: 2 * time.Second,
+		Max:     30 * time.Second,
+		Secret:  "s3cret",
+	}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    time.Duration
+	}{
+		{
+			name: "honored",
+			headers: map[string]string{
+				middleware.InternalSecretHeader: "s3cret",
+				middleware.RequestTimeoutHeader: "10",
+			},
+			want: 10 * time.Second,
+		},
+		{
+			name: "clamped-to-ceiling",
+			headers: map[string]string{
+				middleware.InternalSecretHeader: "s3cret",
+				middleware.RequestTimeoutHeader: "600",
+			},
+			want: 30 * time.Second,
+		},
+		{
+			name: "ignored-without-secret",
+			headers: map[string]string{
+				middleware.RequestTimeoutHeader: "10",
+			},
+			want: 2 * time.Second,
+		},
+		{
+			name: "ignored-with-wrong-secret",
+			headers: map[string]string{
+				middleware.InternalSecretHeader: "guess",
+				middleware.RequestTimeoutHeader: "10",
+			},
+			want: 2 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var remaining time.Duration
+			r := gin.New()
+			r.Use(middleware.SetRequestContextWithTimeoutConfig(cfg))
+			r.GET("/test", func(c *gin.Context) {
+				deadline, ok := c.Request.Context().Deadline()
+				require.True(t, ok)
+				remaining = time.Until(deadline)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.InDelta(t, tt.want, remaining, float64(time.Second))
+		})
+	}
+}
@@ -8,6 +8,8 @@ import (
 	domain "github.com/bxcodec/go-clean-arch/domain"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // ArticleService is an autogenerated mock type for the ArticleService type
@@ -15,6 +17,90 @@ type ArticleService struct {
 	mock.Mock
 }
 
+// Clone provides a mock function with given fields: ctx, id
+func (_m *ArticleService) Clone(ctx context.Context, id int64) (domain.Article, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Clone")
+	}
+
+	var r0 domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (domain.Article, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) domain.Article); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Count provides a mock function with given fields: ctx, filter
+func (_m *ArticleService) Count(ctx context.Context, filter domain.ArticleFilter) (int64, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Count")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ArticleFilter) (int64, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ArticleFilter) int64); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ArticleFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountByAuthor provides a mock function with given fields: ctx, authorID
+func (_m *ArticleService) CountByAuthor(ctx context.Context, authorID int64) (int64, error) {
+	ret := _m.Called(ctx, authorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountByAuthor")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (int64, error)); ok {
+		return rf(ctx, authorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) int64); ok {
+		r0 = rf(ctx, authorID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, authorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ctx, id
 func (_m *ArticleService) Delete(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
@@ -33,36 +119,105 @@ func (_m *ArticleService) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *ArticleService) Fetch(ctx context.Context, cursor string, num int64) ([]domain.Article, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// Fetch provides a mock function with given fields: ctx, cursor, direction, num, filter
+func (_m *ArticleService) Fetch(ctx context.Context, cursor string, direction domain.PageDirection, num int64, filter domain.ArticleFilter) ([]domain.Article, domain.PageCursors, error) {
+	ret := _m.Called(ctx, cursor, direction, num, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Fetch")
 	}
 
+	var r0 []domain.Article
+	var r1 domain.PageCursors
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.PageDirection, int64, domain.ArticleFilter) ([]domain.Article, domain.PageCursors, error)); ok {
+		return rf(ctx, cursor, direction, num, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.PageDirection, int64, domain.ArticleFilter) []domain.Article); ok {
+		r0 = rf(ctx, cursor, direction, num, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.PageDirection, int64, domain.ArticleFilter) domain.PageCursors); ok {
+		r1 = rf(ctx, cursor, direction, num, filter)
+	} else {
+		r1 = ret.Get(1).(domain.PageCursors)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, domain.PageDirection, int64, domain.ArticleFilter) error); ok {
+		r2 = rf(ctx, cursor, direction, num, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchAll provides a mock function with given fields: ctx
+func (_m *ArticleService) FetchAll(ctx context.Context) (<-chan domain.Article, <-chan error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchAll")
+	}
+
+	var r0 <-chan domain.Article
+	var r1 <-chan error
+	if rf, ok := ret.Get(0).(func(context.Context) (<-chan domain.Article, <-chan error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) <-chan domain.Article); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) <-chan error); ok {
+		r1 = rf(ctx)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(<-chan error)
+		}
+	}
+
+	return r0, r1
+}
+
+// FetchChanges provides a mock function with given fields: ctx, since, cursor, num
+func (_m *ArticleService) FetchChanges(ctx context.Context, since time.Time, cursor string, num int64) ([]domain.Article, string, error) {
+	ret := _m.Called(ctx, since, cursor, num)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchChanges")
+	}
+
 	var r0 []domain.Article
 	var r1 string
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) ([]domain.Article, string, error)); ok {
-		return rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string, int64) ([]domain.Article, string, error)); ok {
+		return rf(ctx, since, cursor, num)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []domain.Article); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string, int64) []domain.Article); ok {
+		r0 = rf(ctx, since, cursor, num)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Article)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
-		r1 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, string, int64) string); ok {
+		r1 = rf(ctx, since, cursor, num)
 	} else {
 		r1 = ret.Get(1).(string)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
-		r2 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(2).(func(context.Context, time.Time, string, int64) error); ok {
+		r2 = rf(ctx, since, cursor, num)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -70,6 +225,66 @@ func (_m *ArticleService) Fetch(ctx context.Context, cursor string, num int64) (
 	return r0, r1, r2
 }
 
+// FetchRecent provides a mock function with given fields: ctx, limit
+func (_m *ArticleService) FetchRecent(ctx context.Context, limit int64) ([]domain.Article, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchRecent")
+	}
+
+	var r0 []domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]domain.Article, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []domain.Article); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByTitle provides a mock function with given fields: ctx, title
+func (_m *ArticleService) FindByTitle(ctx context.Context, title string) ([]domain.Article, error) {
+	ret := _m.Called(ctx, title)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByTitle")
+	}
+
+	var r0 []domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Article, error)); ok {
+		return rf(ctx, title)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Article); ok {
+		r0 = rf(ctx, title)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, title)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByID provides a mock function with given fields: ctx, id
 func (_m *ArticleService) GetByID(ctx context.Context, id int64) (domain.Article, error) {
 	ret := _m.Called(ctx, id)
@@ -98,6 +313,101 @@ func (_m *ArticleService) GetByID(ctx context.Context, id int64) (domain.Article
 	return r0, r1
 }
 
+// GetByIDIncludingDeleted provides a mock function with given fields: ctx, id
+func (_m *ArticleService) GetByIDIncludingDeleted(ctx context.Context, id int64) (domain.Article, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByIDIncludingDeleted")
+	}
+
+	var r0 domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (domain.Article, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) domain.Article); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByIDs provides a mock function with given fields: ctx, ids
+func (_m *ArticleService) GetByIDs(ctx context.Context, ids []int64) ([]domain.Article, []int64, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByIDs")
+	}
+
+	var r0 []domain.Article
+	var r1 []int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) ([]domain.Article, []int64, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []domain.Article); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) []int64); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, []int64) error); ok {
+		r2 = rf(ctx, ids)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetBySlug provides a mock function with given fields: ctx, slug
+func (_m *ArticleService) GetBySlug(ctx context.Context, slug string) (domain.Article, error) {
+	ret := _m.Called(ctx, slug)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBySlug")
+	}
+
+	var r0 domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Article, error)); ok {
+		return rf(ctx, slug)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Article); ok {
+		r0 = rf(ctx, slug)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByTitle provides a mock function with given fields: ctx, title
 func (_m *ArticleService) GetByTitle(ctx context.Context, title string) (domain.Article, error) {
 	ret := _m.Called(ctx, title)
@@ -126,6 +436,93 @@ func (_m *ArticleService) GetByTitle(ctx context.Context, title string) (domain.
 	return r0, r1
 }
 
+// GetNeighbors provides a mock function with given fields: ctx, id
+func (_m *ArticleService) GetNeighbors(ctx context.Context, id int64) (*domain.Article, *domain.Article, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNeighbors")
+	}
+
+	var r0 *domain.Article
+	var r1 *domain.Article
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*domain.Article, *domain.Article, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.Article); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) *domain.Article); ok {
+		r1 = rf(ctx, id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64) error); ok {
+		r2 = rf(ctx, id)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// IncrementViewCount provides a mock function with given fields: ctx, id
+func (_m *ArticleService) IncrementViewCount(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrementViewCount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListAuthorsWithCounts provides a mock function with given fields: ctx
+func (_m *ArticleService) ListAuthorsWithCounts(ctx context.Context) ([]domain.AuthorStat, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAuthorsWithCounts")
+	}
+
+	var r0 []domain.AuthorStat
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.AuthorStat, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.AuthorStat); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AuthorStat)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Store provides a mock function with given fields: _a0, _a1
 func (_m *ArticleService) Store(_a0 context.Context, _a1 *domain.Article) error {
 	ret := _m.Called(_a0, _a1)
@@ -144,6 +541,24 @@ func (_m *ArticleService) Store(_a0 context.Context, _a1 *domain.Article) error
 	return r0
 }
 
+// StoreBatch provides a mock function with given fields: ctx, articles
+func (_m *ArticleService) StoreBatch(ctx context.Context, articles []*domain.Article) error {
+	ret := _m.Called(ctx, articles)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreBatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*domain.Article) error); ok {
+		r0 = rf(ctx, articles)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, ar
 func (_m *ArticleService) Update(ctx context.Context, ar *domain.Article) error {
 	ret := _m.Called(ctx, ar)
@@ -162,6 +577,62 @@ func (_m *ArticleService) Update(ctx context.Context, ar *domain.Article) error
 	return r0
 }
 
+// UpdateStatus provides a mock function with given fields: ctx, id, status
+func (_m *ArticleService) UpdateStatus(ctx context.Context, id int64, status domain.ArticleStatus) (domain.Article, error) {
+	ret := _m.Called(ctx, id, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStatus")
+	}
+
+	var r0 domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.ArticleStatus) (domain.Article, error)); ok {
+		return rf(ctx, id, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.ArticleStatus) domain.Article); ok {
+		r0 = rf(ctx, id, status)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.ArticleStatus) error); ok {
+		r1 = rf(ctx, id, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Upsert provides a mock function with given fields: ctx, ar
+func (_m *ArticleService) Upsert(ctx context.Context, ar *domain.Article) (bool, error) {
+	ret := _m.Called(ctx, ar)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Upsert")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Article) (bool, error)); ok {
+		return rf(ctx, ar)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Article) bool); ok {
+		r0 = rf(ctx, ar)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Article) error); ok {
+		r1 = rf(ctx, ar)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewArticleService creates a new instance of ArticleService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewArticleService(t interface {
@@ -0,0 +1,331 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// envelopeKey marks the requests whose success responses are wrapped in SuccessResponse
+const envelopeKey = "handler.envelope"
+
+// SuccessResponse is the envelope of every 2xx JSON response when Envelope is enabled
+type SuccessResponse struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data"`
+}
+
+// Envelope wraps the 2xx JSON responses of the handlers in SuccessResponse, for gateways expecting one
+// uniform shape. Errors keep the middleware.ErrorResponse shape and headers such as X-Cursor are unchanged.
+func Envelope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(envelopeKey, true)
+		c.Next()
+	}
+}
+
+// respondJSON writes body as JSON, indented in debug mode so responses are readable in a browser
+// and compact otherwise
+func respondJSON(c *gin.Context, status int, body interface{}) {
+	if c.GetBool(envelopeKey) && status >= 200 && status < 300 {
+		body = SuccessResponse{Success: true, Data: body}
+	}
+	if gin.IsDebugging() {
+		c.IndentedJSON(status, body)
+		return
+	}
+	c.JSON(status, body)
+}
+
+// headOnly serves a HEAD request with the handler of the GET route, keeping its status and headers.
+// The writer is not restored, so an error body written by a later middleware is dropped as well.
+func headOnly(h gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer = bodylessWriter{c.Writer}
+		h(c)
+	}
+}
+
+// bodylessWriter discards the body while still writing the status and headers on the first write
+type bodylessWriter struct {
+	gin.ResponseWriter
+}
+
+func (w bodylessWriter) Write(b []byte) (int, error) {
+	w.WriteHeaderNow()
+	return len(b), nil
+}
+
+func (w bodylessWriter) WriteString(s string) (int, error) {
+	w.WriteHeaderNow()
+	return len(s), nil
+}
+
+// respondCacheable writes a single article that clients may cache for the max-age configured for endpoint.
+// The weak ETag changes with updated_at, so a revalidation with a matching If-None-Match gets a bodiless 304.
+// A non-zero lastModified is sent as Last-Modified and checked against If-Modified-Since, which is ignored
+// when the request carries If-None-Match.
+func (a *ArticleHandler) respondCacheable(c *gin.Context, endpoint string, res ArticleResponse, lastModified time.Time) {
+	var etag string
+	if maxAge := a.maxAge[endpoint]; maxAge > 0 {
+		etag = fmt.Sprintf(`W/"%d-%d"`, res.ID, res.UpdatedAt.UnixNano())
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+		c.Header("ETag", etag)
+	}
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	notModified := false
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		notModified = etag != "" && etagMatches(ifNoneMatch, etag)
+	} else if !lastModified.IsZero() {
+		if since, ok := parseHTTPDate(c.GetHeader("If-Modified-Since")); ok {
+			// HTTP 日期只精确到秒
+			notModified = !lastModified.Truncate(time.Second).After(since)
+		}
+	}
+	if notModified {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	respondJSON(c, http.StatusOK, res)
+}
+
+// httpDateFormats are the fallbacks of parseHTTPDate for clients that send a numeric zone instead of GMT
+var httpDateFormats = []string{time.RFC1123Z, time.RFC3339}
+
+// parseHTTPDate parses a conditional request date. Besides the formats of http.ParseTime it accepts dates
+// with a numeric zone; an empty or malformed value reports false, as such a header is to be ignored.
+func parseHTTPDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return t, true
+	}
+	for _, layout := range httpDateFormats {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// etagMatches applies the weak comparison of If-None-Match, which may list several tags or be "*"
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// wordsPerMinute is the reading speed used to estimate ReadingTimeSeconds
+const wordsPerMinute = 200
+
+// ArticleResponse represent the article as returned to clients, with read-only fields computed from the content
+type ArticleResponse struct {
+	domain.Article
+	WordCount          int `json:"word_count"`
+	ReadingTimeSeconds int `json:"reading_time_seconds"`
+	// Excerpt is set by the list endpoints, which leave out the content unless ?fields= selects it
+	Excerpt string `json:"excerpt,omitempty"`
+	// stringID encodes the id as a JSON string, see WithStringIDs
+	stringID bool
+	// timeFormat encodes the timestamps as one of the TimeFormat constants, empty is TimeFormatRFC3339
+	timeFormat string
+	// fields restricts the encoded fields to the ones requested with ?fields=, nil encodes every field
+	fields []string
+	// excerptOnly drops the content when no ?fields= is given, see excerptResponses
+	excerptOnly bool
+}
+
+// articleFields are the JSON fields of ArticleResponse that ?fields= may select
+var articleFields = []string{
+	"id", "title", "slug", "status", "content", "author", "updated_at", "created_at", "deleted_at",
+	"metadata", "view_count", "tags", "word_count", "reading_time_seconds", "excerpt",
+}
+
+// parseFields reads the comma separated ?fields= query param, a missing param selects every field
+func parseFields(c *gin.Context) ([]string, error) {
+	value, ok := c.GetQuery("fields")
+	if !ok {
+		return nil, nil
+	}
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if !slices.Contains(articleFields, field) {
+			return nil, fmt.Errorf("unknown field %q", field)
+		}
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// withFields restricts every response of list to fields
+func withFields(list []ArticleResponse, fields []string) []ArticleResponse {
+	for i := range list {
+		list[i].fields = fields
+	}
+	return list
+}
+
+// plainArticleResponse has the fields of ArticleResponse without its methods, so marshaling it does not recurse
+type plainArticleResponse ArticleResponse
+
+// MarshalJSON encodes the id as a string when stringID is set, JavaScript clients lose precision
+// on integers above 2^53 such as snowflake ids. The timestamps are encoded in timeFormat.
+// When fields is set only those fields are kept.
+func (r ArticleResponse) MarshalJSON() ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if r.stringID {
+		data, err = json.Marshal(struct {
+			plainArticleResponse
+			ID int64 `json:"id,string"`
+		}{plainArticleResponse(r), r.ID})
+	} else {
+		data, err = json.Marshal(plainArticleResponse(r))
+	}
+	if err != nil || (r.fields == nil && !r.excerptOnly && !epochTimes(r.timeFormat)) {
+		return data, err
+	}
+
+	// project the encoded object, so the json tags and the string id apply to the selected fields too
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	if epochTimes(r.timeFormat) {
+		if err := encodeTimes(all, r.Article, r.timeFormat); err != nil {
+			return nil, err
+		}
+	}
+	if r.fields == nil {
+		if r.excerptOnly {
+			delete(all, "content")
+		}
+		return json.Marshal(all)
+	}
+	projected := make(map[string]json.RawMessage, len(r.fields))
+	for _, field := range r.fields {
+		if value, ok := all[field]; ok {
+			projected[field] = value
+		}
+	}
+	return json.Marshal(projected)
+}
+
+// UnmarshalJSON decodes both the numeric and the string form of the id. It is needed because the
+// UnmarshalJSON promoted from domain.Article would otherwise skip the computed fields.
+func (r *ArticleResponse) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &r.Article); err != nil {
+		return err
+	}
+	var computed struct {
+		WordCount          int    `json:"word_count"`
+		ReadingTimeSeconds int    `json:"reading_time_seconds"`
+		Excerpt            string `json:"excerpt"`
+	}
+	if err := json.Unmarshal(data, &computed); err != nil {
+		return err
+	}
+	r.WordCount = computed.WordCount
+	r.ReadingTimeSeconds = computed.ReadingTimeSeconds
+	r.Excerpt = computed.Excerpt
+	return nil
+}
+
+// NewArticleResponse computes the read-only fields of ar
+func NewArticleResponse(ar domain.Article) ArticleResponse {
+	words := countWords(ar.Content)
+	return ArticleResponse{
+		Article:            ar,
+		WordCount:          words,
+		ReadingTimeSeconds: (words*60 + wordsPerMinute - 1) / wordsPerMinute,
+	}
+}
+
+// articleResponse is NewArticleResponse honouring WithStringIDs and WithTimeFormat
+func (a *ArticleHandler) articleResponse(ar domain.Article) ArticleResponse {
+	res := NewArticleResponse(ar)
+	res.stringID = a.stringIDs
+	res.timeFormat = a.timeFormat
+	return res
+}
+
+// articleResponses always returns a non-nil slice so an empty page encodes as [] rather than null
+func (a *ArticleHandler) articleResponses(list []domain.Article) []ArticleResponse {
+	res := make([]ArticleResponse, 0, len(list))
+	for _, ar := range list {
+		res = append(res, a.articleResponse(ar))
+	}
+	return res
+}
+
+// excerptResponses is articleResponses for the list endpoints, which return an excerpt instead of the content
+func (a *ArticleHandler) excerptResponses(list []domain.Article) []ArticleResponse {
+	res := a.articleResponses(list)
+	for i := range res {
+		res[i].Excerpt = excerpt(res[i].Content, a.excerptLen)
+		res[i].excerptOnly = true
+	}
+	return res
+}
+
+// excerpt returns the first n characters of content with whitespace collapsed. A longer content is cut
+// at the last word boundary within the n characters, followed by an ellipsis. Like in countWords each Han
+// character is a word of its own; a single word longer than n is cut at n characters.
+func excerpt(content string, n int) string {
+	runes := []rune(strings.Join(strings.Fields(content), " "))
+	if len(runes) <= n {
+		return string(runes)
+	}
+	cut := n
+	for i := n; i > 0; i-- {
+		if unicode.IsSpace(runes[i]) || unicode.Is(unicode.Han, runes[i]) || unicode.Is(unicode.Han, runes[i-1]) {
+			cut = i
+			break
+		}
+	}
+	return strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace) + "…"
+}
+
+// countWords counts runs of letters or digits as words. Han characters are not space separated,
+// so each of them counts as one word.
+func countWords(content string) int {
+	count := 0
+	inWord := false
+	for _, r := range content {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			count++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'':
+			if !inWord {
+				count++
+			}
+			inWord = true
+		default:
+			inWord = false
+		}
+	}
+	return count
+}
@@ -0,0 +1,649 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/handler"
+	"github.com/bxcodec/go-clean-arch/internal/handler/mocks"
+)
+
+func TestNewArticleResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wordCount   int
+		readingTime int
+	}{
+		{name: "empty", content: "", wordCount: 0, readingTime: 0},
+		{name: "single-word", content: "Hello", wordCount: 1, readingTime: 1},
+		{name: "punctuation", content: "Hello, world! It's a clean-architecture demo.", wordCount: 7, readingTime: 3},
+		{
+			name:        "multi-paragraph",
+			content:     "First paragraph has five words.\n\nSecond one\thas four.\r\n\r\nThird.",
+			wordCount:   10,
+			readingTime: 3,
+		},
+		{name: "han", content: "整洁架构 in Go", wordCount: 6, readingTime: 2},
+		{name: "one-minute", content: strings.Repeat("word ", 200), wordCount: 200, readingTime: 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := handler.NewArticleResponse(domain.Article{Content: tt.content})
+			assert.Equal(t, tt.wordCount, res.WordCount)
+			assert.Equal(t, tt.readingTime, res.ReadingTimeSeconds)
+		})
+	}
+}
+
+func TestGetByIDComputedFields(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1, Title: "Title", Content: "three little words"}, nil)
+
+	r := setupRouter()
+	handler.NewArticleHandler(r, mockUCase)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/1", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "Title", body["title"])
+	assert.EqualValues(t, 3, body["word_count"])
+	assert.EqualValues(t, 1, body["reading_time_seconds"])
+	mockUCase.AssertExpectations(t)
+}
+
+func TestRespondJSONIndentation(t *testing.T) {
+	fetch := func(t *testing.T, mode string) string {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1, Title: "Hello"}, nil).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+		gin.SetMode(mode)
+		defer gin.SetMode(gin.TestMode)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/1", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+
+	t.Run("compact-in-release", func(t *testing.T) {
+		body := fetch(t, gin.ReleaseMode)
+		assert.NotContains(t, body, "\n")
+		assert.True(t, strings.HasPrefix(body, `{"id":1,`))
+	})
+
+	t.Run("indented-in-debug", func(t *testing.T) {
+		body := fetch(t, gin.DebugMode)
+		assert.True(t, strings.HasPrefix(body, "{\n    \"id\": 1,"), body)
+	})
+}
+
+func TestEnvelope(t *testing.T) {
+	type envelope struct {
+		Success bool                    `json:"success"`
+		Data    handler.ArticleResponse `json:"data"`
+	}
+
+	t.Run("get-by-id", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1, Title: "Hello"}, nil).Once()
+
+		r := setupRouter()
+		r.Use(handler.Envelope())
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/1", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var res envelope
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.True(t, res.Success)
+		assert.Equal(t, int64(1), res.Data.ID)
+		assert.Equal(t, "Hello", res.Data.Title)
+	})
+
+	t.Run("store", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Article).ID = 9
+		}).Return(nil).Once()
+
+		r := setupRouter()
+		r.Use(handler.Envelope())
+		handler.NewArticleHandler(r, mockUCase)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/articles", strings.NewReader(`{"title":"Hello","content":"Content"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var res envelope
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.True(t, res.Success)
+		assert.Equal(t, int64(9), res.Data.ID)
+	})
+
+	t.Run("fetch-keeps-cursor", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, int64(10), published).Return([]domain.Article{{ID: 1}}, domain.PageCursors{Next: "next"}, nil).Once()
+
+		r := setupRouter()
+		r.Use(handler.Envelope())
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "next", w.Header().Get("X-Cursor"))
+		var res struct {
+			Success bool                      `json:"success"`
+			Data    []handler.ArticleResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.True(t, res.Success)
+		assert.Len(t, res.Data, 1)
+	})
+
+	t.Run("errors-not-wrapped", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{}, domain.ErrNotFound).Once()
+
+		r := setupRouter()
+		r.Use(handler.Envelope())
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/1", nil))
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		assert.NotContains(t, w.Body.String(), `"success"`)
+		assert.Contains(t, w.Body.String(), `"code":404`)
+	})
+}
+
+func TestStringIDs(t *testing.T) {
+	const id = int64(1234567890123456789)
+
+	t.Run("serialized-as-string", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, id).Return(domain.Article{ID: id, Title: "Title", Content: "Content"}, nil)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase, handler.WithStringIDs())
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/1234567890123456789", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var raw map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &raw))
+		assert.Equal(t, "1234567890123456789", raw["id"])
+		assert.Equal(t, "Title", raw["title"])
+		assert.EqualValues(t, 1, raw["word_count"])
+
+		var res handler.ArticleResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, id, res.ID)
+		assert.Equal(t, 1, res.WordCount)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("numeric-by-default", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1, Title: "Title", Content: "Content"}, nil)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/1", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":1,`)
+	})
+
+	for name, body := range map[string]string{
+		"string-id-on-write":  `{"id":"1234567890123456789","title":"Title","content":"Content"}`,
+		"numeric-id-on-write": `{"id":1234567890123456789,"title":"Title","content":"Content"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On("Store", mock.Anything, mock.MatchedBy(func(a *domain.Article) bool {
+				return a.ID == id && a.Title == "Title"
+			})).Return(nil)
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase, handler.WithStringIDs())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/articles", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusCreated, w.Code)
+			assert.Contains(t, w.Body.String(), `"id":"1234567890123456789"`)
+			mockUCase.AssertExpectations(t)
+		})
+	}
+
+	t.Run("invalid-string-id", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/articles", strings.NewReader(`{"id":"abc","title":"Title","content":"Content"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUCase.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSparseFieldsets(t *testing.T) {
+	ar := domain.Article{ID: 1, Title: "Title", Content: "three little words", Status: domain.StatusPublished}
+
+	t.Run("get-by-id", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(1)).Return(ar, nil)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/1?fields=id,title,word_count", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var res map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, map[string]interface{}{"id": float64(1), "title": "Title", "word_count": float64(3)}, res)
+	})
+
+	t.Run("fetch", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, int64(10), published).Return([]domain.Article{ar, ar}, domain.PageCursors{}, nil)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles?fields=title", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var res []map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, []map[string]interface{}{{"title": "Title"}, {"title": "Title"}}, res)
+	})
+
+	t.Run("string-id", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(1)).Return(ar, nil)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase, handler.WithStringIDs())
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/1?fields=id", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id":"1"}`, w.Body.String())
+	})
+
+	for name, target := range map[string]string{
+		"unknown-on-get-by-id": "/api/v1/articles/1?fields=id,password",
+		"unknown-on-fetch":     "/api/v1/articles?fields=titel",
+		"empty":                "/api/v1/articles?fields=",
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "unknown field")
+			mockUCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCacheControl(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	ar := domain.Article{ID: 1, Title: "Title", Content: "Content", UpdatedAt: updatedAt}
+	maxAge := handler.WithCacheMaxAge(map[string]time.Duration{handler.EndpointGetByID: time.Minute})
+
+	t.Run("get-by-id", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(1)).Return(ar, nil)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase, maxAge)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/1", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+		etag := w.Header().Get("ETag")
+		assert.NotEmpty(t, etag)
+
+		// revalidation with the ETag
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/1", nil)
+		req.Header.Set("If-None-Match", etag)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+
+		// a newer version of the article changes the ETag
+		mockUCase.On("GetByID", mock.Anything, int64(2)).Return(domain.Article{ID: 2, UpdatedAt: updatedAt.Add(time.Second)}, nil)
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/articles/2", nil)
+		req.Header.Set("If-None-Match", etag)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("not-configured", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetBySlug", mock.Anything, "title").Return(ar, nil)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase, maxAge)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/slug/title", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+		assert.Empty(t, w.Header().Get("ETag"))
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{}, domain.ErrNotFound)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase, maxAge)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/1", nil))
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, int64(10), published).Return([]domain.Article{ar}, domain.PageCursors{}, nil)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase, maxAge)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	})
+
+	t.Run("store", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase, maxAge)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/articles", strings.NewReader(`{"title":"Title","content":"Content"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+		assert.NotContains(t, w.Header().Get("Cache-Control"), "max-age")
+		assert.Empty(t, w.Header().Get("ETag"))
+	})
+}
+
+func TestLastModified(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC)
+	ar := domain.Article{ID: 1, Title: "Title", Content: "Content", UpdatedAt: updatedAt}
+
+	tests := []struct {
+		name            string
+		ifModifiedSince string
+		ifNoneMatch     string
+		code            int
+	}{
+		{name: "unconditional", code: http.StatusOK},
+		{name: "same-second", ifModifiedSince: "Tue, 02 Jan 2024 03:04:05 GMT", code: http.StatusNotModified},
+		{name: "later", ifModifiedSince: "Wed, 03 Jan 2024 00:00:00 GMT", code: http.StatusNotModified},
+		{name: "earlier", ifModifiedSince: "Tue, 02 Jan 2024 03:04:04 GMT", code: http.StatusOK},
+		{name: "numeric-zone", ifModifiedSince: "Tue, 02 Jan 2024 11:04:05 +0800", code: http.StatusNotModified},
+		{name: "rfc850", ifModifiedSince: "Tuesday, 02-Jan-24 03:04:05 GMT", code: http.StatusNotModified},
+		{name: "malformed-ignored", ifModifiedSince: "yesterday", code: http.StatusOK},
+		{name: "if-none-match-wins", ifModifiedSince: "Wed, 03 Jan 2024 00:00:00 GMT", ifNoneMatch: `W/"other"`, code: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On("GetByID", mock.Anything, int64(1)).Return(ar, nil).Once()
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/1", nil)
+			if tt.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", tt.ifModifiedSince)
+			}
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tt.code, w.Code)
+			assert.Equal(t, "Tue, 02 Jan 2024 03:04:05 GMT", w.Header().Get("Last-Modified"))
+			if tt.code == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+			} else {
+				assert.Contains(t, w.Body.String(), `"title":"Title"`)
+			}
+			mockUCase.AssertExpectations(t)
+		})
+	}
+
+	t.Run("not-on-slug", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetBySlug", mock.Anything, "title").Return(ar, nil).Once()
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/slug/title", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Last-Modified"))
+	})
+}
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		excerpt string
+	}{
+		{name: "short", content: "go code", excerpt: "go code"},
+		{name: "exact-length", content: "clean code", excerpt: "clean code"},
+		{name: "collapses-whitespace", content: "clean\n\n  code", excerpt: "clean code"},
+		{name: "word-boundary", content: "clean architecture in go", excerpt: "clean…"},
+		{name: "ends-on-word", content: "clean code in go", excerpt: "clean code…"},
+		{name: "long-word", content: "supercalifragilistic", excerpt: "supercalif…"},
+		{name: "han", content: "整洁架构的示例项目代码仓库", excerpt: "整洁架构的示例项目代…"},
+		{name: "han-after-latin", content: "Go 语言整洁架构示例项目", excerpt: "Go 语言整洁架构示…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, int64(10), published).
+				Return([]domain.Article{{ID: 1, Title: "Title", Content: tt.content}}, domain.PageCursors{}, nil)
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase, handler.WithExcerptLength(10))
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles", nil))
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var res []map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			require.Len(t, res, 1)
+			assert.Equal(t, tt.excerpt, res[0]["excerpt"])
+			assert.LessOrEqual(t, len([]rune(tt.excerpt)), 11)
+			assert.NotContains(t, res[0], "content")
+		})
+	}
+
+	t.Run("content-on-request", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, int64(10), published).
+			Return([]domain.Article{{ID: 1, Content: "clean architecture in go"}}, domain.PageCursors{}, nil)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase, handler.WithExcerptLength(10))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles?fields=content,excerpt", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"content":"clean architecture in go","excerpt":"clean…"}]`, w.Body.String())
+	})
+
+	t.Run("get-by-id-full-content", func(t *testing.T) {
+		content := strings.Repeat("clean architecture ", 50)
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1, Content: content}, nil)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase, handler.WithExcerptLength(10))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/1", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var res map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, content, res["content"])
+		assert.NotContains(t, res, "excerpt")
+	})
+}
+
+func TestTimeFormat(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC)
+	deleted := created.Add(time.Hour)
+
+	tests := []struct {
+		format  string
+		created string
+		deleted string
+		// roundTrip is created as decoded back from its encoding, the epoch formats truncate it
+		roundTrip time.Time
+	}{
+		{format: handler.TimeFormatRFC3339, created: `"2024-01-02T03:04:05.123456789Z"`, deleted: `"2024-01-02T04:04:05.123456789Z"`, roundTrip: created},
+		{format: handler.TimeFormatUnix, created: `1704164645`, deleted: `1704168245`, roundTrip: created.Truncate(time.Second)},
+		{format: handler.TimeFormatUnixMilli, created: `1704164645123`, deleted: `1704168245123`, roundTrip: created.Truncate(time.Millisecond)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{
+				ID: 1, Title: "Title", Content: "Content", CreatedAt: created, UpdatedAt: created, DeletedAt: &deleted,
+			}, nil)
+
+			r := setupRouter()
+			handler.NewArticleHandler(r, mockUCase, handler.WithTimeFormat(tt.format))
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/1", nil))
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var raw map[string]json.RawMessage
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &raw))
+			assert.JSONEq(t, tt.created, string(raw["created_at"]))
+			assert.JSONEq(t, tt.created, string(raw["updated_at"]))
+			assert.JSONEq(t, tt.deleted, string(raw["deleted_at"]))
+			assert.Equal(t, "Content", strings.Trim(string(raw["content"]), `"`))
+
+			// the timestamps received are accepted back on write
+			mockUCase.On("Store", mock.Anything, mock.MatchedBy(func(a *domain.Article) bool {
+				return a.CreatedAt.Equal(tt.roundTrip) && a.UpdatedAt.Equal(tt.roundTrip)
+			})).Return(nil).Once()
+			body := `{"title":"Title","content":"Content","created_at":` + string(raw["created_at"]) +
+				`,"updated_at":` + string(raw["updated_at"]) + `}`
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/articles", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w = httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusCreated, w.Code)
+			mockUCase.AssertExpectations(t)
+		})
+	}
+
+	t.Run("list", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", domain.PageNext, int64(10), mock.Anything).
+			Return([]domain.Article{{ID: 1, Title: "Title", Content: "Content", CreatedAt: created, UpdatedAt: created}}, domain.PageCursors{}, nil)
+
+		r := setupRouter()
+		handler.NewArticleHandler(r, mockUCase, handler.WithTimeFormat(handler.TimeFormatUnix))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"created_at":1704164645`)
+		assert.NotContains(t, w.Body.String(), `"content"`)
+	})
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+)
+
+//go:embed schema/article.json
+var articleSchemaJSON []byte
+
+// SchemaError is returned when a request body does not conform to the JSON Schema,
+// Errors uses the same field-error shape as struct tag validation with JSON pointers as fields
+type SchemaError struct {
+	Errors []FieldError
+}
+
+func (e *SchemaError) Error() string {
+	parts := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		parts = append(parts, fe.Field+": "+fe.Rule)
+	}
+	return "schema validation failed: " + strings.Join(parts, ", ")
+}
+
+// mustCompileSchema compiles an embedded schema, a broken schema is a programming error
+func mustCompileSchema(name string, raw []byte) *jsonschema.Schema {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		panic(err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource(name, doc); err != nil {
+		panic(err)
+	}
+	return c.MustCompile(name)
+}
+
+// validateSchema checks body against sch, a malformed body is reported as a single error on the root
+func validateSchema(sch *jsonschema.Schema, body []byte) error {
+	inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return &SchemaError{Errors: []FieldError{{Field: "", Rule: "json"}}}
+	}
+	err = sch.Validate(inst)
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return err
+	}
+	res := &SchemaError{}
+	collectSchemaErrors(verr, &res.Errors)
+	return res
+}
+
+// collectSchemaErrors flattens the error tree into its leaves
+func collectSchemaErrors(verr *jsonschema.ValidationError, out *[]FieldError) {
+	if len(verr.Causes) > 0 {
+		for _, cause := range verr.Causes {
+			collectSchemaErrors(cause, out)
+		}
+		return
+	}
+
+	pointer := jsonPointer(verr.InstanceLocation)
+	keywordPath := verr.ErrorKind.KeywordPath()
+	rule := ""
+	if len(keywordPath) > 0 {
+		rule = keywordPath[len(keywordPath)-1]
+	}
+	// required is reported on the parent object, point at the missing properties instead
+	if required, ok := verr.ErrorKind.(*kind.Required); ok {
+		for _, name := range required.Missing {
+			*out = append(*out, FieldError{Field: pointer + "/" + escapePointerToken(name), Rule: rule})
+		}
+		return
+	}
+	*out = append(*out, FieldError{Field: pointer, Rule: rule})
+}
+
+func jsonPointer(tokens []string) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		sb.WriteByte('/')
+		sb.WriteString(escapePointerToken(token))
+	}
+	return sb.String()
+}
+
+func escapePointerToken(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
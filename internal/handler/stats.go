@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+)
+
+// ArticleCounter is the part of the ArticleService the stats endpoint needs
+type ArticleCounter interface {
+	Count(ctx context.Context, filter domain.ArticleFilter) (int64, error)
+}
+
+// AdminOption configures optional behaviour of the AdminHandler
+type AdminOption func(*AdminHandler)
+
+// WithArticleStats enables the article counts of GET /admin/stats
+func WithArticleStats(counter ArticleCounter) AdminOption {
+	return func(h *AdminHandler) {
+		h.counter = counter
+	}
+}
+
+// WithDBStats enables the connection pool section of GET /admin/stats, e.g. with (*sql.DB).Stats
+func WithDBStats(stats func() sql.DBStats) AdminOption {
+	return func(h *AdminHandler) {
+		h.dbStats = stats
+	}
+}
+
+// StatsResponse represent the response body of GetStats
+type StatsResponse struct {
+	Articles      *ArticleStats `json:"articles,omitempty"`
+	DB            *DBPoolStats  `json:"db,omitempty"`
+	UptimeSeconds int64         `json:"uptime_seconds"`
+}
+
+// ArticleStats holds the total article count and the count of every status
+type ArticleStats struct {
+	Total    int64                          `json:"total"`
+	ByStatus map[domain.ArticleStatus]int64 `json:"by_status"`
+}
+
+// DBPoolStats is the subset of sql.DBStats that is useful at a glance
+type DBPoolStats struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+}
+
+// GetStats returns a human-readable snapshot of the article counts, the DB pool and the uptime
+func (h *AdminHandler) GetStats(c *gin.Context) {
+	res := StatsResponse{UptimeSeconds: int64(time.Since(h.startedAt).Seconds())}
+
+	if h.counter != nil {
+		stats, err := h.articleStats(c.Request.Context())
+		if err != nil {
+			middleware.HandleError(c, middleware.NewAppErrorWithErr(http.StatusInternalServerError, "获取文章统计失败", err))
+			return
+		}
+		res.Articles = stats
+	}
+
+	if h.dbStats != nil {
+		s := h.dbStats()
+		res.DB = &DBPoolStats{
+			MaxOpenConnections: s.MaxOpenConnections,
+			OpenConnections:    s.OpenConnections,
+			InUse:              s.InUse,
+			Idle:               s.Idle,
+			WaitCount:          s.WaitCount,
+			WaitDurationMs:     s.WaitDuration.Milliseconds(),
+		}
+	}
+
+	respondJSON(c, http.StatusOK, res)
+}
+
+func (h *AdminHandler) articleStats(ctx context.Context) (*ArticleStats, error) {
+	total, err := h.counter.Count(ctx, domain.ArticleFilter{})
+	if err != nil {
+		return nil, err
+	}
+	stats := &ArticleStats{Total: total, ByStatus: map[domain.ArticleStatus]int64{}}
+	for _, status := range []domain.ArticleStatus{domain.StatusDraft, domain.StatusPublished, domain.StatusArchived} {
+		n, err := h.counter.Count(ctx, domain.ArticleFilter{Status: status})
+		if err != nil {
+			return nil, err
+		}
+		stats.ByStatus[status] = n
+	}
+	return stats, nil
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// Timestamp encodings of the article responses, see WithTimeFormat
+const (
+	// TimeFormatRFC3339 is the default, an RFC 3339 string with nanoseconds as encoding/json writes time.Time
+	TimeFormatRFC3339 = "rfc3339"
+	// TimeFormatUnix is the number of seconds since the Unix epoch
+	TimeFormatUnix = "unix"
+	// TimeFormatUnixMilli is the number of milliseconds since the Unix epoch
+	TimeFormatUnixMilli = "unixmilli"
+)
+
+// timeFields are the timestamp fields of ArticleResponse
+var timeFields = []string{"updated_at", "created_at", "deleted_at"}
+
+// ValidTimeFormat reports whether format is one of the TimeFormat constants
+func ValidTimeFormat(format string) bool {
+	switch format {
+	case TimeFormatRFC3339, TimeFormatUnix, TimeFormatUnixMilli:
+		return true
+	}
+	return false
+}
+
+// epochTimes reports whether format encodes timestamps as numbers rather than RFC 3339 strings
+func epochTimes(format string) bool {
+	return format == TimeFormatUnix || format == TimeFormatUnixMilli
+}
+
+// encodeTime encodes t in format, sub-second precision is truncated by the epoch formats
+func encodeTime(t time.Time, format string) (json.RawMessage, error) {
+	switch format {
+	case TimeFormatUnix:
+		return json.RawMessage(strconv.FormatInt(t.Unix(), 10)), nil
+	case TimeFormatUnixMilli:
+		return json.RawMessage(strconv.FormatInt(t.UnixMilli(), 10)), nil
+	}
+	return json.Marshal(t)
+}
+
+// decodeEpochTime is the inverse of encodeTime for the epoch formats
+func decodeEpochTime(raw json.RawMessage, format string) (time.Time, error) {
+	n, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if format == TimeFormatUnix {
+		return time.Unix(n, 0), nil
+	}
+	return time.UnixMilli(n), nil
+}
+
+// encodeTimes replaces the timestamps of the encoded article all with their encoding in format
+func encodeTimes(all map[string]json.RawMessage, ar domain.Article, format string) error {
+	values := map[string]*time.Time{"updated_at": &ar.UpdatedAt, "created_at": &ar.CreatedAt, "deleted_at": ar.DeletedAt}
+	for _, field := range timeFields {
+		if _, ok := all[field]; !ok || values[field] == nil {
+			continue
+		}
+		raw, err := encodeTime(*values[field], format)
+		if err != nil {
+			return err
+		}
+		all[field] = raw
+	}
+	return nil
+}
+
+// acceptTimeFormat rewrites the numeric timestamps of the request body to RFC 3339 strings, so a client
+// using an epoch time format can send back the articles it received. RFC 3339 strings are always accepted.
+// A body that is not a JSON object is left to the binding to report.
+func (a *ArticleHandler) acceptTimeFormat(c *gin.Context) error {
+	if !epochTimes(a.timeFormat) {
+		return nil
+	}
+	body, err := c.GetRawData()
+	if err != nil {
+		return err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(body, &all); err != nil {
+		return nil
+	}
+	rewritten := false
+	for _, field := range timeFields {
+		raw, ok := all[field]
+		if !ok || len(raw) == 0 || (raw[0] != '-' && (raw[0] < '0' || raw[0] > '9')) {
+			continue
+		}
+		t, err := decodeEpochTime(raw, a.timeFormat)
+		if err != nil {
+			return err
+		}
+		if all[field], err = json.Marshal(t); err != nil {
+			return err
+		}
+		rewritten = true
+	}
+	if !rewritten {
+		return nil
+	}
+	if body, err = json.Marshal(all); err != nil {
+		return err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return nil
+}
@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/internal/pkg/buildinfo"
+)
+
+// NewVersionHandler exposes GET /version so operators can tell which build is running
+func NewVersionHandler(r *gin.Engine, info buildinfo.Info) {
+	r.GET("/version", func(c *gin.Context) {
+		respondJSON(c, http.StatusOK, info)
+	})
+}
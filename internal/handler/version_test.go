@@ -0,0 +1,39 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/internal/handler"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/buildinfo"
+)
+
+func TestVersion(t *testing.T) {
+	version, commit, buildTime := buildinfo.Version, buildinfo.Commit, buildinfo.BuildTime
+	t.Cleanup(func() { buildinfo.Version, buildinfo.Commit, buildinfo.BuildTime = version, commit, buildTime })
+
+	t.Run("defaults", func(t *testing.T) {
+		assert.Equal(t, buildinfo.Info{Version: "dev", Commit: "dev", BuildTime: "dev"}, buildinfo.Get())
+	})
+
+	t.Run("injected", func(t *testing.T) {
+		// what -ldflags "-X .../buildinfo.Version=..." does at link time
+		buildinfo.Version, buildinfo.Commit, buildinfo.BuildTime = "v1.2.0", "0123abc", "2024-05-01T10:00:00Z"
+
+		r := setupRouter()
+		handler.NewVersionHandler(r, buildinfo.Get())
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var res map[string]string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, map[string]string{"version": "v1.2.0", "commit": "0123abc", "build_time": "2024-05-01T10:00:00Z"}, res)
+	})
+}
@@ -0,0 +1,23 @@
+// Package buildinfo holds the build metadata injected at link time, e.g.
+//
+//	go build -ldflags "-X github.com/bxcodec/go-clean-arch/internal/pkg/buildinfo.Version=v1.2.0" ./app/
+package buildinfo
+
+// Set through -ldflags -X, a binary built without them reports "dev"
+var (
+	Version   = "dev"
+	Commit    = "dev"
+	BuildTime = "dev"
+)
+
+// Info is the build metadata of the running binary
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+}
+
+// Get returns the injected build metadata
+func Get() Info {
+	return Info{Version: Version, Commit: Commit, BuildTime: BuildTime}
+}
@@ -0,0 +1,142 @@
+// Package health aggregates the readiness checks of the components the service depends on,
+// each component registers a named check and Run reports them all at once.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status of a single check and of the whole report
+const (
+	StatusOK = "ok"
+	// StatusDegraded means only optional checks failed, the service can still serve requests
+	StatusDegraded = "degraded"
+	StatusFail     = "fail"
+)
+
+// CheckFunc reports whether a dependency is usable, it must return once ctx is done
+type CheckFunc func(ctx context.Context) error
+
+// Check is a named check registered to a Checker
+type Check struct {
+	Name string
+	Func CheckFunc
+	// Timeout bounds a single run of Func, zero uses the default timeout of the Checker
+	Timeout time.Duration
+	// Optional checks turn the report degraded instead of failed, e.g. a cache the service can run without
+	Optional bool
+}
+
+// Report is the outcome of a run, Checks maps every check name to StatusOK or StatusFail
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+	// Errors holds the error of every failed check, it is not exposed to the clients
+	Errors map[string]error `json:"-"`
+}
+
+// Checker is a registry of checks, safe for concurrent use
+type Checker struct {
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks []Check
+}
+
+// New returns an empty Checker, timeout bounds every check that does not set its own
+func New(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Register adds a check, a check registered under an existing name replaces it
+func (c *Checker) Register(check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.checks {
+		if c.checks[i].Name == check.Name {
+			c.checks[i] = check
+			return
+		}
+	}
+	c.checks = append(c.checks, check)
+}
+
+// Names returns the names of the registered checks, sorted
+func (c *Checker) Names() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	names := make([]string, 0, len(c.checks))
+	for _, check := range c.checks {
+		names = append(names, check.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run executes every check concurrently and waits for all of them, a check exceeding its timeout fails
+// even if Func ignores the context. A Checker without checks reports StatusOK.
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := make([]Check, len(c.checks))
+	copy(checks, c.checks)
+	c.mu.RUnlock()
+
+	errs := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			errs[i] = c.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]string, len(checks)), Errors: map[string]error{}}
+	for i, check := range checks {
+		if errs[i] == nil {
+			report.Checks[check.Name] = StatusOK
+			continue
+		}
+		report.Checks[check.Name] = StatusFail
+		report.Errors[check.Name] = errs[i]
+		if !check.Optional {
+			report.Status = StatusFail
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) error {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = c.timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("check panicked: %v", r)
+			}
+		}()
+		done <- check.Func(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,117 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/internal/pkg/health"
+)
+
+func ok(context.Context) error { return nil }
+
+func failing(context.Context) error { return errors.New("connection refused") }
+
+// blocking ignores its context, the Checker must still give up at the timeout
+func blocking(context.Context) error {
+	time.Sleep(time.Second)
+	return nil
+}
+
+func TestChecker(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []health.Check
+		status string
+		result map[string]string
+	}{
+		{
+			name:   "no-checks",
+			status: health.StatusOK,
+			result: map[string]string{},
+		},
+		{
+			name:   "all-ok",
+			checks: []health.Check{{Name: "mysql", Func: ok}, {Name: "redis", Func: ok}},
+			status: health.StatusOK,
+			result: map[string]string{"mysql": "ok", "redis": "ok"},
+		},
+		{
+			name:   "required-fails",
+			checks: []health.Check{{Name: "mysql", Func: ok}, {Name: "redis", Func: failing}},
+			status: health.StatusFail,
+			result: map[string]string{"mysql": "ok", "redis": "fail"},
+		},
+		{
+			name:   "optional-fails",
+			checks: []health.Check{{Name: "mysql", Func: ok}, {Name: "redis", Func: failing, Optional: true}},
+			status: health.StatusDegraded,
+			result: map[string]string{"mysql": "ok", "redis": "fail"},
+		},
+		{
+			name:   "required-and-optional-fail",
+			checks: []health.Check{{Name: "mysql", Func: failing}, {Name: "redis", Func: failing, Optional: true}},
+			status: health.StatusFail,
+			result: map[string]string{"mysql": "fail", "redis": "fail"},
+		},
+		{
+			name:   "timeout",
+			checks: []health.Check{{Name: "mysql", Func: blocking, Timeout: 10 * time.Millisecond}},
+			status: health.StatusFail,
+			result: map[string]string{"mysql": "fail"},
+		},
+		{
+			name:   "panic",
+			checks: []health.Check{{Name: "mysql", Func: func(context.Context) error { panic("boom") }}},
+			status: health.StatusFail,
+			result: map[string]string{"mysql": "fail"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := health.New(time.Second)
+			for _, check := range tt.checks {
+				c.Register(check)
+			}
+
+			report := c.Run(context.Background())
+
+			assert.Equal(t, tt.status, report.Status)
+			assert.Equal(t, tt.result, report.Checks)
+			for name, result := range tt.result {
+				if result == health.StatusFail {
+					assert.Error(t, report.Errors[name])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckerRunsConcurrently(t *testing.T) {
+	c := health.New(50 * time.Millisecond)
+	for _, name := range []string{"a", "b", "c"} {
+		c.Register(health.Check{Name: name, Func: blocking})
+	}
+
+	start := time.Now()
+	report := c.Run(context.Background())
+
+	// three checks timing out one after the other would take 150ms
+	assert.Less(t, time.Since(start), 140*time.Millisecond)
+	assert.Equal(t, health.StatusFail, report.Status)
+	assert.ErrorIs(t, report.Errors["a"], context.DeadlineExceeded)
+}
+
+func TestCheckerRegisterReplaces(t *testing.T) {
+	c := health.New(time.Second)
+	c.Register(health.Check{Name: "mysql", Func: failing})
+	c.Register(health.Check{Name: "mysql", Func: ok})
+	c.Register(health.Check{Name: "mongo", Func: ok})
+
+	assert.Equal(t, []string{"mongo", "mysql"}, c.Names())
+	assert.Equal(t, health.StatusOK, c.Run(context.Background()).Status)
+}
@@ -0,0 +1,138 @@
+// Package logger provides the leveled application logger that is constructed once in main
+// and injected into the handler and middleware layers.
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	log "github.com/lingdongomg/g-lib/logger"
+)
+
+// Level is the severity of a log entry
+type Level int32
+
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+var levelNames = map[Level]string{
+	DebugLevel: "debug",
+	InfoLevel:  "info",
+	WarnLevel:  "warn",
+	ErrorLevel: "error",
+}
+
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("Level(%d)", int32(l))
+}
+
+// ParseLevel converts a level name (debug, info, warn, error) into a Level
+func ParseLevel(s string) (Level, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if name == "warning" {
+		name = "warn"
+	}
+	for level, levelName := range levelNames {
+		if levelName == name {
+			return level, nil
+		}
+	}
+	return InfoLevel, fmt.Errorf("unknown log level %q", s)
+}
+
+// Sink receives every entry that passes the level filter of a Logger
+type Sink func(level Level, msg string)
+
+// Logger is a leveled logger whose level can be changed at runtime
+type Logger struct {
+	level atomic.Int32
+	sink  Sink
+}
+
+var defaultLogger = New(InfoLevel, nil)
+
+// New creates a Logger writing to the given sink, a nil sink writes to the g-lib logger
+func New(level Level, sink Sink) *Logger {
+	if sink == nil {
+		sink = glibSink
+	}
+	l := &Logger{sink: sink}
+	l.SetLevel(level)
+	return l
+}
+
+// Default returns the logger used when none is injected
+func Default() *Logger {
+	return defaultLogger
+}
+
+// Level returns the current minimum level
+func (l *Logger) Level() Level {
+	return Level(l.level.Load())
+}
+
+// SetLevel changes the minimum level, it is safe to call concurrently with logging
+func (l *Logger) SetLevel(level Level) {
+	l.level.Store(int32(level))
+}
+
+// Enabled reports whether entries of the given level are emitted
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.Level()
+}
+
+func (l *Logger) Debug(v ...interface{}) { l.output(DebugLevel, fmt.Sprint(v...)) }
+func (l *Logger) Info(v ...interface{})  { l.output(InfoLevel, fmt.Sprint(v...)) }
+func (l *Logger) Warn(v ...interface{})  { l.output(WarnLevel, fmt.Sprint(v...)) }
+func (l *Logger) Error(v ...interface{}) { l.output(ErrorLevel, fmt.Sprint(v...)) }
+
+func (l *Logger) Debugf(format string, v ...interface{}) {
+	l.outputf(DebugLevel, format, v...)
+}
+
+func (l *Logger) Infof(format string, v ...interface{}) {
+	l.outputf(InfoLevel, format, v...)
+}
+
+func (l *Logger) Warnf(format string, v ...interface{}) {
+	l.outputf(WarnLevel, format, v...)
+}
+
+func (l *Logger) Errorf(format string, v ...interface{}) {
+	l.outputf(ErrorLevel, format, v...)
+}
+
+func (l *Logger) output(level Level, msg string) {
+	if !l.Enabled(level) {
+		return
+	}
+	l.sink(level, msg)
+}
+
+func (l *Logger) outputf(level Level, format string, v ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	l.sink(level, fmt.Sprintf(format, v...))
+}
+
+func glibSink(level Level, msg string) {
+	switch level {
+	case DebugLevel:
+		log.Debug(msg)
+	case InfoLevel:
+		log.Info(msg)
+	case WarnLevel:
+		log.Warn(msg)
+	default:
+		log.Error(msg)
+	}
+}
@@ -0,0 +1,33 @@
+package logger_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/internal/pkg/logger"
+)
+
+func TestParseLevel(t *testing.T) {
+	level, err := logger.ParseLevel("WARN")
+	require.NoError(t, err)
+	assert.Equal(t, logger.WarnLevel, level)
+
+	_, err = logger.ParseLevel("verbose")
+	assert.Error(t, err)
+}
+
+func TestLoggerLevelFilter(t *testing.T) {
+	var got []string
+	l := logger.New(logger.WarnLevel, func(level logger.Level, msg string) {
+		got = append(got, level.String()+":"+msg)
+	})
+
+	l.Infof("hidden %d", 1)
+	l.Warnf("shown %d", 2)
+	l.SetLevel(logger.DebugLevel)
+	l.Debug("now ", "visible")
+
+	assert.Equal(t, []string{"warn:shown 2", "debug:now visible"}, got)
+}
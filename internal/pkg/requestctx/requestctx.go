@@ -0,0 +1,45 @@
+// Package requestctx carries request scoped values through context.Context, so the layers below the
+// handlers can read them without depending on gin
+package requestctx
+
+import "context"
+
+type (
+	actorKey       struct{}
+	localeKey      struct{}
+	bypassCacheKey struct{}
+)
+
+// WithActor returns a copy of ctx carrying the authenticated caller
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the caller set by WithActor, or "" for an anonymous request
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// WithLocale returns a copy of ctx carrying the language negotiated for the caller, e.g. "en"
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the language set by WithLocale, or "" when none was negotiated
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+// WithBypassCache returns a copy of ctx asking the caches below to read through to the database
+// and refresh what they hold
+func WithBypassCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+// BypassCacheFromContext reports whether WithBypassCache was set on ctx
+func BypassCacheFromContext(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassCacheKey{}).(bool)
+	return bypass
+}
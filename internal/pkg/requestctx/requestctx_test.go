@@ -0,0 +1,27 @@
+package requestctx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/internal/pkg/requestctx"
+)
+
+func TestValues(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, requestctx.ActorFromContext(ctx))
+	assert.Empty(t, requestctx.LocaleFromContext(ctx))
+	assert.False(t, requestctx.BypassCacheFromContext(ctx))
+
+	ctx = requestctx.WithActor(ctx, "alice")
+	ctx = requestctx.WithLocale(ctx, "en")
+	ctx = requestctx.WithBypassCache(ctx)
+	assert.Equal(t, "alice", requestctx.ActorFromContext(ctx))
+	assert.Equal(t, "en", requestctx.LocaleFromContext(ctx))
+	assert.True(t, requestctx.BypassCacheFromContext(ctx))
+
+	// the keys are distinct, a value of one helper is never read by another
+	assert.Empty(t, requestctx.LocaleFromContext(requestctx.WithActor(context.Background(), "en")))
+}
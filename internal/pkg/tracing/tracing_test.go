@@ -0,0 +1,99 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/bxcodec/go-clean-arch/article"
+	"github.com/bxcodec/go-clean-arch/internal/handler"
+	"github.com/bxcodec/go-clean-arch/internal/handler/middleware"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/tracing"
+	mysqlRepo "github.com/bxcodec/go-clean-arch/internal/repository/mysql"
+)
+
+func TestGetProducesNestedSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectQuery("SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, .* FROM article WHERE ID = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}).
+			AddRow(1, "title 1", "title-1", "published", "Content 1", 0, time.Now(), time.Now(), nil, 0, nil))
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.Tracing())
+	r.Use(middleware.ErrorMiddleware())
+	svc := article.NewService(mysqlRepo.NewArticleRepository(db), mysqlRepo.NewAuthorRepository(db))
+	handler.NewArticleHandler(r, svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	spans := exporter.GetSpans()
+	byName := map[string]tracetest.SpanStub{}
+	for _, s := range spans {
+		byName[s.Name] = s
+	}
+	require.Len(t, spans, 3)
+
+	server, ok := byName["GET /api/v1/articles/:id"]
+	require.True(t, ok)
+	svcSpan, ok := byName["article.Service.GetByID"]
+	require.True(t, ok)
+	repoSpan, ok := byName["mysql.ArticleRepository.GetByID"]
+	require.True(t, ok)
+
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+	assert.False(t, server.Parent.IsValid())
+	assert.Equal(t, server.SpanContext.SpanID(), svcSpan.Parent.SpanID())
+	assert.Equal(t, svcSpan.SpanContext.SpanID(), repoSpan.Parent.SpanID())
+	assert.Equal(t, server.SpanContext.TraceID(), repoSpan.SpanContext.TraceID())
+}
+
+func TestServerSpanContinuesIncomingTrace(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	shutdown, err := tracing.Setup(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = shutdown(context.Background()) })
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.Tracing())
+	r.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+}
@@ -0,0 +1,124 @@
+// Package breaker wraps the repositories in a circuit breaker so a database outage fails fast
+// instead of making every request wait for the connection timeout.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	defaultMaxFailures = 5
+	defaultCooldown    = 30 * time.Second
+)
+
+type state int
+
+const (
+	stateClosed state = iota
+	stateOpen
+	stateHalfOpen
+)
+
+// Config holds the breaker thresholds
+type Config struct {
+	// MaxFailures is the number of consecutive failures that opens the breaker
+	MaxFailures int
+	// Cooldown is how long the breaker stays open before letting a probe call through
+	Cooldown time.Duration
+}
+
+// Breaker is a consecutive-failure circuit breaker. While open every call fails with
+// domain.ErrInternalServerError, after Cooldown a single probe is let through (half-open):
+// its success closes the breaker, its failure opens it again.
+type Breaker struct {
+	maxFailures int
+	cooldown    time.Duration
+	now         func() time.Time
+
+	mu       sync.Mutex
+	state    state
+	failures int
+	openedAt time.Time
+}
+
+// New will create a Breaker, zero values in cfg fall back to the defaults
+func New(cfg Config) *Breaker {
+	b := &Breaker{
+		maxFailures: cfg.MaxFailures,
+		cooldown:    cfg.Cooldown,
+		now:         time.Now,
+	}
+	if b.maxFailures <= 0 {
+		b.maxFailures = defaultMaxFailures
+	}
+	if b.cooldown <= 0 {
+		b.cooldown = defaultCooldown
+	}
+	return b
+}
+
+// Execute runs fn unless the breaker is open, and records its outcome
+func (b *Breaker) Execute(fn func() error) error {
+	if !b.allow() {
+		return domain.ErrInternalServerError
+	}
+	err := fn()
+	b.record(err)
+	return err
+}
+
+// allow reports whether a call may go through, moving an expired open breaker to half-open
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case stateOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = stateHalfOpen
+		return true
+	case stateHalfOpen:
+		// only the probe call is allowed until it reports back
+		return false
+	default:
+		return true
+	}
+}
+
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !isFailure(err) {
+		b.state = stateClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == stateHalfOpen || b.failures >= b.maxFailures {
+		b.state = stateOpen
+		b.openedAt = b.now()
+	}
+}
+
+// isFailure reports whether err indicates an unhealthy database. Errors describing the
+// request itself (not found, conflict, bad input, client gone) say nothing about the database.
+func isFailure(err error) bool {
+	switch {
+	case err == nil,
+		errors.Is(err, domain.ErrNotFound),
+		errors.Is(err, domain.ErrConflict),
+		errors.Is(err, domain.ErrBadParamInput),
+		errors.Is(err, context.Canceled):
+		return false
+	}
+	return true
+}
@@ -0,0 +1,104 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/bxcodec/go-clean-arch/article/mocks"
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func newTestBreaker(maxFailures int, cooldown time.Duration) (*Breaker, *fakeClock) {
+	clock := &fakeClock{t: time.Now()}
+	b := New(Config{MaxFailures: maxFailures, Cooldown: cooldown})
+	b.now = clock.now
+	return b, clock
+}
+
+func TestBreakerTripsAndRecovers(t *testing.T) {
+	errDown := errors.New("dial tcp: connection refused")
+	b, clock := newTestBreaker(3, time.Minute)
+
+	mockRepo := new(mocks.ArticleRepository)
+	repo := NewArticleRepository(mockRepo, b)
+
+	// trip the breaker after MaxFailures consecutive failures
+	mockRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{}, errDown).Times(3)
+	for i := 0; i < 3; i++ {
+		_, err := repo.GetByID(context.TODO(), 1)
+		assert.ErrorIs(t, err, errDown)
+	}
+
+	// open: fast-fails without touching the repository
+	_, err := repo.GetByID(context.TODO(), 1)
+	assert.ErrorIs(t, err, domain.ErrInternalServerError)
+	mockRepo.AssertNumberOfCalls(t, "GetByID", 3)
+
+	// half-open after the cooldown: a failed probe opens it again
+	clock.t = clock.t.Add(time.Minute)
+	mockRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{}, errDown).Once()
+	_, err = repo.GetByID(context.TODO(), 1)
+	assert.ErrorIs(t, err, errDown)
+	_, err = repo.GetByID(context.TODO(), 1)
+	assert.ErrorIs(t, err, domain.ErrInternalServerError)
+	mockRepo.AssertNumberOfCalls(t, "GetByID", 4)
+
+	// a successful probe closes it
+	clock.t = clock.t.Add(time.Minute)
+	mockRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1}, nil).Twice()
+	res, err := repo.GetByID(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), res.ID)
+	_, err = repo.GetByID(context.TODO(), 1)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBreakerIgnoresDomainErrors(t *testing.T) {
+	b, _ := newTestBreaker(1, time.Minute)
+
+	mockRepo := new(mocks.ArticleRepository)
+	repo := NewArticleRepository(mockRepo, b)
+
+	mockRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{}, domain.ErrNotFound).Twice()
+	for i := 0; i < 2; i++ {
+		_, err := repo.GetByID(context.TODO(), 1)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBreakerFetchAll(t *testing.T) {
+	errDown := errors.New("dial tcp: connection refused")
+	b, _ := newTestBreaker(1, time.Minute)
+
+	mockRepo := new(mocks.ArticleRepository)
+	repo := NewArticleRepository(mockRepo, b)
+
+	articles := make(chan domain.Article)
+	close(articles)
+	errs := make(chan error, 1)
+	errs <- errDown
+	close(errs)
+	mockRepo.On("FetchAll", mock.Anything).Return((<-chan domain.Article)(articles), (<-chan error)(errs)).Once()
+
+	resArticles, resErrs := repo.FetchAll(context.TODO())
+	for range resArticles {
+	}
+	assert.ErrorIs(t, <-resErrs, errDown)
+
+	resArticles, resErrs = repo.FetchAll(context.TODO())
+	for range resArticles {
+	}
+	assert.ErrorIs(t, <-resErrs, domain.ErrInternalServerError)
+	mockRepo.AssertExpectations(t)
+}
@@ -2,29 +2,70 @@ package repository
 
 import (
 	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
 	"time"
-)
 
-const (
-	timeFormat = "2006-01-02T15:04:05.999Z07:00" // reduce precision from RFC3339Nano as date format
+	"github.com/bxcodec/go-clean-arch/domain"
 )
 
-// DecodeCursor will decode cursor from user for mysql
-func DecodeCursor(encodedTime string) (time.Time, error) {
-	byt, err := base64.StdEncoding.DecodeString(encodedTime)
+const cursorSeparator = ","
+
+// DecodeCursor will decode cursor from user for mysql into the (created_at, id) keyset position
+// of the last article of the previous page
+func DecodeCursor(cursor string) (time.Time, int64, error) {
+	byt, err := base64.StdEncoding.DecodeString(cursor)
 	if err != nil {
-		return time.Time{}, err
+		return time.Time{}, 0, err
 	}
 
-	timeString := string(byt)
-	t, err := time.Parse(timeFormat, timeString)
-
-	return t, err
+	timeString, idString, ok := strings.Cut(string(byt), cursorSeparator)
+	if !ok {
+		return time.Time{}, 0, errors.New("invalid cursor")
+	}
+	t, err := time.Parse(time.RFC3339Nano, timeString)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	id, err := strconv.ParseInt(idString, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	return t, id, nil
 }
 
-// EncodeCursor will encode cursor from mysql to user
-func EncodeCursor(t time.Time) string {
-	timeString := t.Format(timeFormat)
+// EncodeCursor will encode cursor from mysql to user. The full precision timestamp and the id
+// are both needed so articles sharing a created_at are neither skipped nor repeated.
+func EncodeCursor(t time.Time, id int64) string {
+	raw := t.Format(time.RFC3339Nano) + cursorSeparator + strconv.FormatInt(id, 10)
+
+	return base64.StdEncoding.EncodeToString([]byte(raw))
+}
 
-	return base64.StdEncoding.EncodeToString([]byte(timeString))
+// PageCursors returns the cursors around res, the page read from cursor in direction, encode gives the cursor
+// positioned on an article. A full page may be followed by more articles in direction, and when a cursor was
+// given there are articles on the other side, the ones the client came from.
+func PageCursors(res []domain.Article, cursor string, direction domain.PageDirection, num int64, encode func(domain.Article) string) domain.PageCursors {
+	var page domain.PageCursors
+	if len(res) == 0 {
+		return page
+	}
+	full := len(res) == int(num)
+	if direction == domain.PagePrev {
+		if full {
+			page.Prev = encode(res[0])
+		}
+		if cursor != "" {
+			page.Next = encode(res[len(res)-1])
+		}
+		return page
+	}
+	if full {
+		page.Next = encode(res[len(res)-1])
+	}
+	if cursor != "" {
+		page.Prev = encode(res[0])
+	}
+	return page
 }
@@ -0,0 +1,542 @@
+package mongo
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+	log "github.com/lingdongomg/g-lib/logger"
+)
+
+const (
+	articleCollection = "article"
+	counterCollection = "counters"
+)
+
+// articleDocument is the BSON representation of domain.Article, the author is stored by id only
+type articleDocument struct {
+	ID        int64      `bson:"_id"`
+	Title     string     `bson:"title"`
+	Slug      string     `bson:"slug"`
+	Status    string     `bson:"status"`
+	Content   string     `bson:"content"`
+	AuthorID  int64      `bson:"author_id"`
+	UpdatedAt time.Time  `bson:"updated_at"`
+	CreatedAt time.Time  `bson:"created_at"`
+	DeletedAt *time.Time `bson:"deleted_at,omitempty"`
+	// Metadata is a bson.M so that its nested documents decode as maps too, rather than as bson.D
+	Metadata bson.M `bson:"metadata,omitempty"`
+	// ViewCount is only written by IncrementViewCount, a new document starts at zero
+	ViewCount int `bson:"view_count"`
+	// Tags is the array the tags filter matches with $in or $all
+	Tags []string `bson:"tags,omitempty"`
+}
+
+func newArticleDocument(a *domain.Article) articleDocument {
+	return articleDocument{
+		ID:        a.ID,
+		Title:     a.Title,
+		Slug:      a.Slug,
+		Status:    string(a.Status),
+		Content:   a.Content,
+		AuthorID:  a.Author.ID,
+		UpdatedAt: a.UpdatedAt,
+		CreatedAt: a.CreatedAt,
+		Metadata:  a.Metadata,
+		Tags:      a.Tags,
+	}
+}
+
+func (d articleDocument) toDomain() domain.Article {
+	return domain.Article{
+		ID:        d.ID,
+		Title:     d.Title,
+		Slug:      d.Slug,
+		Status:    domain.ArticleStatus(d.Status),
+		Content:   d.Content,
+		Author:    domain.Author{ID: d.AuthorID},
+		UpdatedAt: d.UpdatedAt,
+		CreatedAt: d.CreatedAt,
+		DeletedAt: d.DeletedAt,
+		Metadata:  d.Metadata,
+		ViewCount: d.ViewCount,
+		Tags:      d.Tags,
+	}
+}
+
+// notDeleted matches documents without a deleted_at, every query except GetByIDIncludingDeleted
+// and FetchChanges applies it
+func notDeleted(query bson.M) bson.M {
+	query["deleted_at"] = nil
+	return query
+}
+
+type ArticleRepository struct {
+	DB *mongo.Database
+}
+
+// NewArticleRepository will create an object that represent the article.Repository interface
+func NewArticleRepository(db *mongo.Database) *ArticleRepository {
+	return &ArticleRepository{DB: db}
+}
+
+func (m *ArticleRepository) collection() *mongo.Collection {
+	return m.DB.Collection(articleCollection)
+}
+
+func (m *ArticleRepository) find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (result []domain.Article, err error) {
+	cur, err := m.collection().Find(ctx, filter, opts...)
+	if err != nil {
+		log.Error("Failed to execute query:", err)
+		return nil, err
+	}
+	defer func() {
+		errCur := cur.Close(ctx)
+		if errCur != nil {
+			log.Error("Failed to close cursor:", errCur)
+		}
+	}()
+
+	result = make([]domain.Article, 0)
+	for cur.Next(ctx) {
+		var doc articleDocument
+		if err = cur.Decode(&doc); err != nil {
+			log.Error("Failed to decode document:", err)
+			return nil, err
+		}
+		result = append(result, doc.toDomain())
+	}
+	return result, cur.Err()
+}
+
+func (m *ArticleRepository) findOne(ctx context.Context, filter interface{}) (domain.Article, error) {
+	var doc articleDocument
+	err := m.collection().FindOne(ctx, filter).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Article{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Article{}, err
+	}
+	return doc.toDomain(), nil
+}
+
+// Fetch pages through the articles by _id, the cursor is the last _id of the previous page
+func (m *ArticleRepository) Fetch(ctx context.Context, cursor string, direction domain.PageDirection, num int64, filter domain.ArticleFilter) (res []domain.Article, page domain.PageCursors, err error) {
+	lastID, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, domain.PageCursors{}, domain.ErrBadParamInput
+	}
+
+	// the previous page is read backwards from the cursor, without a cursor from the end of the list
+	query := filterDocument(filter)
+	backward := direction == domain.PagePrev
+	order := 1
+	switch {
+	case !backward:
+		query["_id"] = bson.M{"$gt": lastID}
+	case cursor != "":
+		query["_id"] = bson.M{"$lt": lastID}
+		fallthrough
+	default:
+		order = -1
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: order}}).SetLimit(num)
+	res, err = m.find(ctx, query, opts)
+	if err != nil {
+		return nil, domain.PageCursors{}, err
+	}
+	if backward {
+		slices.Reverse(res)
+	}
+
+	return res, repository.PageCursors(res, cursor, direction, num, func(ar domain.Article) string {
+		return encodeCursor(ar.ID)
+	}), nil
+}
+
+func (m *ArticleRepository) FetchRecent(ctx context.Context, num int64, filter domain.ArticleFilter) ([]domain.Article, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(num)
+	return m.find(ctx, filterDocument(filter), opts)
+}
+
+// FetchAll streams every article ordered by _id, see mysql.ArticleRepository.FetchAll for the channel semantics
+func (m *ArticleRepository) FetchAll(ctx context.Context) (<-chan domain.Article, <-chan error) {
+	articles := make(chan domain.Article)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(articles)
+
+		cur, err := m.collection().Find(ctx, notDeleted(bson.M{}), options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+		if err != nil {
+			log.Error("Failed to execute query:", err)
+			errs <- err
+			return
+		}
+		defer func() {
+			errCur := cur.Close(ctx)
+			if errCur != nil {
+				log.Error("Failed to close cursor:", errCur)
+			}
+		}()
+
+		for cur.Next(ctx) {
+			var doc articleDocument
+			if err := cur.Decode(&doc); err != nil {
+				log.Error("Failed to decode document:", err)
+				errs <- err
+				return
+			}
+			select {
+			case articles <- doc.toDomain():
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+		if err := cur.Err(); err != nil {
+			errs <- err
+		}
+	}()
+
+	return articles, errs
+}
+
+// FetchChanges pages by (updated_at, _id), the cursor encodes both like the mysql cursor.
+// Soft-deleted articles are included with DeletedAt set.
+func (m *ArticleRepository) FetchChanges(ctx context.Context, since time.Time, cursor string, num int64) (res []domain.Article, nextCursor string, err error) {
+	query := bson.M{"updated_at": bson.M{"$gt": since}}
+	if cursor != "" {
+		updatedAt, id, err := repository.DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", domain.ErrBadParamInput
+		}
+		query["$or"] = bson.A{
+			bson.M{"updated_at": bson.M{"$gt": updatedAt}},
+			bson.M{"updated_at": updatedAt, "_id": bson.M{"$gt": id}},
+		}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(num)
+	res, err = m.find(ctx, query, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(res) == int(num) {
+		last := res[len(res)-1]
+		nextCursor = repository.EncodeCursor(last.UpdatedAt, last.ID)
+	}
+	return
+}
+
+// Count returns the number of articles matching filter
+func (m *ArticleRepository) Count(ctx context.Context, filter domain.ArticleFilter) (int64, error) {
+	return m.collection().CountDocuments(ctx, filterDocument(filter))
+}
+
+func (m *ArticleRepository) CountByAuthor(ctx context.Context, authorID int64) (int64, error) {
+	return m.collection().CountDocuments(ctx, notDeleted(bson.M{"author_id": authorID}))
+}
+
+func (m *ArticleRepository) Exists(ctx context.Context, id int64) (bool, error) {
+	n, err := m.collection().CountDocuments(ctx, notDeleted(bson.M{"_id": id}), options.Count().SetLimit(1))
+	return n > 0, err
+}
+
+func (m *ArticleRepository) GetPrevious(ctx context.Context, ar domain.Article, filter domain.ArticleFilter) (domain.Article, error) {
+	return m.getNeighbor(ctx, ar, filter, "$lt", -1)
+}
+
+func (m *ArticleRepository) GetNext(ctx context.Context, ar domain.Article, filter domain.ArticleFilter) (domain.Article, error) {
+	return m.getNeighbor(ctx, ar, filter, "$gt", 1)
+}
+
+// getNeighbor finds the closest article in created_at order, with _id breaking ties between equal timestamps
+func (m *ArticleRepository) getNeighbor(ctx context.Context, ar domain.Article, filter domain.ArticleFilter, cmp string, order int) (domain.Article, error) {
+	query := filterDocument(filter)
+	position := bson.A{
+		bson.M{"created_at": bson.M{cmp: ar.CreatedAt}},
+		bson.M{"created_at": ar.CreatedAt, "_id": bson.M{cmp: ar.ID}},
+	}
+	if createdAt, ok := query["created_at"]; ok {
+		// keep the created_at window of the filter next to the position condition
+		delete(query, "created_at")
+		query["$and"] = bson.A{bson.M{"created_at": createdAt}, bson.M{"$or": position}}
+	} else {
+		query["$or"] = position
+	}
+
+	var doc articleDocument
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: order}, {Key: "_id", Value: order}})
+	err := m.collection().FindOne(ctx, query, opts).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Article{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Article{}, err
+	}
+	return doc.toDomain(), nil
+}
+
+// filterDocument translates filter into a query document
+func filterDocument(filter domain.ArticleFilter) bson.M {
+	query := bson.M{}
+	if filter.Status != "" {
+		query["status"] = string(filter.Status)
+	}
+	if filter.AuthorID != 0 {
+		query["author_id"] = filter.AuthorID
+	}
+	createdAt := bson.M{}
+	if !filter.CreatedFrom.IsZero() {
+		createdAt["$gte"] = filter.CreatedFrom
+	}
+	if !filter.CreatedTo.IsZero() {
+		createdAt["$lte"] = filter.CreatedTo
+	}
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+	// tags is an array of strings on the article document
+	if len(filter.Tags) > 0 {
+		if filter.TagMatch == domain.TagMatchAll {
+			query["tags"] = bson.M{"$all": filter.Tags}
+		} else {
+			query["tags"] = bson.M{"$in": filter.Tags}
+		}
+	}
+	return notDeleted(query)
+}
+
+func (m *ArticleRepository) GetByID(ctx context.Context, id int64) (domain.Article, error) {
+	return m.findOne(ctx, notDeleted(bson.M{"_id": id}))
+}
+
+// GetByIDIncludingDeleted also returns a soft-deleted article, with DeletedAt set
+func (m *ArticleRepository) GetByIDIncludingDeleted(ctx context.Context, id int64) (domain.Article, error) {
+	return m.findOne(ctx, bson.M{"_id": id})
+}
+
+// GetByIDs returns the articles among ids that exist, in id order. Missing ids are simply absent from the result.
+func (m *ArticleRepository) GetByIDs(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	if len(ids) == 0 {
+		return []domain.Article{}, nil
+	}
+	return m.find(ctx, notDeleted(bson.M{"_id": bson.M{"$in": ids}}), options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+}
+
+func (m *ArticleRepository) GetByTitle(ctx context.Context, title string) (domain.Article, error) {
+	return m.findOne(ctx, notDeleted(bson.M{"title": title}))
+}
+
+// FindByTitle returns every article titled title in id order
+func (m *ArticleRepository) FindByTitle(ctx context.Context, title string) ([]domain.Article, error) {
+	return m.find(ctx, notDeleted(bson.M{"title": title}), options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+}
+
+func (m *ArticleRepository) GetBySlug(ctx context.Context, slug string) (domain.Article, error) {
+	return m.findOne(ctx, notDeleted(bson.M{"slug": slug}))
+}
+
+// Store assigns the next numeric id from the counters collection before inserting the article,
+// unless the service already generated one
+func (m *ArticleRepository) Store(ctx context.Context, a *domain.Article) (err error) {
+	id, err := m.assignID(ctx, a)
+	if err != nil {
+		return
+	}
+
+	defaultTimestamps(a, time.Now())
+	doc := newArticleDocument(a)
+	doc.ID = id
+	_, err = m.collection().InsertOne(ctx, doc)
+	if err != nil {
+		return translateError(err)
+	}
+	a.ID = id
+	return
+}
+
+// Delete soft-deletes the article, it also bumps updated_at so incremental readers notice the deletion
+func (m *ArticleRepository) Delete(ctx context.Context, id int64) (err error) {
+	now := time.Now()
+	update := bson.M{"$set": bson.M{"deleted_at": now, "updated_at": now}}
+	res, err := m.collection().UpdateOne(ctx, notDeleted(bson.M{"_id": id}), update)
+	if err != nil {
+		return
+	}
+	if res.MatchedCount != 1 {
+		err = fmt.Errorf("weird  Behavior. Total Affected: %d", res.MatchedCount)
+	}
+	return
+}
+
+// IncrementViewCount relies on $inc, which the server applies atomically
+func (m *ArticleRepository) IncrementViewCount(ctx context.Context, id int64) error {
+	res, err := m.collection().UpdateOne(ctx, notDeleted(bson.M{"_id": id}), bson.M{"$inc": bson.M{"view_count": 1}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount != 1 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (m *ArticleRepository) Update(ctx context.Context, ar *domain.Article) (err error) {
+	update := bson.M{"$set": bson.M{
+		"title":      ar.Title,
+		"content":    ar.Content,
+		"author_id":  ar.Author.ID,
+		"metadata":   bson.M(ar.Metadata),
+		"tags":       ar.Tags,
+		"updated_at": ar.UpdatedAt,
+	}}
+	res, err := m.collection().UpdateOne(ctx, notDeleted(bson.M{"_id": ar.ID}), update)
+	if err != nil {
+		return translateError(err)
+	}
+	if res.MatchedCount != 1 {
+		err = fmt.Errorf("weird  Behavior. Total Affected: %d", res.MatchedCount)
+	}
+	return
+}
+
+// Upsert sets the replaceable fields on every write and the slug, status and created_at only when inserting
+func (m *ArticleRepository) Upsert(ctx context.Context, ar *domain.Article) (created bool, err error) {
+	update := bson.M{
+		"$set": bson.M{
+			"title":      ar.Title,
+			"content":    ar.Content,
+			"author_id":  ar.Author.ID,
+			"metadata":   bson.M(ar.Metadata),
+			"tags":       ar.Tags,
+			"updated_at": ar.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{
+			"slug":       ar.Slug,
+			"status":     string(ar.Status),
+			"created_at": ar.CreatedAt,
+		},
+		"$unset": bson.M{"deleted_at": ""},
+	}
+	res, err := m.collection().UpdateOne(ctx, bson.M{"_id": ar.ID}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return false, translateError(err)
+	}
+	return res.UpsertedCount == 1, nil
+}
+
+// UpdateStatus only matches the article while it still has status from, so concurrent transitions cannot both succeed
+func (m *ArticleRepository) UpdateStatus(ctx context.Context, ar *domain.Article, from domain.ArticleStatus) (err error) {
+	update := bson.M{"$set": bson.M{
+		"status":     string(ar.Status),
+		"updated_at": ar.UpdatedAt,
+	}}
+	res, err := m.collection().UpdateOne(ctx, notDeleted(bson.M{"_id": ar.ID, "status": string(from)}), update)
+	if err != nil {
+		return
+	}
+	if res.MatchedCount != 1 {
+		return domain.ErrConflict
+	}
+	return
+}
+
+// StoreBatch inserts the articles with a single ordered InsertMany. Mongo only offers multi-document
+// transactions on replica sets, so a failure part way through may leave the earlier articles stored.
+func (m *ArticleRepository) StoreBatch(ctx context.Context, articles []*domain.Article) error {
+	docs := make([]interface{}, 0, len(articles))
+	ids := make([]int64, 0, len(articles))
+	now := time.Now()
+	for _, a := range articles {
+		id, err := m.assignID(ctx, a)
+		if err != nil {
+			return err
+		}
+		defaultTimestamps(a, now)
+		doc := newArticleDocument(a)
+		doc.ID = id
+		docs = append(docs, doc)
+		ids = append(ids, id)
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
+	_, err := m.collection().InsertMany(ctx, docs)
+	if err != nil {
+		return translateError(err)
+	}
+	for i, a := range articles {
+		a.ID = ids[i]
+	}
+	return nil
+}
+
+// defaultTimestamps fills the timestamps a new article leaves zero, as the column defaults do in MySQL
+func defaultTimestamps(a *domain.Article, now time.Time) {
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = now
+	}
+	if a.UpdatedAt.IsZero() {
+		a.UpdatedAt = now
+	}
+}
+
+// assignID keeps an id generated by the service and only draws from the sequence otherwise
+func (m *ArticleRepository) assignID(ctx context.Context, a *domain.Article) (int64, error) {
+	if a.ID != 0 {
+		return a.ID, nil
+	}
+	return m.nextID(ctx)
+}
+
+// nextID atomically increments the article sequence, MongoDB has no auto-increment
+func (m *ArticleRepository) nextID(ctx context.Context) (int64, error) {
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := m.DB.Collection(counterCollection).
+		FindOneAndUpdate(ctx, bson.M{"_id": articleCollection}, bson.M{"$inc": bson.M{"seq": 1}}, opts).
+		Decode(&counter)
+	if err != nil {
+		return 0, err
+	}
+	return counter.Seq, nil
+}
+
+func translateError(err error) error {
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrConflict
+	}
+	return err
+}
+
+func encodeCursor(id int64) string {
+	return base64.StdEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	byt, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(byt), 10, 64)
+}
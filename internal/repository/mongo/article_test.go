@@ -0,0 +1,499 @@
+package mongo_test
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	articleMongoRepo "github.com/bxcodec/go-clean-arch/internal/repository/mongo"
+)
+
+func articleDoc(id int64, title string) bson.D {
+	return bson.D{
+		{Key: "_id", Value: id},
+		{Key: "title", Value: title},
+		{Key: "slug", Value: "slug-" + title},
+		{Key: "status", Value: "published"},
+		{Key: "content", Value: "content"},
+		{Key: "author_id", Value: int64(1)},
+		{Key: "updated_at", Value: time.Now()},
+		{Key: "created_at", Value: time.Now()},
+	}
+}
+
+func TestArticleRepository(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("fetch", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "article.article", mtest.FirstBatch,
+			articleDoc(1, "title 1"), articleDoc(2, "title 2")))
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		list, page, err := a.Fetch(context.TODO(), "", domain.PageNext, 2, domain.ArticleFilter{Status: domain.StatusPublished})
+		assert.NoError(t, err)
+		assert.Len(t, list, 2)
+		assert.Equal(t, int64(1), list[0].Author.ID)
+		assert.Equal(t, domain.StatusPublished, list[0].Status)
+		assert.NotEmpty(t, page.Next)
+		assert.Empty(t, page.Prev)
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "article.article", mtest.FirstBatch))
+		list, page, err = a.Fetch(context.TODO(), page.Next, domain.PageNext, 2, domain.ArticleFilter{Status: domain.StatusPublished})
+		assert.NoError(t, err)
+		assert.Len(t, list, 0)
+		assert.Empty(t, page)
+	})
+
+	mt.Run("fetch-previous", func(mt *mtest.T) {
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		// backwards from the third article, the page is read in descending _id order and returned ascending
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "article.article", mtest.FirstBatch,
+			articleDoc(2, "title 2"), articleDoc(1, "title 1")))
+		third := base64.StdEncoding.EncodeToString([]byte("3"))
+		list, page, err := a.Fetch(context.TODO(), third, domain.PagePrev, 2, domain.ArticleFilter{})
+		assert.NoError(t, err)
+		if assert.Len(t, list, 2) {
+			assert.Equal(t, int64(1), list[0].ID)
+			assert.Equal(t, int64(2), list[1].ID)
+		}
+		assert.NotEmpty(t, page.Prev)
+		assert.NotEmpty(t, page.Next)
+		started := mt.GetStartedEvent()
+		assert.Equal(t, int32(-1), started.Command.Lookup("sort", "_id").Int32())
+		_, err = started.Command.Lookup("filter", "_id").Document().LookupErr("$lt")
+		assert.NoError(t, err)
+
+		// without a cursor the last page is read, nothing follows it
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "article.article", mtest.FirstBatch, articleDoc(9, "title 9")))
+		list, page, err = a.Fetch(context.TODO(), "", domain.PagePrev, 2, domain.ArticleFilter{})
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		assert.Empty(t, page)
+		_, err = mt.GetStartedEvent().Command.Lookup("filter").Document().LookupErr("_id")
+		assert.Error(t, err)
+	})
+
+	mt.Run("fetch-changes", func(mt *mtest.T) {
+		deletedAt := time.Now()
+		deleted := append(articleDoc(2, "title 2"), bson.E{Key: "deleted_at", Value: deletedAt})
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "article.article", mtest.FirstBatch, articleDoc(1, "title 1"), deleted))
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+		since := time.Now().Add(-time.Hour)
+
+		list, nextCursor, err := a.FetchChanges(context.TODO(), since, "", 2)
+		assert.NoError(t, err)
+		if assert.Len(t, list, 2) {
+			assert.Nil(t, list[0].DeletedAt)
+			assert.NotNil(t, list[1].DeletedAt)
+		}
+		assert.NotEmpty(t, nextCursor)
+		started := mt.GetStartedEvent()
+		_, err = started.Command.Lookup("filter").Document().LookupErr("deleted_at")
+		assert.Error(t, err)
+		_, err = started.Command.Lookup("filter").Document().LookupErr("$or")
+		assert.Error(t, err)
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "article.article", mtest.FirstBatch))
+		list, nextCursor, err = a.FetchChanges(context.TODO(), since, nextCursor, 2)
+		assert.NoError(t, err)
+		assert.Empty(t, list)
+		assert.Empty(t, nextCursor)
+		clauses, err := mt.GetStartedEvent().Command.Lookup("filter", "$or").Array().Values()
+		assert.NoError(t, err)
+		assert.Len(t, clauses, 2)
+	})
+
+	mt.Run("fetch-invalid-cursor", func(mt *mtest.T) {
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		_, _, err := a.Fetch(context.TODO(), "not-a-cursor", domain.PageNext, 2, domain.ArticleFilter{})
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	})
+
+	mt.Run("fetch-recent", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "article.article", mtest.FirstBatch,
+			articleDoc(2, "title 2"), articleDoc(1, "title 1")))
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		list, err := a.FetchRecent(context.TODO(), 2, domain.ArticleFilter{Status: domain.StatusPublished})
+		assert.NoError(t, err)
+		assert.Len(t, list, 2)
+
+		// newest first
+		command := mt.GetStartedEvent().Command
+		sort := command.Lookup("sort").Document()
+		assert.Equal(t, int32(-1), sort.Lookup("created_at").Int32())
+		assert.Equal(t, int64(2), command.Lookup("limit").Int64())
+	})
+
+	mt.Run("get-by-id", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "article.article", mtest.FirstBatch, articleDoc(5, "title 5")))
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		ar, err := a.GetByID(context.TODO(), 5)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), ar.ID)
+		assert.Equal(t, "title 5", ar.Title)
+
+		// soft-deleted documents are filtered out
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		assert.NoError(t, filter.Lookup("deleted_at").Validate())
+	})
+
+	mt.Run("get-by-id-including-deleted", func(mt *mtest.T) {
+		deletedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		doc := append(articleDoc(5, "title 5"), bson.E{Key: "deleted_at", Value: deletedAt})
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "article.article", mtest.FirstBatch, doc))
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		ar, err := a.GetByIDIncludingDeleted(context.TODO(), 5)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), ar.ID)
+		if assert.NotNil(t, ar.DeletedAt) {
+			assert.True(t, deletedAt.Equal(*ar.DeletedAt))
+		}
+
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		_, err = filter.LookupErr("deleted_at")
+		assert.Error(t, err)
+	})
+
+	mt.Run("get-by-slug", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "article.article", mtest.FirstBatch, articleDoc(5, "5")))
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		ar, err := a.GetBySlug(context.TODO(), "slug-5")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), ar.ID)
+		assert.Equal(t, "slug-5", ar.Slug)
+	})
+
+	mt.Run("get-by-title-not-found", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "article.article", mtest.FirstBatch))
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		_, err := a.GetByTitle(context.TODO(), "missing")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	mt.Run("find-by-title", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "article.article", mtest.FirstBatch, articleDoc(5, "5"), articleDoc(9, "9")))
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		list, err := a.FindByTitle(context.TODO(), "Judul")
+		assert.NoError(t, err)
+		if assert.Len(t, list, 2) {
+			assert.Equal(t, int64(5), list[0].ID)
+			assert.Equal(t, int64(9), list[1].ID)
+		}
+		started := mt.GetStartedEvent()
+		assert.Equal(t, "Judul", started.Command.Lookup("filter", "title").StringValue())
+		assert.Equal(t, int32(1), started.Command.Lookup("sort", "_id").Int32())
+	})
+
+	mt.Run("store", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{{Key: "_id", Value: "article"}, {Key: "seq", Value: int64(12)}}}),
+			mtest.CreateSuccessResponse(),
+		)
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		ar := &domain.Article{Title: "Judul", Content: "Content", Author: domain.Author{ID: 1}}
+		err := a.Store(context.TODO(), ar)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(12), ar.ID)
+	})
+
+	mt.Run("metadata", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{{Key: "_id", Value: "article"}, {Key: "seq", Value: int64(12)}}}),
+			mtest.CreateSuccessResponse(),
+		)
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		ar := &domain.Article{Title: "Judul", Content: "Content", Metadata: map[string]any{"cover": "cover.png"}}
+		assert.NoError(t, a.Store(context.TODO(), ar))
+		mt.GetStartedEvent() // the id counter
+		inserted := mt.GetStartedEvent().Command.Lookup("documents").Array().Index(0).Value().Document()
+		assert.Equal(t, "cover.png", inserted.Lookup("metadata", "cover").StringValue())
+
+		doc := append(articleDoc(12, "Judul"), bson.E{Key: "metadata", Value: bson.D{{Key: "cover", Value: "cover.png"}}})
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "article.article", mtest.FirstBatch, doc))
+		got, err := a.GetByID(context.TODO(), 12)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"cover": "cover.png"}, got.Metadata)
+	})
+
+	mt.Run("tags", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{{Key: "_id", Value: "article"}, {Key: "seq", Value: int64(12)}}}),
+			mtest.CreateSuccessResponse(),
+		)
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		ar := &domain.Article{Title: "Judul", Content: "Content", Tags: []string{"go", "web"}}
+		assert.NoError(t, a.Store(context.TODO(), ar))
+		mt.GetStartedEvent() // the id counter
+		inserted := mt.GetStartedEvent().Command.Lookup("documents").Array().Index(0).Value().Document()
+		assert.Equal(t, "web", inserted.Lookup("tags", "1").StringValue())
+
+		// Update replaces the tags
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		ar.Tags = []string{"go"}
+		assert.NoError(t, a.Update(context.TODO(), ar))
+		set := mt.GetStartedEvent().Command.Lookup("updates", "0", "u", "$set").Document()
+		assert.Equal(t, "go", set.Lookup("tags", "0").StringValue())
+
+		doc := append(articleDoc(12, "Judul"), bson.E{Key: "tags", Value: bson.A{"go", "web"}})
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "article.article", mtest.FirstBatch, doc))
+		got, err := a.GetByID(context.TODO(), 12)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"go", "web"}, got.Tags)
+	})
+
+	mt.Run("store-batch", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{{Key: "_id", Value: "article"}, {Key: "seq", Value: int64(20)}}}),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{{Key: "_id", Value: "article"}, {Key: "seq", Value: int64(21)}}}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}),
+		)
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		batch := []*domain.Article{{Title: "First", Content: "Content"}, {Title: "Second", Content: "Content"}}
+		err := a.StoreBatch(context.TODO(), batch)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(20), batch[0].ID)
+		assert.Equal(t, int64(21), batch[1].ID)
+
+		// the imported articles leave the timestamps zero, the repository fills them in
+		started := mt.GetStartedEvent()
+		for started != nil && started.CommandName != "insert" {
+			started = mt.GetStartedEvent()
+		}
+		if !assert.NotNil(t, started) {
+			return
+		}
+		for i, ar := range batch {
+			assert.False(t, ar.CreatedAt.IsZero())
+			doc := started.Command.Lookup("documents", strconv.Itoa(i)).Document()
+			assert.False(t, doc.Lookup("created_at").Time().IsZero())
+			assert.False(t, doc.Lookup("updated_at").Time().IsZero())
+		}
+	})
+
+	mt.Run("store-duplicate", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{{Key: "_id", Value: "article"}, {Key: "seq", Value: int64(13)}}}),
+			mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "duplicate key error"}),
+		)
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		ar := &domain.Article{Title: "Judul", Content: "Content"}
+		err := a.Store(context.TODO(), ar)
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.Equal(t, int64(0), ar.ID)
+	})
+
+	mt.Run("count", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "article.article", mtest.FirstBatch, bson.D{{Key: "n", Value: int64(42)}}))
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		total, err := a.Count(context.TODO(), domain.ArticleFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), total)
+	})
+
+	mt.Run("fetch-by-tags", func(mt *mtest.T) {
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+		for match, operator := range map[domain.TagMatch]string{domain.TagMatchAll: "$all", domain.TagMatchAny: "$in"} {
+			mt.AddMockResponses(mtest.CreateCursorResponse(0, "article.article", mtest.FirstBatch, articleDoc(1, "title 1")))
+
+			filter := domain.ArticleFilter{Tags: []string{"go", "web"}, TagMatch: match}
+			list, _, err := a.Fetch(context.TODO(), "", domain.PageNext, 2, filter)
+			assert.NoError(t, err)
+			assert.Len(t, list, 1)
+
+			tags := mt.GetStartedEvent().Command.Lookup("filter", "tags", operator).Array()
+			values, err := tags.Values()
+			assert.NoError(t, err)
+			assert.Len(t, values, 2)
+		}
+	})
+
+	mt.Run("upsert", func(mt *mtest.T) {
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+		ar := &domain.Article{ID: 12, Title: "Judul", Slug: "judul", Status: domain.StatusDraft, Content: "Content", Author: domain.Author{ID: 1}}
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(
+			bson.E{Key: "n", Value: 1},
+			bson.E{Key: "upserted", Value: bson.A{bson.D{{Key: "index", Value: 0}, {Key: "_id", Value: int64(12)}}}},
+		))
+		created, err := a.Upsert(context.TODO(), ar)
+		assert.NoError(t, err)
+		assert.True(t, created)
+		started := mt.GetStartedEvent()
+		assert.Equal(t, "update", started.CommandName)
+		assert.True(t, started.Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("upsert").Boolean())
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		created, err = a.Upsert(context.TODO(), ar)
+		assert.NoError(t, err)
+		assert.False(t, created)
+	})
+
+	mt.Run("count-by-author", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "article.article", mtest.FirstBatch, bson.D{{Key: "n", Value: int64(3)}}))
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		total, err := a.CountByAuthor(context.TODO(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+	})
+
+	mt.Run("exists", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "article.article", mtest.FirstBatch, bson.D{{Key: "n", Value: int64(1)}}),
+			mtest.CreateCursorResponse(0, "article.article", mtest.FirstBatch),
+		)
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		exists, err := a.Exists(context.TODO(), 12)
+		assert.NoError(t, err)
+		assert.True(t, exists)
+		exists, err = a.Exists(context.TODO(), 13)
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	mt.Run("update", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		err := a.Update(context.TODO(), &domain.Article{ID: 12, Title: "Judul", Content: "Content"})
+		assert.NoError(t, err)
+	})
+
+	mt.Run("update-status", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		err := a.UpdateStatus(context.TODO(), &domain.Article{ID: 12, Status: domain.StatusPublished}, domain.StatusDraft)
+		assert.NoError(t, err)
+	})
+
+	mt.Run("update-status-conflict", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}))
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		err := a.UpdateStatus(context.TODO(), &domain.Article{ID: 12, Status: domain.StatusPublished}, domain.StatusDraft)
+		assert.ErrorIs(t, err, domain.ErrConflict)
+	})
+
+	mt.Run("increment-view-count", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		err := a.IncrementViewCount(context.TODO(), 12)
+		assert.NoError(t, err)
+
+		started := mt.GetStartedEvent()
+		inc := started.Command.Lookup("updates", "0", "u", "$inc").Document()
+		assert.Equal(t, int32(1), inc.Lookup("view_count").Int32())
+	})
+
+	mt.Run("increment-view-count-missing", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}))
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		err := a.IncrementViewCount(context.TODO(), 12)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	mt.Run("delete", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		err := a.Delete(context.TODO(), 12)
+		assert.NoError(t, err)
+
+		// soft delete: the document is updated, not removed
+		started := mt.GetStartedEvent()
+		assert.Equal(t, "update", started.CommandName)
+	})
+
+	mt.Run("delete-missing", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}))
+		a := articleMongoRepo.NewArticleRepository(mt.DB)
+
+		err := a.Delete(context.TODO(), 12)
+		assert.Error(t, err)
+	})
+}
+
+func TestAuthorRepository(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("get-by-id", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "article.author", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: int64(1)},
+			{Key: "name", Value: "Iman Tumorang"},
+		}))
+		a := articleMongoRepo.NewAuthorRepository(mt.DB)
+
+		author, err := a.GetByID(context.TODO(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "Iman Tumorang", author.Name)
+	})
+
+	mt.Run("list-with-counts", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "article.author", mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: int64(1)}, {Key: "name", Value: "Iman"}, {Key: "article_count", Value: int32(3)}},
+			bson.D{{Key: "_id", Value: int64(2)}, {Key: "name", Value: "Without Articles"}, {Key: "article_count", Value: int32(0)}}))
+		a := articleMongoRepo.NewAuthorRepository(mt.DB)
+
+		list, err := a.ListAuthorsWithCounts(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.AuthorStat{
+			{Author: domain.Author{ID: 1, Name: "Iman"}, ArticleCount: 3},
+			{Author: domain.Author{ID: 2, Name: "Without Articles"}, ArticleCount: 0},
+		}, list)
+		assert.Equal(t, "author", mt.GetStartedEvent().Command.Lookup("aggregate").StringValue())
+	})
+}
+
+func TestAuditRepository(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("record", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		a := articleMongoRepo.NewAuditRepository(mt.DB)
+
+		before := &domain.Article{ID: 12, Title: "Judul", Status: domain.StatusDraft, Author: domain.Author{ID: 1}}
+		err := a.Record(context.TODO(), domain.AuditEntry{
+			OccurredAt: time.Now(),
+			Actor:      "alice",
+			Operation:  domain.AuditDelete,
+			ArticleID:  12,
+			Before:     before,
+		})
+		assert.NoError(t, err)
+
+		started := mt.GetStartedEvent()
+		assert.Equal(t, "insert", started.CommandName)
+		assert.Equal(t, "audit_log", started.Command.Lookup("insert").StringValue())
+		doc := started.Command.Lookup("documents").Array().Index(0).Value().Document()
+		assert.Equal(t, "alice", doc.Lookup("actor").StringValue())
+		assert.Equal(t, domain.AuditDelete, doc.Lookup("operation").StringValue())
+		assert.Equal(t, "Judul", doc.Lookup("before", "title").StringValue())
+		_, err = doc.LookupErr("after")
+		assert.Error(t, err)
+	})
+}
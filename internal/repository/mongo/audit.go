@@ -0,0 +1,53 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const auditCollection = "audit_log"
+
+// auditDocument is the BSON representation of domain.AuditEntry, the states use the article layout
+type auditDocument struct {
+	OccurredAt time.Time        `bson:"occurred_at"`
+	Actor      string           `bson:"actor"`
+	Operation  string           `bson:"operation"`
+	ArticleID  int64            `bson:"article_id"`
+	Before     *articleDocument `bson:"before,omitempty"`
+	After      *articleDocument `bson:"after,omitempty"`
+}
+
+func auditState(a *domain.Article) *articleDocument {
+	if a == nil {
+		return nil
+	}
+	doc := newArticleDocument(a)
+	doc.DeletedAt = a.DeletedAt
+	return &doc
+}
+
+// AuditRepository appends the audit entries to the audit_log collection, documents are never updated or deleted
+type AuditRepository struct {
+	DB *mongo.Database
+}
+
+// NewAuditRepository will create an implementation of article.AuditLogger
+func NewAuditRepository(db *mongo.Database) *AuditRepository {
+	return &AuditRepository{DB: db}
+}
+
+func (m *AuditRepository) Record(ctx context.Context, entry domain.AuditEntry) error {
+	_, err := m.DB.Collection(auditCollection).InsertOne(ctx, auditDocument{
+		OccurredAt: entry.OccurredAt,
+		Actor:      entry.Actor,
+		Operation:  entry.Operation,
+		ArticleID:  entry.ArticleID,
+		Before:     auditState(entry.Before),
+		After:      auditState(entry.After),
+	})
+	return err
+}
@@ -0,0 +1,89 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const authorCollection = "author"
+
+type authorDocument struct {
+	ID        int64  `bson:"_id"`
+	Name      string `bson:"name"`
+	CreatedAt string `bson:"created_at"`
+	UpdatedAt string `bson:"updated_at"`
+}
+
+// authorStatDocument is an author document with the article_count added by ListAuthorsWithCounts
+type authorStatDocument struct {
+	Author       authorDocument `bson:",inline"`
+	ArticleCount int64          `bson:"article_count"`
+}
+
+func (d authorDocument) toDomain() domain.Author {
+	return domain.Author{
+		ID:        d.ID,
+		Name:      d.Name,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
+
+type AuthorRepository struct {
+	DB *mongo.Database
+}
+
+// NewAuthorRepository will create an implementation of author.Repository
+func NewAuthorRepository(db *mongo.Database) *AuthorRepository {
+	return &AuthorRepository{DB: db}
+}
+
+func (m *AuthorRepository) GetByID(ctx context.Context, id int64) (domain.Author, error) {
+	var doc authorDocument
+	err := m.DB.Collection(authorCollection).FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Author{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Author{}, err
+	}
+	return doc.toDomain(), nil
+}
+
+// ListAuthorsWithCounts joins every author with its articles that are not deleted and counts them
+func (m *AuthorRepository) ListAuthorsWithCounts(ctx context.Context) ([]domain.AuthorStat, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": articleCollection,
+			"let":  bson.M{"author_id": "$_id"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"deleted_at": nil, "$expr": bson.M{"$eq": bson.A{"$author_id", "$$author_id"}}}},
+				bson.M{"$project": bson.M{"_id": 1}},
+			},
+			"as": "articles",
+		}}},
+		{{Key: "$addFields", Value: bson.M{"article_count": bson.M{"$size": "$articles"}}}},
+		{{Key: "$project", Value: bson.M{"articles": 0}}},
+	}
+	cur, err := m.DB.Collection(authorCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	res := make([]domain.AuthorStat, 0)
+	for cur.Next(ctx) {
+		var doc authorStatDocument
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		res = append(res, domain.AuthorStat{Author: doc.Author.toDomain(), ArticleCount: doc.ArticleCount})
+	}
+	return res, cur.Err()
+}
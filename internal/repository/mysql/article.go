@@ -3,24 +3,43 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/tracing"
 	"github.com/bxcodec/go-clean-arch/internal/repository"
 	log "github.com/lingdongomg/g-lib/logger"
 )
 
+// notDeleted hides soft-deleted articles, every query except GetByIDIncludingDeleted and FetchChanges applies it
+const notDeleted = "deleted_at IS NULL"
+
 type ArticleRepository struct {
 	Conn *sql.DB
+	// Replica serves the read-heavy Fetch, GetByID, GetByTitle and FindByTitle, it is Conn when no replica is configured
+	Replica *sql.DB
 }
 
 // NewArticleRepository will create an object that represent the article.Repository interface
 func NewArticleRepository(conn *sql.DB) *ArticleRepository {
-	return &ArticleRepository{conn}
+	return NewArticleRepositoryWithReplica(conn, nil)
+}
+
+// NewArticleRepositoryWithReplica is NewArticleRepository routing Fetch, GetByID, GetByTitle and FindByTitle to a read replica,
+// every other query and all the writes go to primary. A nil replica falls back to primary.
+func NewArticleRepositoryWithReplica(primary, replica *sql.DB) *ArticleRepository {
+	if replica == nil {
+		replica = primary
+	}
+	return &ArticleRepository{Conn: primary, Replica: replica}
 }
 
-func (m *ArticleRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Article, err error) {
-	rows, err := m.Conn.QueryContext(ctx, query, args...)
+func (m *ArticleRepository) fetch(ctx context.Context, conn *sql.DB, query string, args ...interface{}) (result []domain.Article, err error) {
+	rows, err := conn.QueryContext(ctx, query, args...)
 	if err != nil {
 		log.Error("Failed to execute query:", err)
 		return nil, err
@@ -35,55 +54,292 @@ func (m *ArticleRepository) fetch(ctx context.Context, query string, args ...int
 
 	result = make([]domain.Article, 0)
 	for rows.Next() {
-		t := domain.Article{}
-		authorID := int64(0)
-		err = rows.Scan(
-			&t.ID,
-			&t.Title,
-			&t.Content,
-			&authorID,
-			&t.UpdatedAt,
-			&t.CreatedAt,
-		)
-
+		var t domain.Article
+		t, err = scanArticle(rows)
 		if err != nil {
 			log.Error("Failed to scan row:", err)
 			return nil, err
 		}
-		t.Author = domain.Author{
-			ID: authorID,
-		}
 		result = append(result, t)
 	}
 
 	return result, nil
 }
 
-func (m *ArticleRepository) Fetch(ctx context.Context, cursor string, num int64) (res []domain.Article, nextCursor string, err error) {
-	query := `SELECT id,title,content, author_id, updated_at, created_at
-  						FROM article WHERE created_at > ? ORDER BY created_at LIMIT ? `
+func scanArticle(rows *sql.Rows) (domain.Article, error) {
+	t := domain.Article{}
+	authorID := int64(0)
+	err := rows.Scan(
+		&t.ID,
+		&t.Title,
+		&t.Slug,
+		&t.Status,
+		&t.Content,
+		&authorID,
+		&t.UpdatedAt,
+		&t.CreatedAt,
+		(*jsonMetadata)(&t.Metadata),
+		&t.ViewCount,
+		(*tagList)(&t.Tags),
+	)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	t.Author = domain.Author{
+		ID: authorID,
+	}
+	return t, nil
+}
+
+// filterConditions translates filter into WHERE conditions and their arguments
+func filterConditions(filter domain.ArticleFilter) (conds []string, args []interface{}) {
+	if filter.Status != "" {
+		conds = append(conds, "status = ?")
+		args = append(args, string(filter.Status))
+	}
+	if filter.AuthorID != 0 {
+		conds = append(conds, "author_id = ?")
+		args = append(args, filter.AuthorID)
+	}
+	switch {
+	case !filter.CreatedFrom.IsZero() && !filter.CreatedTo.IsZero():
+		conds = append(conds, "created_at BETWEEN ? AND ?")
+		args = append(args, filter.CreatedFrom, filter.CreatedTo)
+	case !filter.CreatedFrom.IsZero():
+		conds = append(conds, "created_at >= ?")
+		args = append(args, filter.CreatedFrom)
+	case !filter.CreatedTo.IsZero():
+		conds = append(conds, "created_at <= ?")
+		args = append(args, filter.CreatedTo)
+	}
+	if len(filter.Tags) > 0 {
+		cond, tagArgs := tagCondition(filter.Tags, filter.TagMatch)
+		conds = append(conds, cond)
+		args = append(args, tagArgs...)
+	}
+	conds = append(conds, notDeleted)
+	return
+}
 
-	decodedCursor, err := repository.DecodeCursor(cursor)
+// tagCondition matches the articles by their rows in article_tag (article_id, tag). With domain.TagMatchAll
+// the article must have a row for every tag, counted with GROUP BY ... HAVING; otherwise one row is enough.
+func tagCondition(tags []string, match domain.TagMatch) (string, []interface{}) {
+	args := make([]interface{}, 0, len(tags)+1)
+	for _, tag := range tags {
+		args = append(args, tag)
+	}
+	in := "tag IN (?" + strings.Repeat(",?", len(tags)-1) + ")"
+	if match == domain.TagMatchAll {
+		args = append(args, len(tags))
+		return "id IN (SELECT article_id FROM article_tag WHERE " + in +
+			" GROUP BY article_id HAVING COUNT(DISTINCT tag) = ?)", args
+	}
+	return "id IN (SELECT article_id FROM article_tag WHERE " + in + ")", args
+}
+
+func (m *ArticleRepository) Fetch(ctx context.Context, cursor string, direction domain.PageDirection, num int64, filter domain.ArticleFilter) (res []domain.Article, page domain.PageCursors, err error) {
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.Fetch")
+	defer func() { tracing.End(span, err) }()
+
+	createdAt, id, err := repository.DecodeCursor(cursor)
+	if err != nil && cursor != "" {
+		return nil, domain.PageCursors{}, domain.ErrBadParamInput
+	}
+
+	// keyset pagination on (created_at, id): rows inserted while a client pages through the list
+	// can neither shift a page nor be returned twice. The previous page is read backwards from the
+	// cursor, without a cursor from the end of the list.
+	conds, args := filterConditions(filter)
+	backward := direction == domain.PagePrev
+	order := "created_at, id"
+	switch {
+	case !backward:
+		conds = append([]string{"(created_at, id) > (?, ?)"}, conds...)
+		args = append([]interface{}{createdAt, id}, args...)
+	case cursor != "":
+		conds = append([]string{"(created_at, id) < (?, ?)"}, conds...)
+		args = append([]interface{}{createdAt, id}, args...)
+		fallthrough
+	default:
+		order = "created_at DESC, id DESC"
+	}
+	query := `SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, ` + tagsColumn + `
+  						FROM article WHERE ` + strings.Join(conds, " AND ") + ` ORDER BY ` + order + ` LIMIT ? `
+
+	res, err = m.fetch(ctx, m.Replica, query, append(args, num)...)
+	if err != nil {
+		return nil, domain.PageCursors{}, err
+	}
+	if backward {
+		slices.Reverse(res)
+	}
+
+	return res, repository.PageCursors(res, cursor, direction, num, func(ar domain.Article) string {
+		return repository.EncodeCursor(ar.CreatedAt, ar.ID)
+	}), nil
+}
+
+// FetchChanges pages by keyset on (updated_at, id) like Fetch does on created_at. It reads from primary:
+// a lagging replica could let a client move its since past changes it has not received yet.
+func (m *ArticleRepository) FetchChanges(ctx context.Context, since time.Time, cursor string, num int64) (res []domain.Article, nextCursor string, err error) {
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.FetchChanges")
+	defer func() { tracing.End(span, err) }()
+
+	updatedAt, id, err := repository.DecodeCursor(cursor)
 	if err != nil && cursor != "" {
 		return nil, "", domain.ErrBadParamInput
 	}
 
-	res, err = m.fetch(ctx, query, decodedCursor, num)
+	query := `SELECT id,title,slug,status,content, author_id, updated_at, created_at, deleted_at, metadata, view_count, ` + tagsColumn + `
+  						FROM article WHERE updated_at > ? AND (updated_at, id) > (?, ?) ORDER BY updated_at, id LIMIT ? `
+	rows, err := m.Conn.QueryContext(ctx, query, since, updatedAt, id, num)
 	if err != nil {
+		log.Error("Failed to execute query:", err)
+		return nil, "", err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			log.Error("Failed to close rows:", errRow)
+		}
+	}()
+
+	res = make([]domain.Article, 0)
+	for rows.Next() {
+		var (
+			t         domain.Article
+			deletedAt sql.NullTime
+		)
+		err = rows.Scan(&t.ID, &t.Title, &t.Slug, &t.Status, &t.Content, &t.Author.ID, &t.UpdatedAt, &t.CreatedAt, &deletedAt,
+			(*jsonMetadata)(&t.Metadata), &t.ViewCount, (*tagList)(&t.Tags))
+		if err != nil {
+			log.Error("Failed to scan row:", err)
+			return nil, "", err
+		}
+		if deletedAt.Valid {
+			t.DeletedAt = &deletedAt.Time
+		}
+		res = append(res, t)
+	}
+	if err = rows.Err(); err != nil {
 		return nil, "", err
 	}
 
 	if len(res) == int(num) {
-		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+		last := res[len(res)-1]
+		nextCursor = repository.EncodeCursor(last.UpdatedAt, last.ID)
+	}
+	return
+}
+
+func (m *ArticleRepository) FetchRecent(ctx context.Context, num int64, filter domain.ArticleFilter) (res []domain.Article, err error) {
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.FetchRecent")
+	defer func() { tracing.End(span, err) }()
+
+	conds, args := filterConditions(filter)
+	query := `SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, ` + tagsColumn + `
+  						FROM article WHERE ` + strings.Join(conds, " AND ") + ` ORDER BY created_at DESC, id DESC LIMIT ? `
+
+	return m.fetch(ctx, m.Replica, query, append(args, num)...)
+}
+
+// FetchAll streams every article ordered by created_at without buffering them in memory.
+// The article channel is closed once the rows are exhausted, any error is sent on the error channel afterwards.
+func (m *ArticleRepository) FetchAll(ctx context.Context) (<-chan domain.Article, <-chan error) {
+	articles := make(chan domain.Article)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(articles)
+
+		query := `SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, ` + tagsColumn + `
+  						FROM article WHERE ` + notDeleted + ` ORDER BY created_at`
+		rows, err := m.Conn.QueryContext(ctx, query)
+		if err != nil {
+			log.Error("Failed to execute query:", err)
+			errs <- err
+			return
+		}
+		defer func() {
+			errRow := rows.Close()
+			if errRow != nil {
+				log.Error("Failed to close rows:", errRow)
+			}
+		}()
+
+		for rows.Next() {
+			t, err := scanArticle(rows)
+			if err != nil {
+				log.Error("Failed to scan row:", err)
+				errs <- err
+				return
+			}
+			select {
+			case articles <- t:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			errs <- err
+		}
+	}()
+
+	return articles, errs
+}
+
+// Count returns the number of articles matching filter
+func (m *ArticleRepository) Count(ctx context.Context, filter domain.ArticleFilter) (total int64, err error) {
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.Count")
+	defer func() { tracing.End(span, err) }()
+
+	conds, args := filterConditions(filter)
+	query := `SELECT COUNT(*) FROM article WHERE ` + strings.Join(conds, " AND ")
+	err = m.Conn.QueryRowContext(ctx, query, args...).Scan(&total)
+	if err != nil {
+		log.Error("Failed to count articles:", err)
+		return 0, err
+	}
+	return
+}
+
+func (m *ArticleRepository) CountByAuthor(ctx context.Context, authorID int64) (total int64, err error) {
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.CountByAuthor")
+	defer func() { tracing.End(span, err) }()
+
+	query := `SELECT COUNT(*) FROM article WHERE author_id = ? AND ` + notDeleted
+	err = m.Conn.QueryRowContext(ctx, query, authorID).Scan(&total)
+	if err != nil {
+		log.Error("Failed to count articles by author:", err)
+		return 0, err
 	}
+	return
+}
+
+// Exists reports whether a non-deleted article has the id, it reads the primary like the writes it precedes
+func (m *ArticleRepository) Exists(ctx context.Context, id int64) (exists bool, err error) {
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.Exists")
+	defer func() { tracing.End(span, err) }()
 
+	query := `SELECT EXISTS(SELECT 1 FROM article WHERE id = ? AND ` + notDeleted + `)`
+	err = m.Conn.QueryRowContext(ctx, query, id).Scan(&exists)
+	if err != nil {
+		log.Error("Failed to check article existence:", err)
+		return false, err
+	}
 	return
 }
+
 func (m *ArticleRepository) GetByID(ctx context.Context, id int64) (res domain.Article, err error) {
-	query := `SELECT id,title,content, author_id, updated_at, created_at
-  						FROM article WHERE ID = ?`
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.GetByID")
+	defer func() { tracing.End(span, err) }()
+
+	query := `SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, ` + tagsColumn + `
+  						FROM article WHERE ID = ? AND ` + notDeleted
 
-	list, err := m.fetch(ctx, query, id)
+	list, err := m.fetch(ctx, m.Replica, query, id)
 	if err != nil {
 		return domain.Article{}, err
 	}
@@ -97,11 +353,68 @@ func (m *ArticleRepository) GetByID(ctx context.Context, id int64) (res domain.A
 	return
 }
 
+// GetByIDIncludingDeleted also returns a soft-deleted article, with DeletedAt set
+func (m *ArticleRepository) GetByIDIncludingDeleted(ctx context.Context, id int64) (res domain.Article, err error) {
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.GetByIDIncludingDeleted")
+	defer func() { tracing.End(span, err) }()
+
+	query := `SELECT id,title,slug,status,content, author_id, updated_at, created_at, deleted_at, metadata, view_count, ` + tagsColumn + `
+  						FROM article WHERE ID = ?`
+
+	var deletedAt sql.NullTime
+	err = m.Conn.QueryRowContext(ctx, query, id).Scan(
+		&res.ID,
+		&res.Title,
+		&res.Slug,
+		&res.Status,
+		&res.Content,
+		&res.Author.ID,
+		&res.UpdatedAt,
+		&res.CreatedAt,
+		&deletedAt,
+		(*jsonMetadata)(&res.Metadata),
+		&res.ViewCount,
+		(*tagList)(&res.Tags),
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Article{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Article{}, err
+	}
+	if deletedAt.Valid {
+		res.DeletedAt = &deletedAt.Time
+	}
+	return
+}
+
+// GetByIDs returns the articles among ids that exist, in id order. Missing ids are simply absent from the result.
+func (m *ArticleRepository) GetByIDs(ctx context.Context, ids []int64) (res []domain.Article, err error) {
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.GetByIDs")
+	defer func() { tracing.End(span, err) }()
+
+	if len(ids) == 0 {
+		return []domain.Article{}, nil
+	}
+	args := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	query := `SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, ` + tagsColumn + `
+  						FROM article WHERE id IN (` + strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",") + `) AND ` + notDeleted + `
+  						ORDER BY id`
+
+	return m.fetch(ctx, m.Conn, query, args...)
+}
+
 func (m *ArticleRepository) GetByTitle(ctx context.Context, title string) (res domain.Article, err error) {
-	query := `SELECT id,title,content, author_id, updated_at, created_at
-  						FROM article WHERE title = ?`
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.GetByTitle")
+	defer func() { tracing.End(span, err) }()
 
-	list, err := m.fetch(ctx, query, title)
+	query := `SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, ` + tagsColumn + `
+  						FROM article WHERE title = ? AND ` + notDeleted
+
+	list, err := m.fetch(ctx, m.Replica, query, title)
 	if err != nil {
 		return
 	}
@@ -114,34 +427,156 @@ func (m *ArticleRepository) GetByTitle(ctx context.Context, title string) (res d
 	return
 }
 
-func (m *ArticleRepository) Store(ctx context.Context, a *domain.Article) (err error) {
-	query := `INSERT  article SET title=? , content=? , author_id=?, updated_at=? , created_at=?`
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+// FindByTitle returns every article titled title in id order, served by the replica like GetByTitle
+func (m *ArticleRepository) FindByTitle(ctx context.Context, title string) (res []domain.Article, err error) {
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.FindByTitle")
+	defer func() { tracing.End(span, err) }()
+
+	query := `SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, ` + tagsColumn + `
+  						FROM article WHERE title = ? AND ` + notDeleted + `
+  						ORDER BY id`
+
+	return m.fetch(ctx, m.Replica, query, title)
+}
+
+func (m *ArticleRepository) GetBySlug(ctx context.Context, slug string) (res domain.Article, err error) {
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.GetBySlug")
+	defer func() { tracing.End(span, err) }()
+
+	query := `SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, ` + tagsColumn + `
+  						FROM article WHERE slug = ? AND ` + notDeleted
+
+	list, err := m.fetch(ctx, m.Conn, query, slug)
 	if err != nil {
 		return
 	}
 
-	res, err := stmt.ExecContext(ctx, a.Title, a.Content, a.Author.ID, a.UpdatedAt, a.CreatedAt)
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+	return
+}
+
+func (m *ArticleRepository) Store(ctx context.Context, a *domain.Article) (err error) {
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.Store")
+	defer func() { tracing.End(span, err) }()
+
+	return m.inTx(ctx, func(tx *sql.Tx) error {
+		return insertArticle(ctx, tx, a)
+	})
+}
+
+// inTx runs fn inside a transaction on the primary, committed when fn succeeds and rolled back otherwise
+func (m *ArticleRepository) inTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	tx, err := m.Conn.BeginTx(ctx, nil)
 	if err != nil {
 		return
 	}
-	lastID, err := res.LastInsertId()
-	if err != nil {
+	if err = fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Error("Failed to rollback article write:", rbErr)
+		}
 		return
 	}
-	a.ID = lastID
-	return
+	return tx.Commit()
+}
+
+// insertStatement is the INSERT of a single article. It only sets the id when the service generated
+// one, otherwise the auto-increment column picks it, and it leaves out the zero timestamps so the
+// column defaults (DEFAULT CURRENT_TIMESTAMP) fill them in.
+func insertStatement(a *domain.Article) (string, []interface{}) {
+	query := `INSERT  article SET title=? , slug=? , status=? , content=? , author_id=?`
+	args := []interface{}{a.Title, a.Slug, string(a.Status), a.Content, a.Author.ID}
+	if !a.UpdatedAt.IsZero() {
+		query += `, updated_at=?`
+		args = append(args, a.UpdatedAt)
+	}
+	if !a.CreatedAt.IsZero() {
+		query += ` , created_at=?`
+		args = append(args, a.CreatedAt)
+	}
+	if len(a.Metadata) > 0 {
+		query += ` , metadata=?`
+		args = append(args, jsonMetadata(a.Metadata))
+	}
+	if a.ID != 0 {
+		query += ` , id=?`
+		args = append(args, a.ID)
+	}
+	return query, args
+}
+
+// insertQuery always sets the timestamps, so its callers must fill them in (the seeder does), and only
+// sets the id column when the id was generated up front, otherwise the auto-increment column picks it
+func insertQuery(withID bool) string {
+	query := `INSERT  article SET title=? , slug=? , status=? , content=? , author_id=?, updated_at=? , created_at=? , metadata=?`
+	if withID {
+		query += ` , id=?`
+	}
+	return query
+}
+
+func insertArgs(a *domain.Article, withID bool) []interface{} {
+	args := []interface{}{a.Title, a.Slug, string(a.Status), a.Content, a.Author.ID, a.UpdatedAt, a.CreatedAt, jsonMetadata(a.Metadata)}
+	if withID {
+		args = append(args, a.ID)
+	}
+	return args
+}
+
+// insertArticle runs the insertStatement of a on exec, adds its tags and reads the stored timestamps back
+func insertArticle(ctx context.Context, exec execer, a *domain.Article) error {
+	query, args := insertStatement(a)
+	res, err := exec.ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateError(err)
+	}
+	if a.ID == 0 {
+		if a.ID, err = res.LastInsertId(); err != nil {
+			return err
+		}
+	}
+	if err = insertTags(ctx, exec, a.ID, a.Tags); err != nil {
+		return err
+	}
+	// the column defaults may have set the timestamps and the column type rounds them, so the
+	// stored values are read back from the primary instead of returning the ones that were sent
+	return exec.QueryRowContext(ctx, `SELECT updated_at, created_at FROM article WHERE id = ?`, a.ID).
+		Scan(&a.UpdatedAt, &a.CreatedAt)
+}
+
+// StoreBatch inserts every article inside one transaction and rolls back on the first failure.
+// Like Store, the timestamps an article leaves zero are filled in by the column defaults.
+func (m *ArticleRepository) StoreBatch(ctx context.Context, articles []*domain.Article) (err error) {
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.StoreBatch")
+	defer func() { tracing.End(span, err) }()
+
+	return m.inTx(ctx, func(tx *sql.Tx) error {
+		for _, a := range articles {
+			if err := insertArticle(ctx, tx, a); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
+// Delete soft-deletes the article, it also bumps updated_at so incremental readers notice the deletion
 func (m *ArticleRepository) Delete(ctx context.Context, id int64) (err error) {
-	query := "DELETE FROM article WHERE id = ?"
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.Delete")
+	defer func() { tracing.End(span, err) }()
+
+	query := "UPDATE article SET deleted_at = ?, updated_at = ? WHERE id = ? AND " + notDeleted
 
 	stmt, err := m.Conn.PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
 
-	res, err := stmt.ExecContext(ctx, id)
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx, now, now, id)
 	if err != nil {
 		return
 	}
@@ -158,15 +593,129 @@ func (m *ArticleRepository) Delete(ctx context.Context, id int64) (err error) {
 
 	return
 }
+
+// IncrementViewCount adds one view in a single statement so that concurrent views are never lost to a
+// read-modify-write race. updated_at is left alone, a view does not change the article.
+func (m *ArticleRepository) IncrementViewCount(ctx context.Context, id int64) (err error) {
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.IncrementViewCount")
+	defer func() { tracing.End(span, err) }()
+
+	query := `UPDATE article SET view_count = view_count + 1 WHERE id = ? AND ` + notDeleted
+	res, err := m.Conn.ExecContext(ctx, query, id)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		return domain.ErrNotFound
+	}
+	return
+}
+
+func (m *ArticleRepository) GetPrevious(ctx context.Context, ar domain.Article, filter domain.ArticleFilter) (res domain.Article, err error) {
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.GetPrevious")
+	defer func() { tracing.End(span, err) }()
+
+	return m.getNeighbor(ctx, ar, filter, "<", "DESC")
+}
+
+func (m *ArticleRepository) GetNext(ctx context.Context, ar domain.Article, filter domain.ArticleFilter) (res domain.Article, err error) {
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.GetNext")
+	defer func() { tracing.End(span, err) }()
+
+	return m.getNeighbor(ctx, ar, filter, ">", "ASC")
+}
+
+// getNeighbor finds the closest article in created_at order, with id breaking ties between equal timestamps
+func (m *ArticleRepository) getNeighbor(ctx context.Context, ar domain.Article, filter domain.ArticleFilter, cmp, order string) (domain.Article, error) {
+	conds, args := filterConditions(filter)
+	conds = append([]string{"(created_at " + cmp + " ? OR (created_at = ? AND id " + cmp + " ?))"}, conds...)
+	args = append([]interface{}{ar.CreatedAt, ar.CreatedAt, ar.ID}, args...)
+	query := `SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, ` + tagsColumn + `
+  						FROM article WHERE ` + strings.Join(conds, " AND ") + ` ORDER BY created_at ` + order + `, id ` + order + ` LIMIT 1`
+
+	list, err := m.fetch(ctx, m.Conn, query, args...)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	if len(list) == 0 {
+		return domain.Article{}, domain.ErrNotFound
+	}
+	return list[0], nil
+}
+
 func (m *ArticleRepository) Update(ctx context.Context, ar *domain.Article) (err error) {
-	query := `UPDATE article set title=?, content=?, author_id=?, updated_at=? WHERE ID = ?`
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.Update")
+	defer func() { tracing.End(span, err) }()
+
+	query := `UPDATE article set title=?, content=?, author_id=?, metadata=?, updated_at=? WHERE ID = ? AND ` + notDeleted
+
+	return m.inTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, ar.Title, ar.Content, ar.Author.ID, jsonMetadata(ar.Metadata), ar.UpdatedAt, ar.ID)
+		if err != nil {
+			return translateError(err)
+		}
+		affect, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affect != 1 {
+			return fmt.Errorf("weird  Behavior. Total Affected: %d", affect)
+		}
+		return replaceTags(ctx, tx, ar.ID, ar.Tags)
+	})
+}
+
+// Upsert locks the row at ar.ID and then inserts or updates it inside one transaction. A plain INSERT or
+// UPDATE is used rather than ON DUPLICATE KEY UPDATE, which would also fire on the live slug key and
+// overwrite the article holding that slug. A slug taken by another live article fails with domain.ErrConflict.
+func (m *ArticleRepository) Upsert(ctx context.Context, ar *domain.Article) (created bool, err error) {
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.Upsert")
+	defer func() { tracing.End(span, err) }()
+
+	err = m.inTx(ctx, func(tx *sql.Tx) error {
+		// a missing id is gap locked as well, so a concurrent Upsert of the same id waits for this one
+		var id int64
+		err := tx.QueryRowContext(ctx, `SELECT id FROM article WHERE id = ? FOR UPDATE`, ar.ID).Scan(&id)
+		created = errors.Is(err, sql.ErrNoRows)
+		if err != nil && !created {
+			return err
+		}
+
+		if created {
+			_, err = tx.ExecContext(ctx, `INSERT article (id, title, slug, status, content, author_id, updated_at, created_at, metadata) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				ar.ID, ar.Title, ar.Slug, string(ar.Status), ar.Content, ar.Author.ID, ar.UpdatedAt, ar.CreatedAt, jsonMetadata(ar.Metadata))
+		} else {
+			_, err = tx.ExecContext(ctx, `UPDATE article SET title=?, content=?, author_id=?, metadata=?, updated_at=?, deleted_at=NULL WHERE id = ?`,
+				ar.Title, ar.Content, ar.Author.ID, jsonMetadata(ar.Metadata), ar.UpdatedAt, ar.ID)
+		}
+		if err != nil {
+			return translateError(err)
+		}
+		return replaceTags(ctx, tx, ar.ID, ar.Tags)
+	})
+	if err != nil {
+		return false, err
+	}
+	return created, nil
+}
+
+// UpdateStatus is a compare-and-set on the status column, so concurrent transitions cannot both succeed
+func (m *ArticleRepository) UpdateStatus(ctx context.Context, ar *domain.Article, from domain.ArticleStatus) (err error) {
+	ctx, span := tracing.Start(ctx, "mysql.ArticleRepository.UpdateStatus")
+	defer func() { tracing.End(span, err) }()
+
+	query := `UPDATE article set status=?, updated_at=? WHERE ID = ? AND status = ? AND ` + notDeleted
 
 	stmt, err := m.Conn.PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
 
-	res, err := stmt.ExecContext(ctx, ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt, ar.ID)
+	res, err := stmt.ExecContext(ctx, string(ar.Status), ar.UpdatedAt, ar.ID, string(from))
 	if err != nil {
 		return
 	}
@@ -175,9 +724,7 @@ func (m *ArticleRepository) Update(ctx context.Context, ar *domain.Article) (err
 		return
 	}
 	if affect != 1 {
-		err = fmt.Errorf("weird  Behavior. Total Affected: %d", affect)
-		return
+		return domain.ErrConflict
 	}
-
 	return
 }
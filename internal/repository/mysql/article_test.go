@@ -2,10 +2,14 @@ package mysql_test
 
 import (
 	"context"
+	"database/sql/driver"
+	"sync"
 	"testing"
 	"time"
 
+	mysqlDriver "github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
 
 	"github.com/bxcodec/go-clean-arch/domain"
@@ -13,6 +17,9 @@ import (
 	articleMysqlRepo "github.com/bxcodec/go-clean-arch/internal/repository/mysql"
 )
 
+// tagsSelect matches the tags column every article query selects
+const tagsSelect = "\\(SELECT GROUP_CONCAT\\(tag ORDER BY tag SEPARATOR ','\\) FROM article_tag WHERE article_tag.article_id = article.id\\) AS tags"
+
 func TestFetchArticle(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -21,43 +28,273 @@ func TestFetchArticle(t *testing.T) {
 
 	mockArticles := []domain.Article{
 		{
-			ID: 1, Title: "title 1", Content: "content 1",
+			ID: 1, Title: "title 1", Slug: "title-1", Status: domain.StatusPublished, Content: "content 1",
 			Author: domain.Author{ID: 1}, UpdatedAt: time.Now(), CreatedAt: time.Now(),
 		},
 		{
-			ID: 2, Title: "title 2", Content: "content 2",
+			ID: 2, Title: "title 2", Slug: "title-2", Status: domain.StatusPublished, Content: "content 2",
 			Author: domain.Author{ID: 1}, UpdatedAt: time.Now(), CreatedAt: time.Now(),
 		},
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
-		AddRow(mockArticles[0].ID, mockArticles[0].Title, mockArticles[0].Content,
-			mockArticles[0].Author.ID, mockArticles[0].UpdatedAt, mockArticles[0].CreatedAt).
-		AddRow(mockArticles[1].ID, mockArticles[1].Title, mockArticles[1].Content,
-			mockArticles[1].Author.ID, mockArticles[1].UpdatedAt, mockArticles[1].CreatedAt)
+	rows := sqlmock.NewRows([]string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}).
+		AddRow(mockArticles[0].ID, mockArticles[0].Title, mockArticles[0].Slug, mockArticles[0].Status, mockArticles[0].Content,
+			mockArticles[0].Author.ID, mockArticles[0].UpdatedAt, mockArticles[0].CreatedAt, nil, 0, nil).
+		AddRow(mockArticles[1].ID, mockArticles[1].Title, mockArticles[1].Slug, mockArticles[1].Status, mockArticles[1].Content,
+			mockArticles[1].Author.ID, mockArticles[1].UpdatedAt, mockArticles[1].CreatedAt, nil, 0, nil)
 
-	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article WHERE created_at > \\? ORDER BY created_at LIMIT \\?"
+	query := "SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, " + tagsSelect + " FROM article WHERE \\(created_at, id\\) > \\(\\?, \\?\\) AND status = \\? AND deleted_at IS NULL ORDER BY created_at, id LIMIT \\?"
 
-	mock.ExpectQuery(query).WillReturnRows(rows)
-	a := articleMysqlRepo.NewArticleRepository(db)
-	cursor := repository.EncodeCursor(mockArticles[1].CreatedAt)
+	cursor := repository.EncodeCursor(mockArticles[1].CreatedAt, mockArticles[1].ID)
 	num := int64(2)
-	list, nextCursor, err := a.Fetch(context.TODO(), cursor, num)
-	assert.NotEmpty(t, nextCursor)
+	mock.ExpectQuery(query).WithArgs(sqlmock.AnyArg(), mockArticles[1].ID, "published", num).WillReturnRows(rows)
+	a := articleMysqlRepo.NewArticleRepository(db)
+	list, page, err := a.Fetch(context.TODO(), cursor, domain.PageNext, num, domain.ArticleFilter{Status: domain.StatusPublished})
+	assert.NotEmpty(t, page.Next)
+	assert.Equal(t, repository.EncodeCursor(mockArticles[0].CreatedAt, mockArticles[0].ID), page.Prev)
 	assert.NoError(t, err)
 	assert.Len(t, list, 2)
 }
 
+func TestFetchArticleBackward(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	a := articleMysqlRepo.NewArticleRepository(db)
+	columns := []string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}
+	t1 := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	t2, t3 := t1.Add(time.Minute), t1.Add(2*time.Minute)
+
+	t.Run("from-cursor", func(t *testing.T) {
+		// the page before (t3, 3) is read in descending order and returned in ascending order
+		query := "SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, " + tagsSelect + " FROM article WHERE \\(created_at, id\\) < \\(\\?, \\?\\) AND status = \\? AND deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT \\?"
+		mock.ExpectQuery(query).WithArgs(t3, int64(3), "published", int64(2)).WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(2, "title 2", "title-2", "published", "Content 2", 1, t2, t2, nil, 0, nil).
+			AddRow(1, "title 1", "title-1", "published", "Content 1", 1, t1, t1, nil, 0, nil))
+
+		list, page, err := a.Fetch(context.TODO(), repository.EncodeCursor(t3, 3), domain.PagePrev, 2, domain.ArticleFilter{Status: domain.StatusPublished})
+		require.NoError(t, err)
+		if assert.Len(t, list, 2) {
+			assert.Equal(t, int64(1), list[0].ID)
+			assert.Equal(t, int64(2), list[1].ID)
+		}
+		assert.Equal(t, repository.EncodeCursor(t1, 1), page.Prev)
+		assert.Equal(t, repository.EncodeCursor(t2, 2), page.Next)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("first-page-reached", func(t *testing.T) {
+		query := "FROM article WHERE \\(created_at, id\\) < \\(\\?, \\?\\) AND deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT \\?"
+		mock.ExpectQuery(query).WithArgs(t2, int64(2), int64(2)).WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "title 1", "title-1", "published", "Content 1", 1, t1, t1, nil, 0, nil))
+
+		list, page, err := a.Fetch(context.TODO(), repository.EncodeCursor(t2, 2), domain.PagePrev, 2, domain.ArticleFilter{})
+		require.NoError(t, err)
+		assert.Len(t, list, 1)
+		assert.Empty(t, page.Prev)
+		assert.Equal(t, repository.EncodeCursor(t1, 1), page.Next)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("without-cursor-reads-last-page", func(t *testing.T) {
+		query := "FROM article WHERE deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT \\?"
+		mock.ExpectQuery(query).WithArgs(int64(2)).WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(3, "title 3", "title-3", "published", "Content 3", 1, t3, t3, nil, 0, nil).
+			AddRow(2, "title 2", "title-2", "published", "Content 2", 1, t2, t2, nil, 0, nil))
+
+		list, page, err := a.Fetch(context.TODO(), "", domain.PagePrev, 2, domain.ArticleFilter{})
+		require.NoError(t, err)
+		if assert.Len(t, list, 2) {
+			assert.Equal(t, int64(2), list[0].ID)
+		}
+		assert.Equal(t, repository.EncodeCursor(t2, 2), page.Prev)
+		assert.Empty(t, page.Next)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFetchArticleChanges(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	columns := []string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "deleted_at", "metadata", "view_count", "tags"}
+	query := "SELECT id,title,slug,status,content, author_id, updated_at, created_at, deleted_at, metadata, view_count, " + tagsSelect + " FROM article WHERE updated_at > \\? AND \\(updated_at, id\\) > \\(\\?, \\?\\) ORDER BY updated_at, id LIMIT \\?"
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := since.Add(time.Hour)
+	t2 := since.Add(2 * time.Hour)
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	// the soft-deleted article is returned too, flagged by its deleted_at
+	mock.ExpectQuery(query).WithArgs(since, time.Time{}, int64(0), int64(2)).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(3, "title 3", "title-3", "published", "content 3", 1, t1, since, nil, nil, 0, nil).
+		AddRow(1, "title 1", "title-1", "published", "content 1", 1, t2, since, t2, nil, 0, nil))
+	list, nextCursor, err := a.FetchChanges(context.TODO(), since, "", 2)
+	require.NoError(t, err)
+	if assert.Len(t, list, 2) {
+		assert.Equal(t, int64(3), list[0].ID)
+		assert.Nil(t, list[0].DeletedAt)
+		assert.Equal(t, int64(1), list[1].ID)
+		if assert.NotNil(t, list[1].DeletedAt) {
+			assert.True(t, t2.Equal(*list[1].DeletedAt))
+		}
+	}
+	assert.Equal(t, repository.EncodeCursor(t2, 1), nextCursor)
+
+	// the next page continues after (updated_at, id) of the last change
+	mock.ExpectQuery(query).WithArgs(since, t2, int64(1), int64(2)).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(2, "title 2", "title-2", "draft", "content 2", 1, t2, since, nil, nil, 0, nil))
+	list, nextCursor, err = a.FetchChanges(context.TODO(), since, nextCursor, 2)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Empty(t, nextCursor)
+
+	_, _, err = a.FetchChanges(context.TODO(), since, "not-a-cursor", 2)
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchArticleConcurrentInsert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	columns := []string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}
+	query := "SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, " + tagsSelect + " FROM article WHERE \\(created_at, id\\) > \\(\\?, \\?\\) AND deleted_at IS NULL ORDER BY created_at, id LIMIT \\?"
+	t1 := time.Date(2024, 1, 2, 3, 4, 5, 123456000, time.UTC)
+	t2 := t1.Add(time.Microsecond)
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	mock.ExpectQuery(query).WithArgs(time.Time{}, int64(0), int64(2)).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(1, "title 1", "title-1", "published", "Content 1", 1, t1, t1, nil, 0, nil).
+		AddRow(2, "title 2", "title-2", "published", "Content 2", 1, t2, t2, nil, 0, nil))
+	first, cursor, err := a.Fetch(context.TODO(), "", domain.PageNext, 2, domain.ArticleFilter{})
+	assert.NoError(t, err)
+
+	// article 3 is inserted between the two pages with the same created_at as the last row of the
+	// first page; the second page continues right after (t2, 2) so it is returned exactly once
+	mock.ExpectQuery(query).WithArgs(t2, int64(2), int64(2)).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(3, "title 3", "title-3", "published", "Content 3", 1, t2, t2, nil, 0, nil))
+	second, page, err := a.Fetch(context.TODO(), cursor.Next, domain.PageNext, 2, domain.ArticleFilter{})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Next)
+
+	seen := map[int64]bool{}
+	for _, ar := range append(first, second...) {
+		assert.False(t, seen[ar.ID], "article %d returned twice", ar.ID)
+		seen[ar.ID] = true
+	}
+	assert.Len(t, seen, 3)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC)
+	createdAt, id, err := repository.DecodeCursor(repository.EncodeCursor(created, 42))
+	assert.NoError(t, err)
+	assert.True(t, created.Equal(createdAt), "the cursor keeps the full precision")
+	assert.Equal(t, int64(42), id)
+
+	_, _, err = repository.DecodeCursor("not-a-cursor")
+	assert.Error(t, err)
+}
+
+func TestFetchAllArticle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}).
+		AddRow(1, "title 1", "title-1", "published", "Content 1", 1, time.Now(), time.Now(), nil, 0, nil).
+		AddRow(2, "title 2", "title-2", "published", "Content 2", 1, time.Now(), time.Now(), nil, 0, nil).
+		AddRow(3, "title 3", "title-3", "published", "Content 3", 2, time.Now(), time.Now(), nil, 0, nil)
+
+	query := "SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, " + tagsSelect + " FROM article WHERE deleted_at IS NULL ORDER BY created_at"
+
+	mock.ExpectQuery(query).WillReturnRows(rows)
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	articles, errs := a.FetchAll(context.TODO())
+	count := 0
+	for ar := range articles {
+		count++
+		assert.Equal(t, int64(count), ar.ID)
+	}
+	assert.NoError(t, <-errs)
+	assert.Equal(t, 3, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountArticle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"count"}).AddRow(42)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM article WHERE deleted_at IS NULL$").WillReturnRows(rows)
+
+	a := articleMysqlRepo.NewArticleRepository(db)
+	total, err := a.Count(context.TODO(), domain.ArticleFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), total)
+
+	rows = sqlmock.NewRows([]string{"count"}).AddRow(7)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM article WHERE status = \\?").WithArgs("draft").WillReturnRows(rows)
+	total, err = a.Count(context.TODO(), domain.ArticleFilter{Status: domain.StatusDraft})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountArticleByAuthor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"count"}).AddRow(3)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM article WHERE author_id = \\?").WithArgs(int64(1)).WillReturnRows(rows)
+
+	a := articleMysqlRepo.NewArticleRepository(db)
+	total, err := a.CountByAuthor(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArticleExists(t *testing.T) {
+	for name, want := range map[string]bool{"exists": true, "missing": false} {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+
+			rows := sqlmock.NewRows([]string{"exists"}).AddRow(want)
+			query := "SELECT EXISTS\\(SELECT 1 FROM article WHERE id = \\? AND deleted_at IS NULL\\)$"
+			mock.ExpectQuery(query).WithArgs(int64(5)).WillReturnRows(rows)
+
+			a := articleMysqlRepo.NewArticleRepository(db)
+			exists, err := a.Exists(context.TODO(), 5)
+			assert.NoError(t, err)
+			assert.Equal(t, want, exists)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestGetArticleByID(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
-		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now())
+	rows := sqlmock.NewRows([]string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}).
+		AddRow(1, "title 1", "title-1", "published", "Content 1", 1, time.Now(), time.Now(), nil, 0, []byte("go,web"))
 
-	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article WHERE ID = \\?"
+	query := "SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, " + tagsSelect + " FROM article WHERE ID = \\? AND deleted_at IS NULL$"
 
 	mock.ExpectQuery(query).WillReturnRows(rows)
 	a := articleMysqlRepo.NewArticleRepository(db)
@@ -66,13 +303,193 @@ func TestGetArticleByID(t *testing.T) {
 	anArticle, err := a.GetByID(context.TODO(), num)
 	assert.NoError(t, err)
 	assert.NotNil(t, anArticle)
+	assert.Equal(t, []string{"go", "web"}, anArticle.Tags)
+}
+
+func TestArticleMetadata(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	columns := []string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}
+	query := "SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, " + tagsSelect + " FROM article WHERE ID = \\?"
+	mock.ExpectQuery(query).WithArgs(int64(1)).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(1, "title 1", "title-1", "published", "Content 1", 1, time.Now(), time.Now(), []byte(`{"cover":"cover.png","seo":{"keywords":["go"]}}`), 0, nil))
+
+	ar, err := a.GetByID(context.TODO(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"cover": "cover.png", "seo": map[string]any{"keywords": []any{"go"}}}, ar.Metadata)
+
+	// the column is written back as a JSON document
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE article set title=\\?, content=\\?, author_id=\\?, metadata=\\?, updated_at=\\? WHERE ID = \\?").
+		WithArgs(ar.Title, ar.Content, ar.Author.ID, `{"cover":"cover.png","seo":{"keywords":["go"]}}`, ar.UpdatedAt, ar.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE FROM article_tag WHERE article_id = \\?").WithArgs(ar.ID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	require.NoError(t, a.Update(context.TODO(), &ar))
+
+	mock.ExpectQuery(query).WithArgs(int64(2)).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(2, "title 2", "title-2", "published", "Content 2", 1, time.Now(), time.Now(), "not json", 0, nil))
+	_, err = a.GetByID(context.TODO(), 2)
+	assert.ErrorContains(t, err, "decode metadata")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticleByIDIncludingDeleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	columns := []string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "deleted_at", "metadata", "view_count", "tags"}
+	deletedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	// the deleted_at filter is omitted so soft-deleted rows are returned too
+	query := "SELECT id,title,slug,status,content, author_id, updated_at, created_at, deleted_at, metadata, view_count, " + tagsSelect + " FROM article WHERE ID = \\?$"
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	mock.ExpectQuery(query).WithArgs(int64(5)).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(5, "title 5", "title-5", "published", "Content 5", 1, time.Now(), time.Now(), deletedAt, nil, 0, nil))
+	ar, err := a.GetByIDIncludingDeleted(context.TODO(), 5)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), ar.ID)
+	if assert.NotNil(t, ar.DeletedAt) {
+		assert.Equal(t, deletedAt, *ar.DeletedAt)
+	}
+
+	mock.ExpectQuery(query).WithArgs(int64(6)).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(6, "title 6", "title-6", "published", "Content 6", 1, time.Now(), time.Now(), nil, nil, 0, nil))
+	ar, err = a.GetByIDIncludingDeleted(context.TODO(), 6)
+	assert.NoError(t, err)
+	assert.Nil(t, ar.DeletedAt)
+
+	mock.ExpectQuery(query).WithArgs(int64(7)).WillReturnRows(sqlmock.NewRows(columns))
+	_, err = a.GetByIDIncludingDeleted(context.TODO(), 7)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticlesByIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	// 999 does not exist, the partial result only holds the rows found
+	rows := sqlmock.NewRows([]string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}).
+		AddRow(1, "title 1", "title-1", "published", "Content 1", 1, time.Now(), time.Now(), nil, 0, nil).
+		AddRow(2, "title 2", "title-2", "published", "Content 2", 1, time.Now(), time.Now(), nil, 0, nil)
+	query := "SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, " + tagsSelect + " FROM article " +
+		"WHERE id IN \\(\\?,\\?,\\?\\) AND deleted_at IS NULL ORDER BY id$"
+	mock.ExpectQuery(query).WithArgs(int64(1), int64(2), int64(999)).WillReturnRows(rows)
+
+	list, err := a.GetByIDs(context.TODO(), []int64{1, 2, 999})
+	assert.NoError(t, err)
+	if assert.Len(t, list, 2) {
+		assert.Equal(t, int64(1), list[0].ID)
+		assert.Equal(t, int64(2), list[1].ID)
+	}
+
+	// no ids, no query
+	list, err = a.GetByIDs(context.TODO(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchRecentArticles(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}).
+		AddRow(2, "title 2", "title-2", "published", "Content 2", 1, time.Now(), time.Now(), nil, 0, nil).
+		AddRow(1, "title 1", "title-1", "published", "Content 1", 1, time.Now(), time.Now().Add(-time.Hour), nil, 0, nil)
+	query := "SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, " + tagsSelect + " FROM article " +
+		"WHERE status = \\? AND deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT \\?"
+	mock.ExpectQuery(query).WithArgs("published", int64(2)).WillReturnRows(rows)
+
+	list, err := a.FetchRecent(context.TODO(), 2, domain.ArticleFilter{Status: domain.StatusPublished})
+	assert.NoError(t, err)
+	if assert.Len(t, list, 2) {
+		assert.Equal(t, int64(2), list[0].ID)
+		assert.Equal(t, int64(1), list[1].ID)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArticleRepositoryReplica(t *testing.T) {
+	primary, primaryMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	replica, replicaMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	columns := []string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}
+	newRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows(columns).AddRow(1, "title 1", "title-1", "published", "Content 1", 1, time.Now(), time.Now(), nil, 0, nil)
+	}
+	a := articleMysqlRepo.NewArticleRepositoryWithReplica(primary, replica)
+
+	// reads hit the replica only
+	replicaMock.ExpectQuery("FROM article WHERE \\(created_at, id\\) > ").WillReturnRows(newRows())
+	replicaMock.ExpectQuery("FROM article WHERE ID = \\?").WithArgs(int64(1)).WillReturnRows(newRows())
+	replicaMock.ExpectQuery("FROM article WHERE title = \\?").WithArgs("title 1").WillReturnRows(newRows())
+
+	_, _, err = a.Fetch(context.TODO(), "", domain.PageNext, 10, domain.ArticleFilter{})
+	assert.NoError(t, err)
+	_, err = a.GetByID(context.TODO(), 1)
+	assert.NoError(t, err)
+	_, err = a.GetByTitle(context.TODO(), "title 1")
+	assert.NoError(t, err)
+
+	// writes hit the primary only
+	primaryMock.ExpectBegin()
+	primaryMock.ExpectExec("INSERT  article SET").WillReturnResult(sqlmock.NewResult(2, 1))
+	primaryMock.ExpectQuery("SELECT updated_at, created_at FROM article WHERE id = \\?").WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at", "created_at"}).AddRow(time.Now(), time.Now()))
+	primaryMock.ExpectCommit()
+	primaryMock.ExpectPrepare("UPDATE article SET deleted_at = \\?").ExpectExec().WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = a.Store(context.TODO(), &domain.Article{Title: "title 2", Content: "Content 2"})
+	assert.NoError(t, err)
+	err = a.Delete(context.TODO(), 2)
+	assert.NoError(t, err)
+
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+}
+
+func TestArticleRepositoryWithoutReplica(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	a := articleMysqlRepo.NewArticleRepositoryWithReplica(db, nil)
+	assert.Same(t, db, a.Replica)
+
+	mock.ExpectQuery("FROM article WHERE ID = \\?").WithArgs(int64(1)).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}).
+			AddRow(1, "title 1", "title-1", "published", "Content 1", 1, time.Now(), time.Now(), nil, 0, nil))
+	_, err = a.GetByID(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestStoreArticle(t *testing.T) {
 	now := time.Now()
 	ar := &domain.Article{
 		Title:     "Judul",
+		Slug:      "judul",
+		Status:    domain.StatusDraft,
 		Content:   "Content",
+		Tags:      []string{"go", "web"},
 		CreatedAt: now,
 		UpdatedAt: now,
 		Author: domain.Author{
@@ -85,15 +502,225 @@ func TestStoreArticle(t *testing.T) {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	query := "INSERT  article SET title=\\? , content=\\? , author_id=\\?, updated_at=\\? , created_at=\\?"
-	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, ar.CreatedAt, ar.UpdatedAt).WillReturnResult(sqlmock.NewResult(12, 1))
+	query := "INSERT  article SET title=\\? , slug=\\? , status=\\? , content=\\? , author_id=\\?, updated_at=\\? , created_at=\\?"
+	mock.ExpectBegin()
+	mock.ExpectExec(query).WithArgs(ar.Title, ar.Slug, string(ar.Status), ar.Content, ar.Author.ID, ar.CreatedAt, ar.UpdatedAt).WillReturnResult(sqlmock.NewResult(12, 1))
+	mock.ExpectExec("^INSERT article_tag \\(article_id, tag\\) VALUES \\(\\?, \\?\\), \\(\\?, \\?\\)$").
+		WithArgs(int64(12), "go", int64(12), "web").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("SELECT updated_at, created_at FROM article WHERE id = \\?").WithArgs(int64(12)).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at", "created_at"}).AddRow(now, now))
+	mock.ExpectCommit()
 
 	a := articleMysqlRepo.NewArticleRepository(db)
 
 	err = a.Store(context.TODO(), ar)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(12), ar.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreArticleMetadata(t *testing.T) {
+	ar := &domain.Article{Title: "Judul", Slug: "judul", Status: domain.StatusDraft, Content: "Content",
+		Metadata: map[string]any{"cover": "cover.png"}}
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	query := "INSERT  article SET title=\\? , slug=\\? , status=\\? , content=\\? , author_id=\\? , metadata=\\?$"
+	mock.ExpectBegin()
+	mock.ExpectExec(query).WithArgs(ar.Title, ar.Slug, string(ar.Status), ar.Content, ar.Author.ID, `{"cover":"cover.png"}`).
+		WillReturnResult(sqlmock.NewResult(12, 1))
+	mock.ExpectQuery("SELECT updated_at, created_at FROM article WHERE id = \\?").WithArgs(int64(12)).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at", "created_at"}).AddRow(time.Now(), time.Now()))
+	mock.ExpectCommit()
+
+	a := articleMysqlRepo.NewArticleRepository(db)
+	require.NoError(t, a.Store(context.TODO(), ar))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreArticleDatabaseTimestamps(t *testing.T) {
+	ar := &domain.Article{
+		Title:   "Judul",
+		Slug:    "judul",
+		Status:  domain.StatusDraft,
+		Content: "Content",
+		Author:  domain.Author{ID: 1},
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	// the timestamps are left to DEFAULT CURRENT_TIMESTAMP and read back after the insert
+	created := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	updated := created.Add(time.Second)
+	query := "INSERT  article SET title=\\? , slug=\\? , status=\\? , content=\\? , author_id=\\?$"
+	mock.ExpectBegin()
+	mock.ExpectExec(query).WithArgs(ar.Title, ar.Slug, string(ar.Status), ar.Content, ar.Author.ID).
+		WillReturnResult(sqlmock.NewResult(12, 1))
+	mock.ExpectQuery("SELECT updated_at, created_at FROM article WHERE id = \\?").WithArgs(int64(12)).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at", "created_at"}).AddRow(updated, created))
+	mock.ExpectCommit()
+
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	err = a.Store(context.TODO(), ar)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), ar.ID)
+	assert.Equal(t, created, ar.CreatedAt)
+	assert.Equal(t, updated, ar.UpdatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreArticleGeneratedID(t *testing.T) {
+	now := time.Now()
+	ar := &domain.Article{
+		ID:        1234567890123,
+		Title:     "Judul",
+		Slug:      "judul",
+		Status:    domain.StatusDraft,
+		Content:   "Content",
+		CreatedAt: now,
+		UpdatedAt: now,
+		Author:    domain.Author{ID: 1},
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT  article SET title=\\? , slug=\\? , status=\\? , content=\\? , author_id=\\?, updated_at=\\? , created_at=\\? , id=\\?"
+	mock.ExpectBegin()
+	mock.ExpectExec(query).WithArgs(ar.Title, ar.Slug, string(ar.Status), ar.Content, ar.Author.ID, ar.UpdatedAt, ar.CreatedAt, ar.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT updated_at, created_at FROM article WHERE id = \\?").WithArgs(ar.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at", "created_at"}).AddRow(now, now))
+	mock.ExpectCommit()
+
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	err = a.Store(context.TODO(), ar)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1234567890123), ar.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// nonZeroTime matches a time.Time argument that is set, MySQL's strict mode rejects the zero date
+type nonZeroTime struct{}
+
+func (nonZeroTime) Match(v driver.Value) bool {
+	t, ok := v.(time.Time)
+	return ok && !t.IsZero()
+}
+
+func TestStoreArticleBatch(t *testing.T) {
+	// the zero timestamps of imported articles are left to the column defaults
+	query := "^INSERT  article SET title=\\? , slug=\\? , status=\\? , content=\\? , author_id=\\?$"
+	readBack := "SELECT updated_at, created_at FROM article WHERE id = \\?"
+	newBatch := func() []*domain.Article {
+		return []*domain.Article{
+			{Title: "First", Slug: "first", Status: domain.StatusDraft, Content: "Content"},
+			{Title: "Second", Slug: "second", Status: domain.StatusDraft, Content: "Content"},
+		}
+	}
+
+	t.Run("commit", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs("First", "first", "draft", "Content", int64(0)).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery(readBack).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"updated_at", "created_at"}).AddRow(created, created))
+		mock.ExpectExec(query).WithArgs("Second", "second", "draft", "Content", int64(0)).WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectQuery(readBack).WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"updated_at", "created_at"}).AddRow(created, created))
+		mock.ExpectCommit()
+
+		a := articleMysqlRepo.NewArticleRepository(db)
+		batch := newBatch()
+		err = a.StoreBatch(context.TODO(), batch)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), batch[0].ID)
+		assert.Equal(t, int64(2), batch[1].ID)
+		for _, ar := range batch {
+			assert.Equal(t, created, ar.CreatedAt)
+			assert.Equal(t, created, ar.UpdatedAt)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("given-timestamps-and-ids", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		now := time.Now()
+		mock.ExpectBegin()
+		mock.ExpectExec("^INSERT  article SET title=\\? , slug=\\? , status=\\? , content=\\? , author_id=\\?, updated_at=\\? , created_at=\\? , id=\\?$").
+			WithArgs("First", "first", "draft", "Content", int64(0), nonZeroTime{}, nonZeroTime{}, int64(7)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(readBack).WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"updated_at", "created_at"}).AddRow(now, now))
+		mock.ExpectCommit()
+
+		a := articleMysqlRepo.NewArticleRepository(db)
+		batch := newBatch()[:1]
+		batch[0].ID, batch[0].CreatedAt, batch[0].UpdatedAt = 7, now, now
+		require.NoError(t, a.StoreBatch(context.TODO(), batch))
+		assert.Equal(t, int64(7), batch[0].ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs("First", "first", "draft", "Content", int64(0)).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery(readBack).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"updated_at", "created_at"}).AddRow(time.Now(), time.Now()))
+		mock.ExpectExec(query).WithArgs("Second", "second", "draft", "Content", int64(0)).
+			WillReturnError(&mysqlDriver.MySQLError{Number: 1062, Message: "Duplicate entry"})
+		mock.ExpectRollback()
+
+		a := articleMysqlRepo.NewArticleRepository(db)
+		err = a.StoreBatch(context.TODO(), newBatch())
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestStoreArticleDuplicateTitle(t *testing.T) {
+	now := time.Now()
+	ar := &domain.Article{
+		Title:     "Judul",
+		Slug:      "judul",
+		Status:    domain.StatusDraft,
+		Content:   "Content",
+		CreatedAt: now,
+		UpdatedAt: now,
+		Author:    domain.Author{ID: 1},
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT  article SET title=\\? , slug=\\? , status=\\? , content=\\? , author_id=\\?, updated_at=\\? , created_at=\\?"
+	mock.ExpectBegin()
+	mock.ExpectExec(query).WithArgs(ar.Title, ar.Slug, string(ar.Status), ar.Content, ar.Author.ID, ar.UpdatedAt, ar.CreatedAt).
+		WillReturnError(&mysqlDriver.MySQLError{Number: 1062, Message: "Duplicate entry 'Judul' for key 'title'"})
+	mock.ExpectRollback()
+
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	err = a.Store(context.TODO(), ar)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assert.Equal(t, int64(0), ar.ID)
 }
 
 func TestGetArticleByTitle(t *testing.T) {
@@ -102,10 +729,10 @@ func TestGetArticleByTitle(t *testing.T) {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
-		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now())
+	rows := sqlmock.NewRows([]string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}).
+		AddRow(1, "title 1", "title-1", "published", "Content 1", 1, time.Now(), time.Now(), nil, 0, nil)
 
-	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article WHERE title = \\?"
+	query := "SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, " + tagsSelect + " FROM article WHERE title = \\?"
 
 	mock.ExpectQuery(query).WillReturnRows(rows)
 	a := articleMysqlRepo.NewArticleRepository(db)
@@ -116,16 +743,68 @@ func TestGetArticleByTitle(t *testing.T) {
 	assert.NotNil(t, anArticle)
 }
 
+func TestFindArticlesByTitle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	query := "SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, " + tagsSelect + " FROM article WHERE title = \\? AND deleted_at IS NULL\\s+ORDER BY id"
+	columns := []string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}
+
+	rows := sqlmock.NewRows(columns).
+		AddRow(1, "Same title", "same-title", "published", "Content 1", 1, time.Now(), time.Now(), nil, 0, nil).
+		AddRow(4, "Same title", "same-title-2", "draft", "Content 4", 2, time.Now(), time.Now(), nil, 0, nil)
+	mock.ExpectQuery(query).WithArgs("Same title").WillReturnRows(rows)
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	list, err := a.FindByTitle(context.TODO(), "Same title")
+	assert.NoError(t, err)
+	if assert.Len(t, list, 2) {
+		assert.Equal(t, int64(1), list[0].ID)
+		assert.Equal(t, "same-title-2", list[1].Slug)
+		assert.Equal(t, int64(2), list[1].Author.ID)
+	}
+
+	mock.ExpectQuery(query).WithArgs("missing").WillReturnRows(sqlmock.NewRows(columns))
+	list, err = a.FindByTitle(context.TODO(), "missing")
+	assert.NoError(t, err)
+	assert.NotNil(t, list)
+	assert.Empty(t, list)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticleBySlug(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}).
+		AddRow(1, "title 1", "title-1", "published", "Content 1", 1, time.Now(), time.Now(), nil, 0, nil)
+
+	query := "SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, " + tagsSelect + " FROM article WHERE slug = \\?"
+
+	mock.ExpectQuery(query).WithArgs("title-1").WillReturnRows(rows)
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	anArticle, err := a.GetBySlug(context.TODO(), "title-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "title-1", anArticle.Slug)
+
+	mock.ExpectQuery(query).WithArgs("missing").WillReturnRows(sqlmock.NewRows([]string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}))
+	_, err = a.GetBySlug(context.TODO(), "missing")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
 func TestDeleteArticle(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	query := "DELETE FROM article WHERE id = \\?"
+	query := "UPDATE article SET deleted_at = \\?, updated_at = \\? WHERE id = \\? AND deleted_at IS NULL"
 
 	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(12).WillReturnResult(sqlmock.NewResult(12, 1))
+	prep.ExpectExec().WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 12).WillReturnResult(sqlmock.NewResult(12, 1))
 
 	a := articleMysqlRepo.NewArticleRepository(db)
 
@@ -134,12 +813,93 @@ func TestDeleteArticle(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestIncrementViewCount(t *testing.T) {
+	query := "^UPDATE article SET view_count = view_count \\+ 1 WHERE id = \\? AND deleted_at IS NULL$"
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectExec(query).WithArgs(12).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		a := articleMysqlRepo.NewArticleRepository(db)
+		assert.NoError(t, a.IncrementViewCount(context.TODO(), 12))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectExec(query).WithArgs(12).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		a := articleMysqlRepo.NewArticleRepository(db)
+		assert.ErrorIs(t, a.IncrementViewCount(context.TODO(), 12), domain.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// every view is its own UPDATE, no SELECT is issued that a concurrent view could race with
+	t.Run("concurrent", func(t *testing.T) {
+		const views = 20
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.MatchExpectationsInOrder(false)
+		for i := 0; i < views; i++ {
+			mock.ExpectExec(query).WithArgs(12).WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+
+		a := articleMysqlRepo.NewArticleRepository(db)
+		var wg sync.WaitGroup
+		errs := make(chan error, views)
+		for i := 0; i < views; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- a.IncrementViewCount(context.TODO(), 12)
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdateArticleDuplicateTitle(t *testing.T) {
+	ar := &domain.Article{
+		ID:        12,
+		Title:     "Judul",
+		Content:   "Content",
+		UpdatedAt: time.Now(),
+		Author:    domain.Author{ID: 1},
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "UPDATE article set title=\\?, content=\\?, author_id=\\?, metadata=\\?, updated_at=\\? WHERE ID = \\?"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(query).WithArgs(ar.Title, ar.Content, ar.Author.ID, nil, ar.UpdatedAt, ar.ID).
+		WillReturnError(&mysqlDriver.MySQLError{Number: 1062, Message: "Duplicate entry 'Judul' for key 'title'"})
+	mock.ExpectRollback()
+
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	err = a.Update(context.TODO(), ar)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+}
+
 func TestUpdateArticle(t *testing.T) {
 	now := time.Now()
 	ar := &domain.Article{
 		ID:        12,
 		Title:     "Judul",
 		Content:   "Content",
+		Tags:      []string{"go", "web"},
 		CreatedAt: now,
 		UpdatedAt: now,
 		Author: domain.Author{
@@ -153,13 +913,276 @@ func TestUpdateArticle(t *testing.T) {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	query := "UPDATE article set title=\\?, content=\\?, author_id=\\?, updated_at=\\? WHERE ID = \\?"
+	query := "UPDATE article set title=\\?, content=\\?, author_id=\\?, metadata=\\?, updated_at=\\? WHERE ID = \\?"
 
-	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt, ar.ID).WillReturnResult(sqlmock.NewResult(12, 1))
+	// the tags of the article are replaced in the same transaction
+	mock.ExpectBegin()
+	mock.ExpectExec(query).WithArgs(ar.Title, ar.Content, ar.Author.ID, nil, ar.UpdatedAt, ar.ID).WillReturnResult(sqlmock.NewResult(12, 1))
+	mock.ExpectExec("^DELETE FROM article_tag WHERE article_id = \\?$").WithArgs(ar.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^INSERT article_tag \\(article_id, tag\\) VALUES \\(\\?, \\?\\), \\(\\?, \\?\\)$").
+		WithArgs(ar.ID, "go", ar.ID, "web").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
 
 	a := articleMysqlRepo.NewArticleRepository(db)
 
 	err = a.Update(context.TODO(), ar)
 	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertArticle(t *testing.T) {
+	now := time.Now()
+	ar := &domain.Article{
+		ID:        12,
+		Title:     "Judul",
+		Slug:      "judul",
+		Status:    domain.StatusDraft,
+		Content:   "Content",
+		Tags:      []string{"go"},
+		CreatedAt: now,
+		UpdatedAt: now,
+		Author:    domain.Author{ID: 1},
+	}
+	lockQuery := "^SELECT id FROM article WHERE id = \\? FOR UPDATE$"
+	insertQuery := "^INSERT article \\(id, title, slug, status, content, author_id, updated_at, created_at, metadata\\) " +
+		"VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?\\)$"
+	updateQuery := "^UPDATE article SET title=\\?, content=\\?, author_id=\\?, metadata=\\?, updated_at=\\?, deleted_at=NULL WHERE id = \\?$"
+	expectTags := func(mock sqlmock.Sqlmock) {
+		mock.ExpectExec("^DELETE FROM article_tag WHERE article_id = \\?$").WithArgs(ar.ID).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("^INSERT article_tag \\(article_id, tag\\) VALUES \\(\\?, \\?\\)$").WithArgs(ar.ID, "go").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	t.Run("insert", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).WithArgs(ar.ID).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectExec(insertQuery).WithArgs(ar.ID, ar.Title, ar.Slug, "draft", ar.Content, ar.Author.ID, ar.UpdatedAt, ar.CreatedAt, nil).
+			WillReturnResult(sqlmock.NewResult(12, 1))
+		expectTags(mock)
+		mock.ExpectCommit()
+
+		a := articleMysqlRepo.NewArticleRepository(db)
+		created, err := a.Upsert(context.TODO(), ar)
+		assert.NoError(t, err)
+		assert.True(t, created)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		// the slug, status and created_at of the existing row are kept
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).WithArgs(ar.ID).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(ar.ID))
+		mock.ExpectExec(updateQuery).WithArgs(ar.Title, ar.Content, ar.Author.ID, nil, ar.UpdatedAt, ar.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectTags(mock)
+		mock.ExpectCommit()
+
+		a := articleMysqlRepo.NewArticleRepository(db)
+		created, err := a.Upsert(context.TODO(), ar)
+		assert.NoError(t, err)
+		assert.False(t, created)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("duplicate-slug", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		// another live article holds the slug, it is left untouched
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).WithArgs(ar.ID).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectExec(insertQuery).
+			WillReturnError(&mysqlDriver.MySQLError{Number: 1062, Message: "Duplicate entry 'judul' for key 'uniq_article_live_slug'"})
+		mock.ExpectRollback()
+
+		a := articleMysqlRepo.NewArticleRepository(db)
+		_, err = a.Upsert(context.TODO(), ar)
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFetchArticleAllStatuses(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}).
+		AddRow(1, "title 1", "title-1", "draft", "Content 1", 1, time.Now(), time.Now(), nil, 0, nil)
+
+	query := "SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, " + tagsSelect + " FROM article WHERE \\(created_at, id\\) > \\(\\?, \\?\\) AND deleted_at IS NULL ORDER BY created_at, id LIMIT \\?"
+
+	mock.ExpectQuery(query).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), int64(2)).WillReturnRows(rows)
+	a := articleMysqlRepo.NewArticleRepository(db)
+	list, _, err := a.Fetch(context.TODO(), "", domain.PageNext, 2, domain.ArticleFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, domain.StatusDraft, list[0].Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchArticleTimeRange(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}).
+		AddRow(1, "title 1", "title-1", "published", "Content 1", 1, time.Now(), from.Add(time.Hour), nil, 0, nil)
+
+	query := "SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, " + tagsSelect + " FROM article WHERE \\(created_at, id\\) > \\(\\?, \\?\\) AND status = \\? AND created_at BETWEEN \\? AND \\? AND deleted_at IS NULL ORDER BY created_at, id LIMIT \\?"
+	mock.ExpectQuery(query).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "published", from, to, int64(2)).WillReturnRows(rows)
+
+	a := articleMysqlRepo.NewArticleRepository(db)
+	list, _, err := a.Fetch(context.TODO(), "", domain.PageNext, 2, domain.ArticleFilter{Status: domain.StatusPublished, CreatedFrom: from, CreatedTo: to})
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	countRows := sqlmock.NewRows([]string{"count"}).AddRow(3)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM article WHERE created_at >= \\?").WithArgs(from).WillReturnRows(countRows)
+	total, err := a.Count(context.TODO(), domain.ArticleFilter{CreatedFrom: from})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchArticleByAuthor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	a := articleMysqlRepo.NewArticleRepository(db)
+	columns := []string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := "SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, " + tagsSelect + " FROM article WHERE \\(created_at, id\\) > \\(\\?, \\?\\) AND status = \\? AND author_id = \\? AND deleted_at IS NULL ORDER BY created_at, id LIMIT \\?"
+	filter := domain.ArticleFilter{Status: domain.StatusPublished, AuthorID: 7}
+
+	rows := sqlmock.NewRows(columns).
+		AddRow(1, "title 1", "title-1", "published", "Content 1", 7, created, created, nil, 0, nil).
+		AddRow(3, "title 3", "title-3", "published", "Content 3", 7, created, created.Add(time.Hour), nil, 0, nil)
+	mock.ExpectQuery(query).WithArgs(sqlmock.AnyArg(), int64(0), "published", int64(7), int64(2)).WillReturnRows(rows)
+
+	list, page, err := a.Fetch(context.TODO(), "", domain.PageNext, 2, filter)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.NotEmpty(t, page.Next)
+
+	// the next page keeps the author filter after the keyset condition
+	mock.ExpectQuery(query).WithArgs(created.Add(time.Hour), int64(3), "published", int64(7), int64(2)).
+		WillReturnRows(sqlmock.NewRows(columns))
+	list, page, err = a.Fetch(context.TODO(), page.Next, domain.PageNext, 2, filter)
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+	assert.Empty(t, page)
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM article WHERE author_id = \\? AND deleted_at IS NULL").WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	total, err := a.Count(context.TODO(), domain.ArticleFilter{AuthorID: 7})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchArticleByTags(t *testing.T) {
+	columns := []string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	prefix := "SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, " + tagsSelect + " FROM article WHERE \\(created_at, id\\) > \\(\\?, \\?\\) AND status = \\? AND "
+	suffix := " AND deleted_at IS NULL ORDER BY created_at, id LIMIT \\?"
+
+	tests := []struct {
+		name  string
+		match domain.TagMatch
+		cond  string
+		args  []driver.Value
+	}{
+		{
+			name:  "all",
+			match: domain.TagMatchAll,
+			cond:  "id IN \\(SELECT article_id FROM article_tag WHERE tag IN \\(\\?,\\?\\) GROUP BY article_id HAVING COUNT\\(DISTINCT tag\\) = \\?\\)",
+			args:  []driver.Value{sqlmock.AnyArg(), int64(0), "published", "go", "web", int64(2), int64(10)},
+		},
+		{
+			name:  "any",
+			match: domain.TagMatchAny,
+			cond:  "id IN \\(SELECT article_id FROM article_tag WHERE tag IN \\(\\?,\\?\\)\\)",
+			args:  []driver.Value{sqlmock.AnyArg(), int64(0), "published", "go", "web", int64(10)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			a := articleMysqlRepo.NewArticleRepository(db)
+
+			rows := sqlmock.NewRows(columns).AddRow(1, "title 1", "title-1", "published", "Content 1", 7, created, created, nil, 0, nil)
+			mock.ExpectQuery(prefix + tt.cond + suffix).WithArgs(tt.args...).WillReturnRows(rows)
+
+			filter := domain.ArticleFilter{Status: domain.StatusPublished, Tags: []string{"go", "web"}, TagMatch: tt.match}
+			list, _, err := a.Fetch(context.TODO(), "", domain.PageNext, 10, filter)
+			assert.NoError(t, err)
+			assert.Len(t, list, 1)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetArticleNeighbors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	created := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	ar := domain.Article{ID: 5, CreatedAt: created}
+	columns := []string{"id", "title", "slug", "status", "content", "author_id", "updated_at", "created_at", "metadata", "view_count", "tags"}
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	prevQuery := "SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, " + tagsSelect + " FROM article " +
+		"WHERE \\(created_at < \\? OR \\(created_at = \\? AND id < \\?\\)\\) AND status = \\? AND deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT 1"
+	rows := sqlmock.NewRows(columns).AddRow(4, "title 4", "title-4", "published", "Content 4", 1, created, created.Add(-time.Hour), nil, 0, nil)
+	mock.ExpectQuery(prevQuery).WithArgs(created, created, int64(5), "published").WillReturnRows(rows)
+
+	prev, err := a.GetPrevious(context.TODO(), ar, domain.ArticleFilter{Status: domain.StatusPublished})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), prev.ID)
+
+	nextQuery := "SELECT id,title,slug,status,content, author_id, updated_at, created_at, metadata, view_count, " + tagsSelect + " FROM article " +
+		"WHERE \\(created_at > \\? OR \\(created_at = \\? AND id > \\?\\)\\) AND status = \\? AND deleted_at IS NULL ORDER BY created_at ASC, id ASC LIMIT 1"
+	mock.ExpectQuery(nextQuery).WithArgs(created, created, int64(5), "published").WillReturnRows(sqlmock.NewRows(columns))
+
+	_, err = a.GetNext(context.TODO(), ar, domain.ArticleFilter{Status: domain.StatusPublished})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateArticleStatus(t *testing.T) {
+	now := time.Now()
+	ar := &domain.Article{ID: 12, Status: domain.StatusPublished, UpdatedAt: now}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "UPDATE article set status=\\?, updated_at=\\? WHERE ID = \\? AND status = \\?"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs("published", now, ar.ID, "draft").WillReturnResult(sqlmock.NewResult(12, 1))
+	a := articleMysqlRepo.NewArticleRepository(db)
+	err = a.UpdateStatus(context.TODO(), ar, domain.StatusDraft)
+	assert.NoError(t, err)
+
+	prep = mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs("published", now, ar.ID, "draft").WillReturnResult(sqlmock.NewResult(12, 0))
+	err = a.UpdateStatus(context.TODO(), ar, domain.StatusDraft)
+	assert.ErrorIs(t, err, domain.ErrConflict)
 }
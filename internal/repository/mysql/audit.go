@@ -0,0 +1,61 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/tracing"
+)
+
+// AuditRepository appends the audit entries to the audit_log table, the rows are never updated or deleted:
+//
+//	CREATE TABLE audit_log (
+//	  id           BIGINT AUTO_INCREMENT PRIMARY KEY,
+//	  occurred_at  DATETIME(6) NOT NULL,
+//	  actor        VARCHAR(255) NOT NULL,
+//	  operation    VARCHAR(16) NOT NULL,
+//	  article_id   BIGINT NOT NULL,
+//	  before_state JSON NULL,
+//	  after_state  JSON NULL,
+//	  KEY idx_audit_log_article (article_id, occurred_at)
+//	);
+type AuditRepository struct {
+	DB *sql.DB
+}
+
+// NewAuditRepository will create an implementation of article.AuditLogger
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{DB: db}
+}
+
+func (m *AuditRepository) Record(ctx context.Context, entry domain.AuditEntry) (err error) {
+	ctx, span := tracing.Start(ctx, "mysql.AuditRepository.Record")
+	defer func() { tracing.End(span, err) }()
+
+	before, err := auditState(entry.Before)
+	if err != nil {
+		return
+	}
+	after, err := auditState(entry.After)
+	if err != nil {
+		return
+	}
+
+	query := `INSERT audit_log SET occurred_at=? , actor=? , operation=? , article_id=? , before_state=? , after_state=?`
+	_, err = m.DB.ExecContext(ctx, query, entry.OccurredAt, entry.Actor, entry.Operation, entry.ArticleID, before, after)
+	return
+}
+
+// auditState is the JSON column value of an article state, NULL when there is none
+func auditState(a *domain.Article) (sql.NullString, error) {
+	if a == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(a)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
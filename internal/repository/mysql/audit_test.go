@@ -0,0 +1,57 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	repository "github.com/bxcodec/go-clean-arch/internal/repository/mysql"
+)
+
+// articleJSON matches a JSON article state by title
+type articleJSON string
+
+func (title articleJSON) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	var a domain.Article
+	return json.Unmarshal([]byte(s), &a) == nil && a.Title == string(title)
+}
+
+func TestRecordAudit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	now := time.Now()
+	before := &domain.Article{ID: 12, Title: "before"}
+	after := &domain.Article{ID: 12, Title: "after"}
+
+	query := "INSERT audit_log SET occurred_at=\\? , actor=\\? , operation=\\? , article_id=\\? , before_state=\\? , after_state=\\?"
+	mock.ExpectExec(query).
+		WithArgs(now, "alice", domain.AuditUpdate, int64(12), articleJSON("before"), articleJSON("after")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	// a create has no before state, it is stored as NULL
+	mock.ExpectExec(query).
+		WithArgs(now, "alice", domain.AuditCreate, int64(12), nil, articleJSON("after")).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+
+	a := repository.NewAuditRepository(db)
+	err = a.Record(context.TODO(), domain.AuditEntry{
+		OccurredAt: now, Actor: "alice", Operation: domain.AuditUpdate, ArticleID: 12, Before: before, After: after,
+	})
+	assert.NoError(t, err)
+	err = a.Record(context.TODO(), domain.AuditEntry{
+		OccurredAt: now, Actor: "alice", Operation: domain.AuditCreate, ArticleID: 12, After: after,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -3,8 +3,10 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/tracing"
 )
 
 type AuthorRepository struct {
@@ -32,10 +34,50 @@ func (m *AuthorRepository) getOne(ctx context.Context, query string, args ...int
 		&res.CreatedAt,
 		&res.UpdatedAt,
 	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Author{}, domain.ErrNotFound
+	}
 	return
 }
 
-func (m *AuthorRepository) GetByID(ctx context.Context, id int64) (domain.Author, error) {
+func (m *AuthorRepository) GetByID(ctx context.Context, id int64) (res domain.Author, err error) {
+	ctx, span := tracing.Start(ctx, "mysql.AuthorRepository.GetByID")
+	defer func() { tracing.End(span, err) }()
+
 	query := `SELECT id, name, created_at, updated_at FROM author WHERE id=?`
 	return m.getOne(ctx, query, id)
 }
+
+func (m *AuthorRepository) ListAuthorsWithCounts(ctx context.Context) (res []domain.AuthorStat, err error) {
+	ctx, span := tracing.Start(ctx, "mysql.AuthorRepository.ListAuthorsWithCounts")
+	defer func() { tracing.End(span, err) }()
+
+	// LEFT JOIN keeps the authors without articles, the deleted articles are excluded in the join condition
+	// so they are not counted without dropping their author
+	query := `SELECT author.id, author.name, author.created_at, author.updated_at, COUNT(article.id)
+		FROM author LEFT JOIN article ON article.author_id = author.id AND article.deleted_at IS NULL
+		GROUP BY author.id, author.name, author.created_at, author.updated_at
+		ORDER BY author.id`
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res = make([]domain.AuthorStat, 0)
+	for rows.Next() {
+		var stat domain.AuthorStat
+		err = rows.Scan(
+			&stat.Author.ID,
+			&stat.Author.Name,
+			&stat.Author.CreatedAt,
+			&stat.Author.UpdatedAt,
+			&stat.ArticleCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, stat)
+	}
+	return res, rows.Err()
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
 
+	"github.com/bxcodec/go-clean-arch/domain"
 	repository "github.com/bxcodec/go-clean-arch/internal/repository/mysql"
 )
 
@@ -32,3 +33,49 @@ func TestGetAuthorByID(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, anArticle)
 }
+
+func TestGetAuthorByIDNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "name", "updated_at", "created_at"})
+
+	query := "SELECT id, name, created_at, updated_at FROM author WHERE id=\\?"
+
+	prep := mock.ExpectPrepare(query)
+	userID := int64(1)
+	prep.ExpectQuery().WithArgs(userID).WillReturnRows(rows)
+
+	a := repository.NewAuthorRepository(db)
+
+	_, err = a.GetByID(context.TODO(), userID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestListAuthorsWithCounts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at", "count"}).
+		AddRow(1, "Iman Tumorang", "2024-01-01", "2024-01-01", 3).
+		AddRow(2, "Without Articles", "2024-01-02", "2024-01-02", 0)
+
+	query := "SELECT author.id, author.name, author.created_at, author.updated_at, COUNT\\(article.id\\)\\s+" +
+		"FROM author LEFT JOIN article ON article.author_id = author.id AND article.deleted_at IS NULL\\s+" +
+		"GROUP BY author.id, author.name, author.created_at, author.updated_at\\s+ORDER BY author.id"
+	mock.ExpectQuery(query).WillReturnRows(rows)
+
+	a := repository.NewAuthorRepository(db)
+
+	list, err := a.ListAuthorsWithCounts(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.AuthorStat{
+		{Author: domain.Author{ID: 1, Name: "Iman Tumorang", CreatedAt: "2024-01-01", UpdatedAt: "2024-01-01"}, ArticleCount: 3},
+		{Author: domain.Author{ID: 2, Name: "Without Articles", CreatedAt: "2024-01-02", UpdatedAt: "2024-01-02"}, ArticleCount: 0},
+	}, list)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
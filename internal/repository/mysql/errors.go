@@ -0,0 +1,21 @@
+package mysql
+
+import (
+	"errors"
+
+	mysqlDriver "github.com/go-sql-driver/mysql"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// errDuplicateEntry is the MySQL error number raised when a unique index is violated
+const errDuplicateEntry = 1062
+
+// translateError maps driver errors that have a domain meaning to the domain errors
+func translateError(err error) error {
+	var mysqlErr *mysqlDriver.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errDuplicateEntry {
+		return domain.ErrConflict
+	}
+	return err
+}
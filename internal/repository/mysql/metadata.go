@@ -0,0 +1,45 @@
+package mysql
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// jsonMetadata stores domain.Article.Metadata in the nullable JSON column metadata, an empty map is
+// written as NULL and NULL is read back as a nil map
+type jsonMetadata map[string]any
+
+// Value implements driver.Valuer
+func (m jsonMetadata) Value() (driver.Value, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(map[string]any(m))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (m *jsonMetadata) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported metadata column type %T", src)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("decode metadata: %w", err)
+	}
+	*m = decoded
+	return nil
+}
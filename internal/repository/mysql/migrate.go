@@ -0,0 +1,93 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+)
+
+// migrations holds the schema changes, applied in the order of their file names (0001_..., 0002_...).
+// A file is never edited once released, a schema change is a new file.
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrate applies the migrations not recorded in the schema_migrations table yet and returns their versions,
+// the version of a migration is its file name without the extension
+func Migrate(ctx context.Context, db *sql.DB) (applied []string, err error) {
+	_, err = db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+  version    VARCHAR(255) NOT NULL PRIMARY KEY,
+  applied_at DATETIME(6) NOT NULL
+)`)
+	if err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	done, err := appliedVersions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		version := strings.TrimSuffix(strings.TrimPrefix(file, "migrations/"), ".sql")
+		if done[version] {
+			continue
+		}
+		content, err := migrations.ReadFile(file)
+		if err != nil {
+			return applied, err
+		}
+		// MySQL commits DDL implicitly, so a failed migration is not rolled back and must be fixed by hand
+		if err := execStatements(ctx, db, string(content)); err != nil {
+			return applied, fmt.Errorf("migration %s: %w", version, err)
+		}
+		_, err = db.ExecContext(ctx, `INSERT schema_migrations SET version=? , applied_at=?`, version, time.Now().UTC())
+		if err != nil {
+			return applied, fmt.Errorf("record migration %s: %w", version, err)
+		}
+		applied = append(applied, version)
+	}
+	return applied, nil
+}
+
+func appliedVersions(ctx context.Context, db *sql.DB) (map[string]bool, error) {
+	rows, err := db.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	done := map[string]bool{}
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		done[version] = true
+	}
+	return done, rows.Err()
+}
+
+// execStatements runs the ;-terminated statements of script one by one, the DSN does not enable
+// multiStatements
+func execStatements(ctx context.Context, db *sql.DB, script string) error {
+	for _, stmt := range strings.Split(script, ";") {
+		if stmt = strings.TrimSpace(stmt); stmt == "" {
+			continue
+		}
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS author (
+  id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+  name       VARCHAR(200) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS article (
+  id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+  title      VARCHAR(255) NOT NULL,
+  slug       VARCHAR(255) NOT NULL,
+  status     VARCHAR(16) NOT NULL DEFAULT 'draft',
+  content    LONGTEXT NOT NULL,
+  author_id  BIGINT NOT NULL DEFAULT 0,
+  metadata   JSON NULL,
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  deleted_at DATETIME NULL,
+  UNIQUE KEY uniq_article_title (title),
+  UNIQUE KEY uniq_article_slug (slug),
+  KEY idx_article_created (created_at, id),
+  KEY idx_article_updated (updated_at, id),
+  KEY idx_article_author (author_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
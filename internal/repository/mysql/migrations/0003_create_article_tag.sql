@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS article_tag (
+  article_id BIGINT NOT NULL,
+  tag        VARCHAR(64) NOT NULL,
+  PRIMARY KEY (article_id, tag),
+  KEY idx_article_tag_tag (tag)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
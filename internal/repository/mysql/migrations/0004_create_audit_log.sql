@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+  id           BIGINT AUTO_INCREMENT PRIMARY KEY,
+  occurred_at  DATETIME(6) NOT NULL,
+  actor        VARCHAR(255) NOT NULL,
+  operation    VARCHAR(16) NOT NULL,
+  article_id   BIGINT NOT NULL,
+  before_state JSON NULL,
+  after_state  JSON NULL,
+  KEY idx_audit_log_article (article_id, occurred_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
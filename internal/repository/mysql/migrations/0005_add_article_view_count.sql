@@ -0,0 +1 @@
+ALTER TABLE article ADD COLUMN view_count INT NOT NULL DEFAULT 0 AFTER metadata;
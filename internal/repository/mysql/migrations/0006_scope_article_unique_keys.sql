@@ -0,0 +1,10 @@
+-- Soft-deleted rows keep their title and slug, so the unique keys of 0002 blocked re-creating a deleted
+-- article. Titles are only unique when content.unique_titles is on, which the service checks. Slugs stay
+-- unique among the live rows through a generated column that is NULL once the row is deleted.
+ALTER TABLE article
+  DROP INDEX uniq_article_title,
+  DROP INDEX uniq_article_slug,
+  ADD COLUMN live_slug VARCHAR(255) AS (IF(deleted_at IS NULL, slug, NULL)) VIRTUAL,
+  ADD UNIQUE KEY uniq_article_live_slug (live_slug),
+  ADD KEY idx_article_title (title),
+  ADD KEY idx_article_slug (slug);
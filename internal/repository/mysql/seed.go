@@ -0,0 +1,79 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	log "github.com/lingdongomg/g-lib/logger"
+)
+
+// Seed inserts the authors, then the articles with each one assigned to the next author in turn, all in
+// one transaction. A database that already has articles is left untouched and Seed reports false, unless
+// force is set: then every tag, article and author is deleted first. The ids of the inserted rows are set
+// on authors and articles.
+func Seed(ctx context.Context, db *sql.DB, authors []domain.Author, articles []*domain.Article, force bool) (seeded bool, err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil || !seeded {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Error("Failed to rollback seed:", rbErr)
+			}
+		}
+	}()
+
+	if force {
+		// DELETE instead of TRUNCATE, which would commit the transaction implicitly
+		for _, table := range []string{"article_tag", "article", "author"} {
+			if _, err = tx.ExecContext(ctx, "DELETE FROM "+table); err != nil {
+				return false, fmt.Errorf("clear %s: %w", table, err)
+			}
+		}
+	} else {
+		var count int64
+		if err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM article`).Scan(&count); err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return false, nil
+		}
+	}
+
+	for i := range authors {
+		// the timestamps are left to the column defaults
+		res, err := tx.ExecContext(ctx, `INSERT author SET name=?`, authors[i].Name)
+		if err != nil {
+			return false, fmt.Errorf("insert author: %w", err)
+		}
+		if authors[i].ID, err = res.LastInsertId(); err != nil {
+			return false, err
+		}
+	}
+
+	stmt, err := tx.PrepareContext(ctx, insertQuery(false))
+	if err != nil {
+		return false, err
+	}
+	defer stmt.Close()
+	for i, a := range articles {
+		if len(authors) > 0 {
+			a.Author = authors[i%len(authors)]
+		}
+		res, err := stmt.ExecContext(ctx, insertArgs(a, false)...)
+		if err != nil {
+			return false, fmt.Errorf("insert article: %w", translateError(err))
+		}
+		if a.ID, err = res.LastInsertId(); err != nil {
+			return false, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,58 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// tagsColumn selects the tags of the article from article_tag as one comma separated string in tag order.
+// The service keeps tags free of commas and few enough to fit the default group_concat_max_len.
+const tagsColumn = `(SELECT GROUP_CONCAT(tag ORDER BY tag SEPARATOR ',') FROM article_tag WHERE article_tag.article_id = article.id) AS tags`
+
+// tagList reads domain.Article.Tags from tagsColumn, NULL (no tags) is read back as a nil slice
+type tagList []string
+
+// Scan implements sql.Scanner
+func (t *tagList) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*t = nil
+	case []byte:
+		*t = strings.Split(string(v), ",")
+	case string:
+		*t = strings.Split(v, ",")
+	default:
+		return fmt.Errorf("unsupported tags column type %T", src)
+	}
+	return nil
+}
+
+// execer is the part of *sql.DB and *sql.Tx that writes the article rows
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// insertTags adds a row to article_tag for every tag of the article
+func insertTags(ctx context.Context, exec execer, id int64, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	args := make([]interface{}, 0, 2*len(tags))
+	for _, tag := range tags {
+		args = append(args, id, tag)
+	}
+	query := `INSERT article_tag (article_id, tag) VALUES (?, ?)` + strings.Repeat(`, (?, ?)`, len(tags)-1)
+	_, err := exec.ExecContext(ctx, query, args...)
+	return err
+}
+
+// replaceTags makes tags the only tags of the article
+func replaceTags(ctx context.Context, exec execer, id int64, tags []string) error {
+	if _, err := exec.ExecContext(ctx, `DELETE FROM article_tag WHERE article_id = ?`, id); err != nil {
+		return err
+	}
+	return insertTags(ctx, exec, id, tags)
+}
@@ -0,0 +1,219 @@
+package slowquery
+
+import (
+	"context"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/article"
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// ArticleRepository decorates an article.ArticleRepository with a Timer
+type ArticleRepository struct {
+	repo  article.ArticleRepository
+	timer *Timer
+}
+
+// NewArticleRepository will wrap repo so its calls are timed by t
+func NewArticleRepository(repo article.ArticleRepository, t *Timer) *ArticleRepository {
+	return &ArticleRepository{repo: repo, timer: t}
+}
+
+func (r *ArticleRepository) Fetch(ctx context.Context, cursor string, direction domain.PageDirection, num int64, filter domain.ArticleFilter) (res []domain.Article, page domain.PageCursors, err error) {
+	err = r.timer.Observe("ArticleRepository.Fetch", func() error {
+		res, page, err = r.repo.Fetch(ctx, cursor, direction, num, filter)
+		return err
+	})
+	return
+}
+
+func (r *ArticleRepository) FetchRecent(ctx context.Context, num int64, filter domain.ArticleFilter) (res []domain.Article, err error) {
+	err = r.timer.Observe("ArticleRepository.FetchRecent", func() error {
+		res, err = r.repo.FetchRecent(ctx, num, filter)
+		return err
+	})
+	return
+}
+
+// FetchAll times the stream from its start until it reports its end
+func (r *ArticleRepository) FetchAll(ctx context.Context) (<-chan domain.Article, <-chan error) {
+	start := r.timer.now()
+	articles, innerErrs := r.repo.FetchAll(ctx)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		err := <-innerErrs
+		r.timer.record("ArticleRepository.FetchAll", r.timer.now().Sub(start))
+		if err != nil {
+			errs <- err
+		}
+	}()
+	return articles, errs
+}
+
+func (r *ArticleRepository) FetchChanges(ctx context.Context, since time.Time, cursor string, num int64) (res []domain.Article, nextCursor string, err error) {
+	err = r.timer.Observe("ArticleRepository.FetchChanges", func() error {
+		res, nextCursor, err = r.repo.FetchChanges(ctx, since, cursor, num)
+		return err
+	})
+	return
+}
+
+func (r *ArticleRepository) Count(ctx context.Context, filter domain.ArticleFilter) (total int64, err error) {
+	err = r.timer.Observe("ArticleRepository.Count", func() error {
+		total, err = r.repo.Count(ctx, filter)
+		return err
+	})
+	return
+}
+
+func (r *ArticleRepository) CountByAuthor(ctx context.Context, authorID int64) (total int64, err error) {
+	err = r.timer.Observe("ArticleRepository.CountByAuthor", func() error {
+		total, err = r.repo.CountByAuthor(ctx, authorID)
+		return err
+	})
+	return
+}
+
+func (r *ArticleRepository) Exists(ctx context.Context, id int64) (exists bool, err error) {
+	err = r.timer.Observe("ArticleRepository.Exists", func() error {
+		exists, err = r.repo.Exists(ctx, id)
+		return err
+	})
+	return
+}
+
+func (r *ArticleRepository) GetByID(ctx context.Context, id int64) (res domain.Article, err error) {
+	err = r.timer.Observe("ArticleRepository.GetByID", func() error {
+		res, err = r.repo.GetByID(ctx, id)
+		return err
+	})
+	return
+}
+
+func (r *ArticleRepository) GetByIDIncludingDeleted(ctx context.Context, id int64) (res domain.Article, err error) {
+	err = r.timer.Observe("ArticleRepository.GetByIDIncludingDeleted", func() error {
+		res, err = r.repo.GetByIDIncludingDeleted(ctx, id)
+		return err
+	})
+	return
+}
+
+func (r *ArticleRepository) GetByIDs(ctx context.Context, ids []int64) (res []domain.Article, err error) {
+	err = r.timer.Observe("ArticleRepository.GetByIDs", func() error {
+		res, err = r.repo.GetByIDs(ctx, ids)
+		return err
+	})
+	return
+}
+
+func (r *ArticleRepository) GetByTitle(ctx context.Context, title string) (res domain.Article, err error) {
+	err = r.timer.Observe("ArticleRepository.GetByTitle", func() error {
+		res, err = r.repo.GetByTitle(ctx, title)
+		return err
+	})
+	return
+}
+
+func (r *ArticleRepository) FindByTitle(ctx context.Context, title string) (res []domain.Article, err error) {
+	err = r.timer.Observe("ArticleRepository.FindByTitle", func() error {
+		res, err = r.repo.FindByTitle(ctx, title)
+		return err
+	})
+	return
+}
+
+func (r *ArticleRepository) GetBySlug(ctx context.Context, slug string) (res domain.Article, err error) {
+	err = r.timer.Observe("ArticleRepository.GetBySlug", func() error {
+		res, err = r.repo.GetBySlug(ctx, slug)
+		return err
+	})
+	return
+}
+
+func (r *ArticleRepository) IncrementViewCount(ctx context.Context, id int64) error {
+	return r.timer.Observe("ArticleRepository.IncrementViewCount", func() error {
+		return r.repo.IncrementViewCount(ctx, id)
+	})
+}
+
+func (r *ArticleRepository) Update(ctx context.Context, ar *domain.Article) error {
+	return r.timer.Observe("ArticleRepository.Update", func() error {
+		return r.repo.Update(ctx, ar)
+	})
+}
+
+func (r *ArticleRepository) UpdateStatus(ctx context.Context, ar *domain.Article, from domain.ArticleStatus) error {
+	return r.timer.Observe("ArticleRepository.UpdateStatus", func() error {
+		return r.repo.UpdateStatus(ctx, ar, from)
+	})
+}
+
+func (r *ArticleRepository) Store(ctx context.Context, a *domain.Article) error {
+	return r.timer.Observe("ArticleRepository.Store", func() error {
+		return r.repo.Store(ctx, a)
+	})
+}
+
+func (r *ArticleRepository) GetPrevious(ctx context.Context, ar domain.Article, filter domain.ArticleFilter) (res domain.Article, err error) {
+	err = r.timer.Observe("ArticleRepository.GetPrevious", func() error {
+		res, err = r.repo.GetPrevious(ctx, ar, filter)
+		return err
+	})
+	return
+}
+
+func (r *ArticleRepository) GetNext(ctx context.Context, ar domain.Article, filter domain.ArticleFilter) (res domain.Article, err error) {
+	err = r.timer.Observe("ArticleRepository.GetNext", func() error {
+		res, err = r.repo.GetNext(ctx, ar, filter)
+		return err
+	})
+	return
+}
+
+func (r *ArticleRepository) StoreBatch(ctx context.Context, articles []*domain.Article) error {
+	return r.timer.Observe("ArticleRepository.StoreBatch", func() error {
+		return r.repo.StoreBatch(ctx, articles)
+	})
+}
+
+func (r *ArticleRepository) Upsert(ctx context.Context, ar *domain.Article) (created bool, err error) {
+	err = r.timer.Observe("ArticleRepository.Upsert", func() error {
+		created, err = r.repo.Upsert(ctx, ar)
+		return err
+	})
+	return
+}
+
+func (r *ArticleRepository) Delete(ctx context.Context, id int64) error {
+	return r.timer.Observe("ArticleRepository.Delete", func() error {
+		return r.repo.Delete(ctx, id)
+	})
+}
+
+// AuthorRepository decorates an article.AuthorRepository with a Timer
+type AuthorRepository struct {
+	repo  article.AuthorRepository
+	timer *Timer
+}
+
+// NewAuthorRepository will wrap repo so its calls are timed by t
+func NewAuthorRepository(repo article.AuthorRepository, t *Timer) *AuthorRepository {
+	return &AuthorRepository{repo: repo, timer: t}
+}
+
+func (r *AuthorRepository) GetByID(ctx context.Context, id int64) (res domain.Author, err error) {
+	err = r.timer.Observe("AuthorRepository.GetByID", func() error {
+		res, err = r.repo.GetByID(ctx, id)
+		return err
+	})
+	return
+}
+
+func (r *AuthorRepository) ListAuthorsWithCounts(ctx context.Context) (res []domain.AuthorStat, err error) {
+	err = r.timer.Observe("AuthorRepository.ListAuthorsWithCounts", func() error {
+		res, err = r.repo.ListAuthorsWithCounts(ctx)
+		return err
+	})
+	return
+}
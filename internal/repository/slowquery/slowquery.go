@@ -0,0 +1,39 @@
+// Package slowquery times the repository calls and logs a warning for every call slower than a
+// threshold, so database performance regressions show up in the logs before users report them.
+package slowquery
+
+import (
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/internal/pkg/logger"
+)
+
+// Timer logs the queries exceeding its threshold
+type Timer struct {
+	threshold time.Duration
+	log       *logger.Logger
+	now       func() time.Time
+}
+
+// New will create a Timer warning on l about queries slower than threshold, a nil l logs to logger.Default
+func New(threshold time.Duration, l *logger.Logger) *Timer {
+	if l == nil {
+		l = logger.Default()
+	}
+	return &Timer{threshold: threshold, log: l, now: time.Now}
+}
+
+// Observe runs query and logs a warning with name and the duration when it took longer than the
+// threshold. The error of query is returned as is, a failed query is timed too.
+func (t *Timer) Observe(name string, query func() error) error {
+	start := t.now()
+	err := query()
+	t.record(name, t.now().Sub(start))
+	return err
+}
+
+func (t *Timer) record(name string, elapsed time.Duration) {
+	if elapsed > t.threshold {
+		t.log.Warnf("slow query %s took %s (threshold %s)", name, elapsed, t.threshold)
+	}
+}
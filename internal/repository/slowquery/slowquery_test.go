@@ -0,0 +1,93 @@
+package slowquery
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/bxcodec/go-clean-arch/article/mocks"
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/pkg/logger"
+)
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+// newTestTimer returns a Timer on a fake clock and the warnings it logs
+func newTestTimer(threshold time.Duration) (*Timer, *fakeClock, *[]string) {
+	var warnings []string
+	l := logger.New(logger.DebugLevel, func(level logger.Level, msg string) {
+		if level == logger.WarnLevel {
+			warnings = append(warnings, msg)
+		}
+	})
+	clock := &fakeClock{t: time.Now()}
+	timer := New(threshold, l)
+	timer.now = clock.now
+	return timer, clock, &warnings
+}
+
+func TestTimerObserve(t *testing.T) {
+	t.Run("over-threshold", func(t *testing.T) {
+		timer, clock, warnings := newTestTimer(100 * time.Millisecond)
+
+		err := timer.Observe("ArticleRepository.Fetch", func() error {
+			clock.t = clock.t.Add(250 * time.Millisecond)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		if assert.Len(t, *warnings, 1) {
+			assert.Contains(t, (*warnings)[0], "ArticleRepository.Fetch")
+			assert.Contains(t, (*warnings)[0], "250ms")
+		}
+	})
+
+	t.Run("under-threshold", func(t *testing.T) {
+		timer, clock, warnings := newTestTimer(100 * time.Millisecond)
+
+		err := timer.Observe("ArticleRepository.Fetch", func() error {
+			clock.t = clock.t.Add(50 * time.Millisecond)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Empty(t, *warnings)
+	})
+
+	t.Run("failed-query-is-timed-and-returned", func(t *testing.T) {
+		timer, clock, warnings := newTestTimer(100 * time.Millisecond)
+		errDown := errors.New("dial tcp: connection refused")
+
+		err := timer.Observe("ArticleRepository.Fetch", func() error {
+			clock.t = clock.t.Add(time.Second)
+			return errDown
+		})
+
+		assert.ErrorIs(t, err, errDown)
+		assert.Len(t, *warnings, 1)
+	})
+}
+
+func TestArticleRepositoryNamesTheQuery(t *testing.T) {
+	timer, clock, warnings := newTestTimer(100 * time.Millisecond)
+	mockRepo := new(mocks.ArticleRepository)
+	mockRepo.On("GetByID", mock.Anything, int64(1)).Run(func(mock.Arguments) {
+		clock.t = clock.t.Add(time.Second)
+	}).Return(domain.Article{ID: 1}, nil).Once()
+	repo := NewArticleRepository(mockRepo, timer)
+
+	res, err := repo.GetByID(context.TODO(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), res.ID)
+	if assert.Len(t, *warnings, 1) {
+		assert.Contains(t, (*warnings)[0], "ArticleRepository.GetByID")
+	}
+	mockRepo.AssertExpectations(t)
+}
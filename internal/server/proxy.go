@@ -0,0 +1,12 @@
+package server
+
+import "github.com/gin-gonic/gin"
+
+// TrustProxies limits which hops may set X-Forwarded-For / X-Real-IP for c.ClientIP().
+// gin trusts every proxy by default, an empty list trusts none so the remote address is used.
+func TrustProxies(r *gin.Engine, proxies []string) error {
+	if len(proxies) == 0 {
+		proxies = nil
+	}
+	return r.SetTrustedProxies(proxies)
+}
@@ -0,0 +1,54 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/internal/server"
+)
+
+func TestTrustProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		proxies  []string
+		expected string
+	}{
+		{name: "trusted-proxy", proxies: []string{"10.0.0.0/8"}, expected: "203.0.113.7"},
+		{name: "untrusted-proxy", proxies: []string{"192.168.0.1"}, expected: "10.0.0.2"},
+		{name: "none-configured", proxies: nil, expected: "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			require.NoError(t, server.TrustProxies(r, tt.proxies))
+
+			var clientIP string
+			r.GET("/test", func(c *gin.Context) {
+				clientIP = c.ClientIP()
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.RemoteAddr = "10.0.0.2:54321"
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, clientIP)
+		})
+	}
+}
+
+func TestTrustProxiesInvalid(t *testing.T) {
+	r := gin.New()
+	assert.Error(t, server.TrustProxies(r, []string{"not-an-ip"}))
+}
@@ -0,0 +1,95 @@
+// Package server runs the HTTP server and drains in-flight requests on shutdown.
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	log "github.com/lingdongomg/g-lib/logger"
+)
+
+const defaultDrainTimeout = 10 * time.Second
+
+// Server wraps http.Server and tracks the requests still being served
+type Server struct {
+	srv          *http.Server
+	drainTimeout time.Duration
+	inFlight     atomic.Int64
+	draining     atomic.Bool
+}
+
+// New will create a Server listening on addr. On shutdown it waits up to drainTimeout
+// for in-flight requests before forcing the remaining connections closed.
+func New(addr string, h http.Handler, drainTimeout time.Duration) *Server {
+	if drainTimeout <= 0 {
+		drainTimeout = defaultDrainTimeout
+	}
+	s := &Server{drainTimeout: drainTimeout}
+	s.srv = &http.Server{
+		Addr:    addr,
+		Handler: s.track(h),
+	}
+	return s
+}
+
+// InFlight returns the number of requests currently being served
+func (s *Server) InFlight() int64 {
+	return s.inFlight.Load()
+}
+
+// Draining reports whether shutdown has begun, see middleware.RejectWhileDraining
+func (s *Server) Draining() bool {
+	return s.draining.Load()
+}
+
+func (s *Server) track(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.inFlight.Add(1)
+		defer s.inFlight.Add(-1)
+		h.ServeHTTP(w, r)
+	})
+}
+
+// Run listens on the configured address and serves until ctx is done, see Serve
+func (s *Server) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.srv.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve serves on ln until ctx is done. New connections are refused straight away,
+// in-flight requests get the drain timeout to finish before being cut off.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	s.draining.Store(true)
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), s.drainTimeout)
+	defer cancel()
+
+	err := s.srv.Shutdown(drainCtx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		log.Warnf("drain timeout %s reached with %d requests still in flight, closing", s.drainTimeout, s.InFlight())
+		if closeErr := s.srv.Close(); closeErr != nil {
+			log.Error("Failed to close server:", closeErr)
+		}
+	}
+	if serveErr := <-errCh; serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+		return serveErr
+	}
+	return err
+}
@@ -0,0 +1,74 @@
+package server_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/internal/server"
+)
+
+// startSlowServer serves a handler that takes delay to answer and returns once a request is in flight
+func startSlowServer(t *testing.T, delay, drain time.Duration) (srv *server.Server, cancel context.CancelFunc, resp <-chan *http.Response, done <-chan error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(delay)
+		w.WriteHeader(http.StatusCreated)
+	})
+	srv = server.New(ln.Addr().String(), h, drain)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	serveDone := make(chan error, 1)
+	go func() { serveDone <- srv.Serve(ctx, ln) }()
+
+	respCh := make(chan *http.Response, 1)
+	go func() {
+		res, err := http.Post("http://"+ln.Addr().String()+"/articles", "application/json", nil)
+		if err != nil {
+			respCh <- nil
+			return
+		}
+		_, _ = io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+		respCh <- res
+	}()
+
+	<-started
+	return srv, cancel, respCh, serveDone
+}
+
+func TestServeDrainsInFlightRequests(t *testing.T) {
+	srv, cancel, resp, done := startSlowServer(t, 200*time.Millisecond, 2*time.Second)
+	assert.Equal(t, int64(1), srv.InFlight())
+	assert.False(t, srv.Draining())
+
+	cancel()
+
+	res := <-resp
+	require.NotNil(t, res)
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.NoError(t, <-done)
+	assert.Equal(t, int64(0), srv.InFlight())
+	assert.True(t, srv.Draining())
+}
+
+func TestServeDrainTimeout(t *testing.T) {
+	_, cancel, resp, done := startSlowServer(t, 2*time.Second, 100*time.Millisecond)
+
+	start := time.Now()
+	cancel()
+
+	assert.ErrorIs(t, <-done, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Nil(t, <-resp)
+}